@@ -13,6 +13,7 @@ import (
 	"github.com/eovipmak/v-insight/backend/internal/database"
 	"github.com/eovipmak/v-insight/backend/internal/domain/service"
 	"github.com/eovipmak/v-insight/shared/repository/postgres"
+	"github.com/eovipmak/v-insight/shared/secrets"
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	swaggerFiles "github.com/swaggo/files"
@@ -120,6 +121,10 @@ func main() {
 	incidentRepo := postgres.NewIncidentRepository(db.DB)
 	statusPageRepo := postgres.NewStatusPageRepository(db.DB)
 	maintenanceWindowRepo := postgres.NewMaintenanceWindowRepository(db.DB)
+	certificateRepo := postgres.NewCertificateRepository(db.DB)
+	clientCertificateRepo := postgres.NewClientCertificateRepository(db.DB)
+	contentBaselineRepo := postgres.NewContentBaselineRepository(db.DB)
+	checkEvidenceRepo := postgres.NewCheckEvidenceRepository(db.DB)
 
 	// Initialize services
 	authService := service.NewAuthService(userRepo, cfg.JWT.Secret)
@@ -127,10 +132,16 @@ func main() {
 	metricsService := service.NewMetricsService(db.DB)
 	statusPageService := service.NewStatusPageService(db, statusPageRepo, monitorRepo)
 
+	// Monitor credentials (database DSNs) are encrypted with a key shared with the worker
+	secretsCipher, err := secrets.NewCipher(cfg.Security.EncryptionKey)
+	if err != nil {
+		internal.Log.Fatal("Failed to initialize secrets cipher", zap.Error(err))
+	}
+
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService, userRepo)
 	adminHandler := handlers.NewAdminHandler(userRepo, monitorRepo, alertRuleRepo, alertChannelRepo)
-	monitorHandler := handlers.NewMonitorHandler(monitorRepo, alertRuleRepo, alertChannelRepo, monitorService)
+	monitorHandler := handlers.NewMonitorHandler(monitorRepo, alertRuleRepo, alertChannelRepo, clientCertificateRepo, monitorService, secretsCipher)
 	metricsHandler := handlers.NewMetricsHandler(metricsService, monitorRepo)
 	alertRuleHandler := handlers.NewAlertRuleHandler(alertRuleRepo, alertChannelRepo, monitorRepo)
 	alertChannelHandler := handlers.NewAlertChannelHandler(alertChannelRepo)
@@ -139,7 +150,12 @@ func main() {
 	streamHandler := handlers.NewStreamHandler()
 	statusPageHandler := handlers.NewStatusPageHandler(statusPageService)
 	publicStatusPageHandler := handlers.NewPublicStatusPageHandler(statusPageService)
+	pushHandler := handlers.NewPushHandler(monitorRepo, streamHandler)
 	maintenanceWindowHandler := handlers.NewMaintenanceWindowHandler(maintenanceWindowRepo)
+	certificateHandler := handlers.NewCertificateHandler(certificateRepo)
+	clientCertificateHandler := handlers.NewClientCertificateHandler(clientCertificateRepo, secretsCipher)
+	contentHandler := handlers.NewContentHandler(monitorRepo, contentBaselineRepo)
+	checkHandler := handlers.NewCheckHandler(monitorRepo, checkEvidenceRepo)
 
 	// Initialize middleware
 	authMiddleware := middleware.NewAuthMiddleware(authService)
@@ -245,6 +261,17 @@ func main() {
 		public.GET("/status/:slug", publicStatusPageHandler.GetPublicStatusPage)
 	}
 
+	// Push monitor heartbeats (no auth - the token in the URL identifies the monitor)
+	push := router.Group("/api/push")
+	push.Use(middleware.RateLimiter(middleware.RateLimiterConfig{
+		PerIP:   cfg.RateLimit.PerIP,
+		PerUser: cfg.RateLimit.PerUser,
+	}))
+	{
+		push.GET("/:token", pushHandler.Push)
+		push.POST("/:token", pushHandler.Push)
+	}
+
 	// Protected routes requiring authentication
 	protected := api.Group("/")
 	protected.Use(authMiddleware.AuthRequired())
@@ -259,9 +286,12 @@ func main() {
 		protected.PUT("/monitors/:id", monitorHandler.Update)
 		protected.DELETE("/monitors/:id", monitorHandler.Delete)
 		protected.GET("/monitors/:id/checks", monitorHandler.GetChecks)
+		protected.GET("/monitors/:id/checks/:check_id", checkHandler.Get)
 		protected.GET("/monitors/:id/ssl-status", monitorHandler.GetSSLStatus)
 		protected.GET("/monitors/:id/stats", monitorHandler.GetStats)
 		protected.GET("/monitors/:id/metrics", metricsHandler.GetMonitorMetrics)
+		protected.GET("/monitors/:id/content", contentHandler.Get)
+		protected.POST("/monitors/:id/content/accept", contentHandler.Accept)
 
 		// Alert rule endpoints
 		protected.POST("/alert-rules", alertRuleHandler.Create)
@@ -305,6 +335,17 @@ func main() {
 		protected.PUT("/maintenance-windows/:id", maintenanceWindowHandler.Update)
 		protected.DELETE("/maintenance-windows/:id", maintenanceWindowHandler.Delete)
 
+		// Certificate inventory endpoints
+		protected.GET("/certificates", certificateHandler.List)
+		protected.POST("/certificate-targets", certificateHandler.CreateTarget)
+		protected.GET("/certificate-targets", certificateHandler.ListTargets)
+		protected.DELETE("/certificate-targets/:id", certificateHandler.DeleteTarget)
+
+		// Client certificate (mTLS) endpoints
+		protected.POST("/client-certificates", clientCertificateHandler.Create)
+		protected.GET("/client-certificates", clientCertificateHandler.List)
+		protected.DELETE("/client-certificates/:id", clientCertificateHandler.Delete)
+
 		// SSE Stream endpoint
 		protected.GET("/stream/events", streamHandler.HandleSSE)
 	}
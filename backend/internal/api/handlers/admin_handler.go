@@ -267,6 +267,9 @@ func (h *AdminHandler) ListMonitors(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get all monitors"})
 		return
 	}
+	for _, monitor := range monitors {
+		monitor.Config = monitor.Config.Masked()
+	}
 	c.JSON(http.StatusOK, monitors)
 }
 
@@ -402,5 +405,6 @@ func (h *AdminHandler) GetMonitor(c *gin.Context) {
 		return
 	}
 
+	monitor.Config = monitor.Config.Masked()
 	c.JSON(http.StatusOK, monitor)
 }
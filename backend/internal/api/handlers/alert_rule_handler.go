@@ -31,7 +31,7 @@ func NewAlertRuleHandler(alertRuleRepo repository.AlertRuleRepository, alertChan
 type CreateAlertRuleRequest struct {
 	MonitorID      *string  `json:"monitor_id"`
 	Name           string   `json:"name" binding:"required"`
-	TriggerType    string   `json:"trigger_type" binding:"required,oneof=down ssl_expiry slow_response"`
+	TriggerType    string   `json:"trigger_type" binding:"required,oneof=down ssl_expiry slow_response packet_loss ssl_invalid tls_weak ssl_changed domain_expiry content_changed"`
 	ThresholdValue int      `json:"threshold_value" binding:"required,min=0"`
 	Enabled        *bool    `json:"enabled"`
 	ChannelIDs     []string `json:"channel_ids"`
@@ -41,7 +41,7 @@ type CreateAlertRuleRequest struct {
 type UpdateAlertRuleRequest struct {
 	MonitorID      *string  `json:"monitor_id"`
 	Name           string   `json:"name" binding:"omitempty"`
-	TriggerType    string   `json:"trigger_type" binding:"omitempty,oneof=down ssl_expiry slow_response"`
+	TriggerType    string   `json:"trigger_type" binding:"omitempty,oneof=down ssl_expiry slow_response packet_loss ssl_invalid tls_weak ssl_changed domain_expiry content_changed"`
 	ThresholdValue *int     `json:"threshold_value" binding:"omitempty,min=0"`
 	Enabled        *bool    `json:"enabled"`
 	ChannelIDs     []string `json:"channel_ids"`
@@ -68,6 +68,12 @@ func (h *AlertRuleHandler) Create(c *gin.Context) {
 		return
 	}
 
+	// Packet loss thresholds are percentages
+	if req.TriggerType == "packet_loss" && req.ThresholdValue > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "packet loss threshold must be between 0 and 100"})
+		return
+	}
+
 	// Get user ID from context (set by middleware)
 	userIDValue, exists := c.Get("user_id")
 	if !exists {
@@ -112,9 +118,33 @@ func (h *AlertRuleHandler) Create(c *gin.Context) {
 			return
 		}
 		
-		// Validate SSL expiry rules cannot be created for TCP monitors
-		if req.TriggerType == "ssl_expiry" && monitor.Type == "tcp" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "SSL expiry rules cannot be created for TCP monitors"})
+		// Validate SSL expiry rules cannot be created for TCP monitors, unless they present a client certificate
+		if req.TriggerType == "ssl_expiry" && monitor.Type == "tcp" && monitor.ClientCertificateID == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "SSL expiry rules cannot be created for TCP monitors without a client certificate"})
+			return
+		}
+
+		// Validate packet loss rules are only created for ping monitors
+		if req.TriggerType == "packet_loss" && monitor.Type != "ping" && monitor.Type != "icmp" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "packet loss rules can only be created for ping monitors"})
+			return
+		}
+
+		// Validate domain expiry rules are only created for domain monitors
+		if req.TriggerType == "domain_expiry" && monitor.Type != "domain" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "domain expiry rules can only be created for domain monitors"})
+			return
+		}
+
+		// Certificate posture rules need the SSL check of an HTTPS monitor
+		if isTLSPostureTrigger(req.TriggerType) && monitor.Type != "http" && monitor.Type != "transaction" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "SSL certificate rules can only be created for HTTP monitors"})
+			return
+		}
+
+		// Content change rules need a monitor with change detection
+		if req.TriggerType == "content_changed" && monitor.Config.Content == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "content change rules can only be created for HTTP monitors with content change detection"})
 			return
 		}
 		
@@ -330,13 +360,29 @@ func (h *AlertRuleHandler) Update(c *gin.Context) {
 				return
 			}
 			
-			// Validate SSL expiry rules cannot be created for TCP monitors
+			// Validate SSL expiry rules cannot be created for TCP monitors, unless they present a client certificate
 			triggerType := req.TriggerType
 			if triggerType == "" {
 				triggerType = rule.TriggerType
 			}
-			if triggerType == "ssl_expiry" && monitor.Type == "tcp" {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "SSL expiry rules cannot be created for TCP monitors"})
+			if triggerType == "ssl_expiry" && monitor.Type == "tcp" && monitor.ClientCertificateID == nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "SSL expiry rules cannot be created for TCP monitors without a client certificate"})
+				return
+			}
+			if triggerType == "packet_loss" && monitor.Type != "ping" && monitor.Type != "icmp" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "packet loss rules can only be created for ping monitors"})
+				return
+			}
+			if triggerType == "domain_expiry" && monitor.Type != "domain" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "domain expiry rules can only be created for domain monitors"})
+				return
+			}
+			if isTLSPostureTrigger(triggerType) && monitor.Type != "http" && monitor.Type != "transaction" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "SSL certificate rules can only be created for HTTP monitors"})
+				return
+			}
+			if triggerType == "content_changed" && monitor.Config.Content == nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "content change rules can only be created for HTTP monitors with content change detection"})
 				return
 			}
 			
@@ -356,6 +402,12 @@ func (h *AlertRuleHandler) Update(c *gin.Context) {
 		rule.Enabled = *req.Enabled
 	}
 
+	// Packet loss thresholds are percentages
+	if rule.TriggerType == "packet_loss" && rule.ThresholdValue > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "packet loss threshold must be between 0 and 100"})
+		return
+	}
+
 	if err := h.alertRuleRepo.Update(rule); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update alert rule"})
 		return
@@ -520,7 +572,7 @@ func (h *AlertRuleHandler) validateRuleConfiguration(rule *entities.AlertRuleWit
 
 	// Validate trigger-specific logic
 	switch rule.TriggerType {
-	case "ssl_expiry":
+	case "ssl_expiry", "ssl_invalid", "tls_weak", "ssl_changed":
 		if rule.MonitorID != nil {
 			// Check if monitor has SSL checking enabled
 			monitor, err := h.monitorRepo.GetByID(*rule.MonitorID)
@@ -528,7 +580,19 @@ func (h *AlertRuleHandler) validateRuleConfiguration(rule *entities.AlertRuleWit
 				issues = append(issues, "monitor does not have SSL checking enabled")
 			}
 		}
+	case "content_changed":
+		if rule.MonitorID != nil {
+			monitor, err := h.monitorRepo.GetByID(*rule.MonitorID)
+			if err == nil && monitor.Config.Content == nil {
+				issues = append(issues, "monitor does not have content change detection enabled")
+			}
+		}
 	}
 
 	return issues
 }
+
+// isTLSPostureTrigger reports whether the trigger evaluates the certificate chain recorded by SSL checks
+func isTLSPostureTrigger(triggerType string) bool {
+	return triggerType == "ssl_invalid" || triggerType == "tls_weak" || triggerType == "ssl_changed"
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/eovipmak/v-insight/backend/internal/utils"
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+	"github.com/eovipmak/v-insight/shared/domain/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// certificateStartTLSProtocols are the plaintext protocols a certificate target can be upgraded from
+var certificateStartTLSProtocols = map[string]bool{"": true, "smtp": true, "postgres": true}
+
+type CertificateHandler struct {
+	repo repository.CertificateRepository
+}
+
+func NewCertificateHandler(repo repository.CertificateRepository) *CertificateHandler {
+	return &CertificateHandler{repo: repo}
+}
+
+type CreateCertificateTargetRequest struct {
+	Name       string `json:"name" binding:"required"`
+	Host       string `json:"host" binding:"required"`
+	Port       int    `json:"port" binding:"required,min=1,max=65535"`
+	ServerName string `json:"server_name"`
+	StartTLS   string `json:"starttls"`
+}
+
+// List returns the certificate inventory of the user, soonest expiry first
+func (h *CertificateHandler) List(c *gin.Context) {
+	userIDValue, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user context not found"})
+		return
+	}
+	userID := userIDValue.(int)
+
+	certificates, err := h.repo.GetByUserID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve certificates"})
+		return
+	}
+
+	if certificates == nil {
+		certificates = []*entities.Certificate{}
+	}
+
+	c.JSON(http.StatusOK, certificates)
+}
+
+func (h *CertificateHandler) CreateTarget(c *gin.Context) {
+	var req CreateCertificateTargetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDValue, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user context not found"})
+		return
+	}
+	userID := userIDValue.(int)
+
+	sanitizedName, valid := utils.SanitizeAndValidate(req.Name, 1, 255)
+	if !valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name must be between 1 and 255 characters"})
+		return
+	}
+
+	host := strings.TrimSpace(req.Host)
+	if !isValidCertificateHost(host) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "host must be a hostname or IP address without scheme or port"})
+		return
+	}
+
+	serverName := strings.TrimSpace(req.ServerName)
+	if serverName != "" && !isValidCertificateHost(serverName) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "server_name must be a hostname"})
+		return
+	}
+
+	startTLS := strings.ToLower(strings.TrimSpace(req.StartTLS))
+	if !certificateStartTLSProtocols[startTLS] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "starttls must be one of: smtp, postgres"})
+		return
+	}
+
+	target := &entities.CertificateTarget{
+		UserID:     userID,
+		Name:       sanitizedName,
+		Host:       host,
+		Port:       req.Port,
+		ServerName: serverName,
+		StartTLS:   startTLS,
+	}
+
+	if err := h.repo.CreateTarget(target); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create certificate target"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, target)
+}
+
+func (h *CertificateHandler) ListTargets(c *gin.Context) {
+	userIDValue, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user context not found"})
+		return
+	}
+	userID := userIDValue.(int)
+
+	targets, err := h.repo.GetTargetsByUserID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve certificate targets"})
+		return
+	}
+
+	if targets == nil {
+		targets = []*entities.CertificateTarget{}
+	}
+
+	c.JSON(http.StatusOK, targets)
+}
+
+func (h *CertificateHandler) DeleteTarget(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id required"})
+		return
+	}
+
+	userIDValue, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user context not found"})
+		return
+	}
+	userID := userIDValue.(int)
+
+	target, err := h.repo.GetTargetByID(id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "certificate target not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve certificate target"})
+		return
+	}
+
+	if target.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		return
+	}
+
+	if err := h.repo.DeleteTarget(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete certificate target"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "certificate target deleted successfully"})
+}
+
+// isValidCertificateHost reports whether host is a bare hostname or IP address
+func isValidCertificateHost(host string) bool {
+	if host == "" || len(host) > 255 {
+		return false
+	}
+	if net.ParseIP(host) != nil {
+		return true
+	}
+	return !strings.ContainsAny(host, ":/ @?#")
+}
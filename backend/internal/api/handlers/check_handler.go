@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+	"github.com/eovipmak/v-insight/shared/domain/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CheckHandler serves single monitor checks with the response evidence kept for them
+type CheckHandler struct {
+	monitorRepo  repository.MonitorRepository
+	evidenceRepo repository.CheckEvidenceRepository
+}
+
+// NewCheckHandler creates a new check handler
+func NewCheckHandler(monitorRepo repository.MonitorRepository, evidenceRepo repository.CheckEvidenceRepository) *CheckHandler {
+	return &CheckHandler{
+		monitorRepo:  monitorRepo,
+		evidenceRepo: evidenceRepo,
+	}
+}
+
+// CheckResponse is a monitor check with the response it received
+type CheckResponse struct {
+	entities.MonitorCheck
+	Evidence *entities.CheckEvidence `json:"evidence"` // null when none was kept or it expired
+}
+
+// Get godoc
+// @Summary Get a monitor check with its evidence
+// @Description Returns a single check and, for failed and sampled HTTP checks, the response headers, body snippet, remote IP and TLS connection it received
+// @Tags Monitors
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Monitor ID"
+// @Param check_id path string true "Check ID"
+// @Success 200 {object} CheckResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Monitor or check not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /monitors/{id}/checks/{check_id} [get]
+func (h *CheckHandler) Get(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "monitor ID required"})
+		return
+	}
+
+	userIDValue, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user context not found"})
+		return
+	}
+	userID := userIDValue.(int)
+
+	monitor, err := h.monitorRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "monitor not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve monitor"})
+		return
+	}
+
+	if monitor.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		return
+	}
+
+	// Check IDs are UUIDs, anything else cannot exist
+	checkID := c.Param("check_id")
+	if _, err := uuid.Parse(checkID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "check not found"})
+		return
+	}
+
+	check, err := h.monitorRepo.GetCheckByID(checkID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "check not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve check"})
+		return
+	}
+	if check.MonitorID != monitor.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "check not found"})
+		return
+	}
+
+	response := CheckResponse{MonitorCheck: *check}
+	evidence, err := h.evidenceRepo.GetByCheckID(check.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve check evidence"})
+		return
+	}
+	response.Evidence = evidence
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/eovipmak/v-insight/backend/internal/utils"
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+	"github.com/eovipmak/v-insight/shared/domain/repository"
+	"github.com/eovipmak/v-insight/shared/secrets"
+	"github.com/gin-gonic/gin"
+)
+
+// maxClientCertificatePEMSize limits the uploaded certificate chain and key
+const maxClientCertificatePEMSize = 64 * 1024
+
+type ClientCertificateHandler struct {
+	repo   repository.ClientCertificateRepository
+	cipher *secrets.Cipher
+}
+
+func NewClientCertificateHandler(repo repository.ClientCertificateRepository, cipher *secrets.Cipher) *ClientCertificateHandler {
+	return &ClientCertificateHandler{repo: repo, cipher: cipher}
+}
+
+type CreateClientCertificateRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Certificate string `json:"certificate" binding:"required"` // PEM, leaf first, optionally followed by intermediates
+	PrivateKey  string `json:"private_key" binding:"required"` // PEM, PKCS#1, PKCS#8 or EC
+}
+
+// Create uploads a client certificate; the PEM data is stored encrypted and never returned
+func (h *ClientCertificateHandler) Create(c *gin.Context) {
+	var req CreateClientCertificateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDValue, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user context not found"})
+		return
+	}
+	userID := userIDValue.(int)
+
+	sanitizedName, valid := utils.SanitizeAndValidate(req.Name, 1, 255)
+	if !valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name must be between 1 and 255 characters"})
+		return
+	}
+
+	if len(req.Certificate)+len(req.PrivateKey) > maxClientCertificatePEMSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "certificate and private key must be at most 64KB"})
+		return
+	}
+
+	leaf, err := parseClientCertificate(req.Certificate, req.PrivateKey)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	encrypted, err := h.cipher.EncryptJSON(entities.ClientCertificateSecrets{
+		Certificate: req.Certificate,
+		PrivateKey:  req.PrivateKey,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save client certificate"})
+		return
+	}
+
+	fingerprint := sha256.Sum256(leaf.Raw)
+	certificate := &entities.ClientCertificate{
+		UserID:           userID,
+		Name:             sanitizedName,
+		Subject:          leaf.Subject.String(),
+		Issuer:           leaf.Issuer.String(),
+		Fingerprint:      hex.EncodeToString(fingerprint[:]),
+		NotBefore:        leaf.NotBefore,
+		NotAfter:         leaf.NotAfter,
+		EncryptedSecrets: encrypted,
+	}
+
+	if err := h.repo.Create(certificate); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create client certificate"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, certificate)
+}
+
+// List returns the client certificates of the user, soonest expiry first
+func (h *ClientCertificateHandler) List(c *gin.Context) {
+	userIDValue, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user context not found"})
+		return
+	}
+	userID := userIDValue.(int)
+
+	certificates, err := h.repo.GetByUserID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve client certificates"})
+		return
+	}
+
+	if certificates == nil {
+		certificates = []*entities.ClientCertificate{}
+	}
+
+	c.JSON(http.StatusOK, certificates)
+}
+
+// Delete removes a client certificate; monitors using it stop presenting a certificate
+func (h *ClientCertificateHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id required"})
+		return
+	}
+
+	userIDValue, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user context not found"})
+		return
+	}
+	userID := userIDValue.(int)
+
+	certificate, err := h.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "client certificate not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve client certificate"})
+		return
+	}
+
+	if certificate.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		return
+	}
+
+	if err := h.repo.Delete(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete client certificate"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "client certificate deleted successfully"})
+}
+
+// parseClientCertificate checks that the PEM certificate and key form a pair usable for
+// client authentication and returns the leaf certificate
+func parseClientCertificate(certificatePEM, privateKeyPEM string) (*x509.Certificate, error) {
+	pair, err := tls.X509KeyPair([]byte(certificatePEM), []byte(privateKeyPEM))
+	if err != nil {
+		return nil, errors.New("invalid certificate or private key: " + err.Error())
+	}
+
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, errors.New("invalid certificate: " + err.Error())
+	}
+
+	if time.Now().After(leaf.NotAfter) {
+		return nil, errors.New("certificate has expired")
+	}
+
+	// Without extended key usages a certificate may be used for any purpose
+	if len(leaf.ExtKeyUsage) > 0 {
+		clientAuth := false
+		for _, usage := range leaf.ExtKeyUsage {
+			if usage == x509.ExtKeyUsageClientAuth || usage == x509.ExtKeyUsageAny {
+				clientAuth = true
+				break
+			}
+		}
+		if !clientAuth {
+			return nil, errors.New("certificate is not valid for client authentication")
+		}
+	}
+
+	return leaf, nil
+}
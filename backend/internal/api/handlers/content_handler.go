@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/eovipmak/v-insight/shared/content"
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+	"github.com/eovipmak/v-insight/shared/domain/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// ContentHandler serves the content snapshots of HTTP monitors with change detection
+type ContentHandler struct {
+	monitorRepo repository.MonitorRepository
+	contentRepo repository.ContentBaselineRepository
+}
+
+// NewContentHandler creates a new content handler
+func NewContentHandler(monitorRepo repository.MonitorRepository, contentRepo repository.ContentBaselineRepository) *ContentHandler {
+	return &ContentHandler{
+		monitorRepo: monitorRepo,
+		contentRepo: contentRepo,
+	}
+}
+
+// ContentResponse is the accepted baseline and latest snapshot of a monitor with their diff
+type ContentResponse struct {
+	entities.ContentBaseline
+	Changed bool   `json:"changed"`
+	Diff    string `json:"diff,omitempty"` // unified diff from the baseline to the current content
+}
+
+// Get godoc
+// @Summary Get the content snapshots of a monitor
+// @Description Returns the accepted baseline, the latest content and their diff for an HTTP monitor with change detection
+// @Tags Monitors
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Monitor ID"
+// @Success 200 {object} ContentResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Monitor or baseline not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /monitors/{id}/content [get]
+func (h *ContentHandler) Get(c *gin.Context) {
+	monitor, ok := h.authorizeMonitor(c)
+	if !ok {
+		return
+	}
+
+	baseline, err := h.contentRepo.GetByMonitorID(monitor.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "no content baseline has been taken for this monitor yet"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve content baseline"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ContentResponse{
+		ContentBaseline: *baseline,
+		Changed:         baseline.CurrentHash != baseline.BaselineHash,
+		Diff:            content.Diff(baseline.BaselineContent, baseline.CurrentContent),
+	})
+}
+
+// Accept godoc
+// @Summary Accept the current content of a monitor
+// @Description Makes the latest content the new baseline, open content_changed incidents resolve on the next check
+// @Tags Monitors
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Monitor ID"
+// @Success 200 {object} map[string]string "Content accepted"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Monitor or baseline not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /monitors/{id}/content/accept [post]
+func (h *ContentHandler) Accept(c *gin.Context) {
+	monitor, ok := h.authorizeMonitor(c)
+	if !ok {
+		return
+	}
+
+	if _, err := h.contentRepo.GetByMonitorID(monitor.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "no content baseline has been taken for this monitor yet"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve content baseline"})
+		return
+	}
+
+	if err := h.contentRepo.Accept(monitor.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to accept content"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "content accepted as the new baseline"})
+}
+
+// authorizeMonitor loads the monitor of the request and checks that it belongs to the user,
+// writing the error response when it does not
+func (h *ContentHandler) authorizeMonitor(c *gin.Context) (*entities.Monitor, bool) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "monitor ID required"})
+		return nil, false
+	}
+
+	userIDValue, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user context not found"})
+		return nil, false
+	}
+	userID := userIDValue.(int)
+
+	monitor, err := h.monitorRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "monitor not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve monitor"})
+		return nil, false
+	}
+
+	if monitor.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		return nil, false
+	}
+
+	return monitor, true
+}
@@ -35,7 +35,7 @@ func NewMetricsHandler(metricsService *service.MetricsService, monitorRepo repos
 // @Security BearerAuth
 // @Param id path string true "Monitor ID"
 // @Param period query string false "Time period for metrics (24h, 7d, or 30d)" default(24h)
-// @Success 200 {object} map[string]interface{} "Monitor metrics including uptime, response time history, and status code distribution"
+// @Success 200 {object} map[string]interface{} "Monitor metrics including uptime, response time history, HTTP timing breakdown, and status code distribution"
 // @Failure 400 {object} map[string]string "Invalid request or period parameter"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 403 {object} map[string]string "Access denied"
@@ -99,6 +99,14 @@ func (h *MetricsHandler) GetMonitorMetrics(c *gin.Context) {
 		return
 	}
 
+	// Get HTTP timing breakdown history
+	timingBreakdownHistory, err := h.metricsService.GetTimingBreakdownHistory(id, period)
+	if err != nil {
+		internal.Log.Error("failed to get timing breakdown history", zap.Error(err), zap.String("monitor_id", id), zap.String("period", period))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get timing breakdown history"})
+		return
+	}
+
 	// Get status code distribution
 	statusCodeDistribution, err := h.metricsService.GetStatusCodeDistribution(id, period)
 	if err != nil {
@@ -127,11 +135,15 @@ func (h *MetricsHandler) GetMonitorMetrics(c *gin.Context) {
 	if statusCodeDistribution == nil {
 		statusCodeDistribution = []service.StatusCodeDistribution{}
 	}
+	if timingBreakdownHistory == nil {
+		timingBreakdownHistory = []service.TimingBreakdownPoint{}
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"period":                   period,
 		"uptime":                   uptime,
 		"response_time_history":    responseTimeHistory,
+		"timing_breakdown_history": timingBreakdownHistory,
 		"status_code_distribution": statusCodeDistribution,
 		"average_response_time":    avgResponseTime,
 	})
@@ -1,46 +1,50 @@
 package handlers
 
 import (
+	"crypto/rand"
 	"database/sql"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net/http"
-	"net/url"
-	"regexp"
 	"strings"
 
 	"github.com/eovipmak/v-insight/shared/domain/entities"
 	"github.com/eovipmak/v-insight/shared/domain/repository"
+	"github.com/eovipmak/v-insight/shared/monitortype"
+	"github.com/eovipmak/v-insight/shared/secrets"
 	"github.com/eovipmak/v-insight/backend/internal/domain/service"
 	"github.com/eovipmak/v-insight/backend/internal/utils"
 	"github.com/gin-gonic/gin"
 )
 
-var tcpAddressRegex = regexp.MustCompile(`^[^:]+:\d+$`)
-
 // MonitorHandler handles monitor-related HTTP requests
 type MonitorHandler struct {
 	monitorRepo      repository.MonitorRepository
 	alertRuleRepo    repository.AlertRuleRepository
 	alertChannelRepo repository.AlertChannelRepository
+	clientCertRepo   repository.ClientCertificateRepository
 	monitorService   *service.MonitorService
+	cipher           *secrets.Cipher
 }
 
 // NewMonitorHandler creates a new monitor handler
-func NewMonitorHandler(monitorRepo repository.MonitorRepository, alertRuleRepo repository.AlertRuleRepository, alertChannelRepo repository.AlertChannelRepository, monitorService *service.MonitorService) *MonitorHandler {
+func NewMonitorHandler(monitorRepo repository.MonitorRepository, alertRuleRepo repository.AlertRuleRepository, alertChannelRepo repository.AlertChannelRepository, clientCertRepo repository.ClientCertificateRepository, monitorService *service.MonitorService, cipher *secrets.Cipher) *MonitorHandler {
 	return &MonitorHandler{
 		monitorRepo:      monitorRepo,
 		alertRuleRepo:    alertRuleRepo,
 		alertChannelRepo: alertChannelRepo,
+		clientCertRepo:   clientCertRepo,
 		monitorService:   monitorService,
+		cipher:           cipher,
 	}
 }
 
 // CreateMonitorRequest represents the request body for creating a monitor
 type CreateMonitorRequest struct {
 	Name                string  `json:"name" binding:"required"`
-	URL                 string  `json:"url" binding:"required"`
-	Type                string  `json:"type" binding:"omitempty,oneof=http tcp ping icmp"`
+	URL                 string  `json:"url"`                                            // required except for push and database monitors
+	Type                string  `json:"type"`                                           // one of the registered monitor types, http when empty
 	Keyword             *string `json:"keyword" binding:"omitempty"`
 	CheckInterval       int     `json:"check_interval" binding:"omitempty,min=60"`     // minimum 60 seconds
 	Timeout             int     `json:"timeout" binding:"omitempty,min=5,max=120"`     // 5-120 seconds
@@ -49,13 +53,15 @@ type CreateMonitorRequest struct {
 	SSLAlertDays        int     `json:"ssl_alert_days" binding:"omitempty,min=1"`      // minimum 1 day
 	Tags                []string `json:"tags"`                                          // tags for filtering/organization
 	ExpectedStatusCodes []int64  `json:"expected_status_codes"`                         // expected HTTP status codes
+	Config              *entities.MonitorConfig `json:"config"`                         // type-specific settings
+	ClientCertificateID *string `json:"client_certificate_id"`                          // mTLS certificate, empty string to detach on update
 }
 
 // UpdateMonitorRequest represents the request body for updating a monitor
 type UpdateMonitorRequest struct {
 	Name                string   `json:"name" binding:"omitempty"`
 	URL                 string   `json:"url" binding:"omitempty"`
-	Type                string   `json:"type" binding:"omitempty,oneof=http tcp ping icmp"`
+	Type                string   `json:"type"`                                          // one of the registered monitor types
 	Keyword             *string  `json:"keyword" binding:"omitempty"`
 	CheckInterval       int      `json:"check_interval" binding:"omitempty,min=60"`
 	Timeout             int      `json:"timeout" binding:"omitempty,min=5,max=120"`
@@ -64,6 +70,8 @@ type UpdateMonitorRequest struct {
 	SSLAlertDays        int      `json:"ssl_alert_days" binding:"omitempty,min=1"`
 	Tags                []string `json:"tags"`                                          // tags for filtering/organization
 	ExpectedStatusCodes []int64  `json:"expected_status_codes"`                         // expected HTTP status codes
+	Config              *entities.MonitorConfig `json:"config"`                         // type-specific settings
+	ClientCertificateID *string `json:"client_certificate_id"`                          // mTLS certificate, empty string to detach on update
 }
 
 // Create godoc
@@ -98,27 +106,33 @@ func (h *MonitorHandler) Create(c *gin.Context) {
 	if monitorType == "" {
 		monitorType = "http" // default
 	}
-	
-	if monitorType == "http" {
-		// Validate as URL
-		if _, err := url.ParseRequestURI(req.URL); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid URL format"})
-			return
-		}
-	} else if monitorType == "tcp" {
-		// Validate as host:port
-		if !tcpAddressRegex.MatchString(req.URL) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Host:Port format. Use format: host:port"})
-			return
-		}
-	} else if monitorType == "ping" || monitorType == "icmp" {
-		// Validate as hostname or IP (no protocol)
-		if strings.Contains(req.URL, "://") {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Hostname/IP format. Do not include protocol (http://, etc.)"})
+	typeDef, ok := monitortype.Get(monitorType)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": unsupportedMonitorTypeError(monitorType)})
+		return
+	}
+
+	// Push monitors are never probed and database monitors derive their URL from the DSN
+	if req.URL == "" && !typeDef.TargetOptional {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "url is required"})
+		return
+	}
+	if req.URL != "" && !typeDef.UsesDSN() {
+		if err := typeDef.ValidateTarget(req.URL); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 	}
 
+	var config entities.MonitorConfig
+	if req.Config != nil {
+		config = *req.Config
+	}
+	if err := typeDef.ValidateConfig(&config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Sanitize user inputs to prevent XSS
 	sanitizedName, valid := utils.SanitizeAndValidate(req.Name, 1, 255)
 	if !valid {
@@ -163,6 +177,20 @@ func (h *MonitorHandler) Create(c *gin.Context) {
 		expectedStatusCodes = []int64{200} // default to 200 OK
 	}
 
+	// Push monitors are identified by the token of their heartbeat URL and never probed
+	var pushToken *string
+	if monitorType == "push" {
+		token, err := generatePushToken()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create monitor"})
+			return
+		}
+		pushToken = &token
+	}
+	if !typeDef.CertificateCheck {
+		checkSSL = false
+	}
+
 	monitor := &entities.Monitor{
 		UserID:              userID,
 		Name:                sanitizedName,
@@ -176,6 +204,21 @@ func (h *MonitorHandler) Create(c *gin.Context) {
 		SSLAlertDays:        sslAlertDays,
 		Tags:                req.Tags,
 		ExpectedStatusCodes: expectedStatusCodes,
+		Config:              config,
+		PushToken:           pushToken,
+	}
+
+	if req.ClientCertificateID != nil && *req.ClientCertificateID != "" {
+		monitor.ClientCertificateID = req.ClientCertificateID
+	}
+	if err := h.validateClientCertificate(userID, monitor); err != nil {
+		writeClientCertificateError(c, err)
+		return
+	}
+
+	if err := h.storeMonitorSecrets(typeDef, monitor); err != nil {
+		writeSecretsError(c, err)
+		return
 	}
 
 	if err := h.monitorRepo.Create(monitor); err != nil {
@@ -184,14 +227,15 @@ func (h *MonitorHandler) Create(c *gin.Context) {
 	}
 
 	// Auto-create SSL expiry alert rule if SSL checking is enabled
-	// (Only for HTTP)
-	if monitorType == "http" && monitor.CheckSSL && monitor.SSLAlertDays > 0 {
+	// (Only for types with a certificate check, e.g. HTTP or mail servers)
+	if typeDef.CertificateCheck && monitor.CheckSSL && monitor.SSLAlertDays > 0 {
 		if err := h.createOrUpdateSSLAlertRule(userID, monitor); err != nil {
 			// Log error but don't fail the monitor creation
 			fmt.Printf("Warning: Failed to create SSL alert rule for monitor %s: %v\n", monitor.Name, err)
 		}
 	}
 
+	monitor.Config = monitor.Config.Masked()
 	c.JSON(http.StatusCreated, monitor)
 }
 
@@ -226,6 +270,11 @@ func (h *MonitorHandler) List(c *gin.Context) {
 		monitors = []*entities.Monitor{}
 	}
 
+	// Never return stored credentials
+	for _, monitor := range monitors {
+		monitor.Config = monitor.Config.Masked()
+	}
+
 	c.JSON(http.StatusOK, monitors)
 }
 
@@ -275,6 +324,7 @@ func (h *MonitorHandler) GetByID(c *gin.Context) {
 		return
 	}
 
+	monitor.Config = monitor.Config.Masked()
 	c.JSON(http.StatusOK, monitor)
 }
 
@@ -338,26 +388,17 @@ func (h *MonitorHandler) Update(c *gin.Context) {
 		monitorType = req.Type
 	}
 
-	// Validate URL if provided
-	if req.URL != "" {
-		if monitorType == "http" {
-			// Validate as URL
-			if _, err := url.ParseRequestURI(req.URL); err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid URL format"})
-				return
-			}
-		} else if monitorType == "tcp" {
-			// Validate as host:port
-			if !tcpAddressRegex.MatchString(req.URL) {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Host:Port format. Use format: host:port"})
-				return
-			}
-		} else if monitorType == "ping" || monitorType == "icmp" {
-			// Validate as hostname or IP (no protocol)
-			if strings.Contains(req.URL, "://") {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Hostname/IP format. Do not include protocol (http://, etc.)"})
-				return
-			}
+	typeDef, ok := monitortype.Get(monitorType)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": unsupportedMonitorTypeError(monitorType)})
+		return
+	}
+
+	// Validate URL if provided (database monitors take the address from config.database.dsn)
+	if req.URL != "" && !typeDef.UsesDSN() {
+		if err := typeDef.ValidateTarget(req.URL); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
 		}
 	}
 
@@ -371,7 +412,7 @@ func (h *MonitorHandler) Update(c *gin.Context) {
 		}
 		monitor.Name = sanitizedName
 	}
-	if req.URL != "" {
+	if req.URL != "" && !typeDef.UsesDSN() {
 		monitor.URL = req.URL
 	}
 	if req.Type != "" {
@@ -409,14 +450,65 @@ func (h *MonitorHandler) Update(c *gin.Context) {
 		monitor.ExpectedStatusCodes = req.ExpectedStatusCodes
 	}
 
+	// Replace type-specific settings if provided, then validate against the resulting type
+	// Masked secrets sent back unchanged keep their stored value, settings kept from a previous
+	// type are dropped
+	if req.Config != nil {
+		if err := req.Config.RestoreSecrets(monitor.Config); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		monitor.Config = *req.Config
+	} else {
+		typeDef.DropUnsupported(&monitor.Config)
+	}
+	if err := typeDef.ValidateConfig(&monitor.Config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Push monitors keep their heartbeat token; other types must not accept heartbeats
+	if monitorType == "push" {
+		if monitor.PushToken == nil {
+			token, err := generatePushToken()
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update monitor"})
+				return
+			}
+			monitor.PushToken = &token
+		}
+	} else {
+		monitor.PushToken = nil
+	}
+
+	if !typeDef.CertificateCheck {
+		monitor.CheckSSL = false
+	}
+
+	if req.ClientCertificateID != nil {
+		monitor.ClientCertificateID = req.ClientCertificateID
+		if *req.ClientCertificateID == "" {
+			monitor.ClientCertificateID = nil
+		}
+	}
+	if err := h.validateClientCertificate(userID, monitor); err != nil {
+		writeClientCertificateError(c, err)
+		return
+	}
+
+	if err := h.storeMonitorSecrets(typeDef, monitor); err != nil {
+		writeSecretsError(c, err)
+		return
+	}
+
 	if err := h.monitorRepo.Update(monitor); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update monitor"})
 		return
 	}
 
 	// Update SSL alert rule if SSL settings changed
-	// (Only if type is HTTP)
-	if monitorType == "http" && (req.CheckSSL != nil || req.SSLAlertDays > 0) {
+	// (Only if the type has a certificate check, e.g. HTTP or mail servers)
+	if typeDef.CertificateCheck && (req.CheckSSL != nil || req.SSLAlertDays > 0) {
 		if monitor.CheckSSL && monitor.SSLAlertDays > 0 {
 			if err := h.createOrUpdateSSLAlertRule(userID, monitor); err != nil {
 				fmt.Printf("Warning: Failed to update SSL alert rule for monitor %s: %v\n", monitor.Name, err)
@@ -427,19 +519,20 @@ func (h *MonitorHandler) Update(c *gin.Context) {
 				fmt.Printf("Warning: Failed to disable SSL alert rule for monitor %s: %v\n", monitor.Name, err)
 			}
 		}
-	} else if monitorType != "http" {
+	} else if !typeDef.CertificateCheck {
         // If type changed from HTTP to something else, disable SSL rule?
         // We probably should.
         // If monitor.Type was updated, we should check if we need to clean up SSL rules.
         // But simplifying: just leave it for now or disable if type changed.
         // If we switched away from HTTP, we should probably disable SSL monitoring rules.
-        if req.Type != "" && req.Type != "http" {
+        if req.Type != "" {
              if err := h.disableSSLAlertRule(userID, monitor.ID); err != nil {
 				fmt.Printf("Warning: Failed to disable SSL alert rule for monitor %s: %v\n", monitor.Name, err)
 			}
         }
     }
 
+	monitor.Config = monitor.Config.Masked()
 	c.JSON(http.StatusOK, monitor)
 }
 
@@ -577,7 +670,7 @@ func (h *MonitorHandler) GetChecks(c *gin.Context) {
 // GET /api/v1/monitors/:id/ssl-status
 // GetSSLStatus godoc
 // @Summary Get SSL certificate status
-// @Description Get SSL certificate information and expiration status for a monitor
+// @Description Get SSL certificate information, expiration status and TLS posture (presented chain, protocol, cipher, weaknesses) for a monitor
 // @Tags Monitors
 // @Accept json
 // @Produce json
@@ -806,3 +899,140 @@ func (h *MonitorHandler) disableSSLAlertRule(userID int, monitorID string) error
 
 	return nil
 }
+
+// unsupportedMonitorTypeError describes a monitor type that is not registered
+func unsupportedMonitorTypeError(monitorType string) string {
+	return fmt.Sprintf("invalid monitor type %q. Use one of: %s", monitorType, strings.Join(monitortype.Names(), ", "))
+}
+
+var (
+	// errDSNRequired is returned when a database monitor has no connection string
+	errDSNRequired = errors.New("config.database.dsn is required")
+	// errEncryptSecrets is returned when monitor credentials cannot be encrypted
+	errEncryptSecrets = errors.New("failed to encrypt credentials")
+)
+
+// storeMonitorSecrets encrypts the credentials of a monitor into its secrets column: the DSN of a
+// database monitor, which also replaces the monitor URL with the credential-free address, and the
+// passwords of its config. Credentials sent back masked, or a database monitor without a new DSN,
+// keep the stored value.
+func (h *MonitorHandler) storeMonitorSecrets(typeDef *monitortype.Type, monitor *entities.Monitor) error {
+	var previous entities.MonitorSecrets
+	if monitor.EncryptedSecrets != nil {
+		if err := h.cipher.DecryptJSON(*monitor.EncryptedSecrets, &previous); err != nil {
+			// Usually saved with a previous SECRETS_ENCRYPTION_KEY, masked values must be entered again
+			previous = entities.MonitorSecrets{}
+		}
+	}
+
+	var monitorSecrets entities.MonitorSecrets
+	if err := monitor.Config.MoveSecrets(&monitorSecrets, previous); err != nil {
+		return err
+	}
+
+	if !typeDef.UsesDSN() {
+		if monitor.Config.Database != nil {
+			monitor.Config.Database.DSN = ""
+		}
+	} else {
+		dsn := monitor.Config.Database.DSN
+		monitor.Config.Database.DSN = ""
+		if dsn == "" || dsn == entities.MaskedSecret {
+			// The stored DSN must belong to the same engine, e.g. after switching from mysql to postgres
+			if previous.DSN == "" {
+				return errDSNRequired
+			}
+			if _, err := typeDef.RedactDSN(previous.DSN); err != nil {
+				return errDSNRequired
+			}
+			monitorSecrets.DSN = previous.DSN
+		} else {
+			redacted, err := typeDef.RedactDSN(dsn)
+			if err != nil {
+				return err
+			}
+			monitorSecrets.DSN = dsn
+			monitor.URL = redacted
+		}
+	}
+
+	if monitorSecrets.IsEmpty() {
+		monitor.EncryptedSecrets = nil
+		return nil
+	}
+	encrypted, err := h.cipher.EncryptJSON(monitorSecrets)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errEncryptSecrets, err)
+	}
+	monitor.EncryptedSecrets = &encrypted
+	return nil
+}
+
+// errClientCertificateLookup is returned when the client certificate of a monitor cannot be loaded
+var errClientCertificateLookup = errors.New("failed to retrieve client certificate")
+
+// validateClientCertificate checks that the client certificate of a monitor belongs to the user
+// and that the monitor connects over TLS: http, transaction, grpc or docker with TLS or tls:// tcp monitors
+func (h *MonitorHandler) validateClientCertificate(userID int, monitor *entities.Monitor) error {
+	if monitor.ClientCertificateID == nil {
+		return nil
+	}
+
+	switch monitor.Type {
+	case "http", "transaction":
+	case "grpc":
+		if monitor.Config.GRPC == nil || !monitor.Config.GRPC.TLS {
+			return errors.New("client certificates require TLS to be enabled for gRPC monitors")
+		}
+	case "tcp":
+		if !strings.HasPrefix(monitor.URL, "tls://") {
+			return errors.New("client certificates require a tls:// address for TCP monitors")
+		}
+	case "docker":
+		if monitor.Config.Docker == nil || !monitor.Config.Docker.TLS {
+			return errors.New("client certificates require TLS to be enabled for Docker monitors")
+		}
+	default:
+		return fmt.Errorf("client certificates are not supported for %s monitors", monitor.Type)
+	}
+
+	certificate, err := h.clientCertRepo.GetByID(*monitor.ClientCertificateID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("client certificate not found")
+		}
+		return errClientCertificateLookup
+	}
+	if certificate.UserID != userID {
+		return errors.New("client certificate not found")
+	}
+
+	return nil
+}
+
+// writeClientCertificateError responds to a validateClientCertificate failure
+func writeClientCertificateError(c *gin.Context, err error) {
+	if errors.Is(err, errClientCertificateLookup) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// writeSecretsError responds to a storeMonitorSecrets failure
+func writeSecretsError(c *gin.Context, err error) {
+	if errors.Is(err, errEncryptSecrets) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save monitor credentials"})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// generatePushToken returns a random token for the heartbeat URL of a push monitor
+func generatePushToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate push token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
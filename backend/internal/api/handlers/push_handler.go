@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/eovipmak/v-insight/backend/internal/utils"
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+	"github.com/eovipmak/v-insight/shared/domain/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// maxPushMessageLength limits the status message stored with a heartbeat
+const maxPushMessageLength = 1024
+
+// PushHandler handles heartbeats sent to push monitors (no auth required, the token identifies the monitor)
+type PushHandler struct {
+	monitorRepo   repository.MonitorRepository
+	streamHandler *StreamHandler
+}
+
+// NewPushHandler creates a new push handler
+func NewPushHandler(monitorRepo repository.MonitorRepository, streamHandler *StreamHandler) *PushHandler {
+	return &PushHandler{
+		monitorRepo:   monitorRepo,
+		streamHandler: streamHandler,
+	}
+}
+
+// Push godoc
+// @Summary Send a heartbeat to a push monitor
+// @Description Record a heartbeat for a push monitor, e.g. at the end of a cron job. Parameters can be sent as query string or form values.
+// @Tags Monitors
+// @Produce json
+// @Param token path string true "Push token of the monitor"
+// @Param status query string false "Job outcome (up or down)" default(up)
+// @Param msg query string false "Status message"
+// @Param duration query int false "Job duration in milliseconds"
+// @Success 200 {object} map[string]interface{} "Heartbeat recorded"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Push monitor not found"
+// @Failure 409 {object} map[string]string "Monitor is disabled"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /push/{token} [get]
+// @Router /push/{token} [post]
+func (h *PushHandler) Push(c *gin.Context) {
+	token := c.Param("token")
+	if token == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "push monitor not found"})
+		return
+	}
+
+	status := pushParam(c, "status")
+	if status == "" {
+		status = "up"
+	}
+	if status != "up" && status != "down" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be 'up' or 'down'"})
+		return
+	}
+
+	message := utils.SanitizeString(pushParam(c, "msg"))
+	if len(message) > maxPushMessageLength {
+		message = message[:maxPushMessageLength]
+	}
+
+	var duration int64
+	if value := pushParam(c, "duration"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "duration must be a non-negative number of milliseconds"})
+			return
+		}
+		duration = parsed
+	}
+
+	monitor, err := h.monitorRepo.GetByPushToken(token)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "push monitor not found"})
+		return
+	}
+	if !monitor.Enabled {
+		c.JSON(http.StatusConflict, gin.H{"error": "monitor is disabled"})
+		return
+	}
+
+	checkedAt := time.Now().UTC()
+	check := &entities.MonitorCheck{
+		MonitorID: monitor.ID,
+		CheckedAt: checkedAt,
+		Success:   status == "up",
+	}
+	if duration > 0 {
+		check.ResponseTimeMs = sql.NullInt64{Int64: duration, Valid: true}
+	}
+	if message != "" {
+		check.Details = &entities.CheckDetails{Push: &entities.PushCheckDetails{Message: message}}
+	}
+	if !check.Success {
+		errorMessage := message
+		if errorMessage == "" {
+			errorMessage = "heartbeat reported failure"
+		}
+		check.ErrorMessage = sql.NullString{String: errorMessage, Valid: true}
+	}
+
+	if err := h.monitorRepo.SaveCheck(check); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to record heartbeat"})
+		return
+	}
+
+	// The worker reports the monitor down once last_checked_at is older than the interval plus grace period
+	if err := h.monitorRepo.UpdateLastCheckedAt(monitor.ID, checkedAt); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to record heartbeat"})
+		return
+	}
+
+	data := map[string]interface{}{
+		"monitor_id":   monitor.ID,
+		"monitor_name": monitor.Name,
+		"success":      check.Success,
+		"checked_at":   checkedAt.Format(time.RFC3339),
+	}
+	if check.ResponseTimeMs.Valid {
+		data["response_time_ms"] = check.ResponseTimeMs.Int64
+	}
+	if check.ErrorMessage.Valid {
+		data["error_message"] = check.ErrorMessage.String
+	}
+	if check.Details != nil {
+		data["details"] = check.Details
+	}
+	h.streamHandler.BroadcastEvent("monitor_check", data, monitor.UserID)
+
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+// pushParam reads a heartbeat parameter from the query string or the form body
+func pushParam(c *gin.Context, name string) string {
+	if value, ok := c.GetQuery(name); ok {
+		return value
+	}
+	return c.PostForm(name)
+}
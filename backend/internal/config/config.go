@@ -26,6 +26,7 @@ type ServerConfig struct {
 type SecurityConfig struct {
 	HSTSMaxAge            int
 	HSTSIncludeSubdomains bool
+	EncryptionKey         string // encrypts stored monitor credentials, shared with the worker
 }
 
 type RateLimitConfig struct {
@@ -77,6 +78,7 @@ func Load() (*Config, error) {
 		Security: SecurityConfig{
 			HSTSMaxAge:            getEnvInt("HSTS_MAX_AGE", 31536000), // 1 year default
 			HSTSIncludeSubdomains: getEnv("HSTS_INCLUDE_SUBDOMAINS", "true") == "true",
+			EncryptionKey:         getEnv("SECRETS_ENCRYPTION_KEY", "dev-encryption-key-change-in-production"),
 		},
 		RateLimit: RateLimitConfig{
 			PerIP:   getEnvInt("RATE_LIMIT_PER_IP", 100),   // 100 requests per minute
@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/eovipmak/v-insight/shared/domain/entities"
@@ -65,7 +66,55 @@ func (s *AlertService) EvaluateCheck(check *entities.MonitorCheck, rules []*enti
 			if s.evaluateSSLExpiryTrigger(check, rule.ThresholdValue) {
 				triggered = append(triggered, &TriggeredAlert{
 					AlertRule:    rule,
-					TriggerValue: s.getSSLExpiryTriggerValue(check),
+					TriggerValue: s.getSSLExpiryTriggerValue(check, rule.ThresholdValue),
+				})
+			}
+
+		case "domain_expiry":
+			if s.evaluateDomainExpiryTrigger(check, rule.ThresholdValue) {
+				triggered = append(triggered, &TriggeredAlert{
+					AlertRule:    rule,
+					TriggerValue: s.getDomainExpiryTriggerValue(check),
+				})
+			}
+
+		case "packet_loss":
+			if s.evaluatePacketLossTrigger(check, rule.ThresholdValue) {
+				triggered = append(triggered, &TriggeredAlert{
+					AlertRule:    rule,
+					TriggerValue: s.getPacketLossTriggerValue(check),
+				})
+			}
+
+		case "ssl_invalid":
+			if s.evaluateSSLInvalidTrigger(check) {
+				triggered = append(triggered, &TriggeredAlert{
+					AlertRule:    rule,
+					TriggerValue: s.getSSLInvalidTriggerValue(check),
+				})
+			}
+
+		case "tls_weak":
+			if s.evaluateTLSWeakTrigger(check) {
+				triggered = append(triggered, &TriggeredAlert{
+					AlertRule:    rule,
+					TriggerValue: s.getTLSWeakTriggerValue(check),
+				})
+			}
+
+		case "ssl_changed":
+			if s.evaluateSSLChangedTrigger(check) {
+				triggered = append(triggered, &TriggeredAlert{
+					AlertRule:    rule,
+					TriggerValue: s.getSSLChangedTriggerValue(check),
+				})
+			}
+
+		case "content_changed":
+			if s.evaluateContentChangedTrigger(check) {
+				triggered = append(triggered, &TriggeredAlert{
+					AlertRule:    rule,
+					TriggerValue: s.getContentChangedTriggerValue(check),
 				})
 			}
 		}
@@ -89,12 +138,58 @@ func (s *AlertService) evaluateSlowResponseTrigger(check *entities.MonitorCheck,
 
 // evaluateSSLExpiryTrigger checks if SSL certificate expires within threshold days
 func (s *AlertService) evaluateSSLExpiryTrigger(check *entities.MonitorCheck, thresholdDays int) bool {
-	if !check.SSLExpiresAt.Valid {
+	if check.SSLExpiresAt.Valid {
+		daysUntilExpiry := int(time.Until(check.SSLExpiresAt.Time).Hours() / 24)
+		if daysUntilExpiry <= thresholdDays && daysUntilExpiry >= 0 {
+			return true
+		}
+	}
+
+	// The client certificate presented for mutual TLS is covered too
+	if check.Details != nil && check.Details.ClientCertificate != nil {
+		daysUntilExpiry := int(time.Until(check.Details.ClientCertificate.ExpiresAt).Hours() / 24)
+		return daysUntilExpiry <= thresholdDays && daysUntilExpiry >= 0
+	}
+
+	return false
+}
+
+// evaluateDomainExpiryTrigger checks if the domain registration expires within threshold days or has expired
+func (s *AlertService) evaluateDomainExpiryTrigger(check *entities.MonitorCheck, thresholdDays int) bool {
+	if check.Details == nil || check.Details.Domain == nil || check.Details.Domain.ExpiresAt == nil {
 		return false
 	}
 
-	daysUntilExpiry := int(time.Until(check.SSLExpiresAt.Time).Hours() / 24)
-	return daysUntilExpiry <= thresholdDays && daysUntilExpiry >= 0
+	daysUntilExpiry := int(time.Until(*check.Details.Domain.ExpiresAt).Hours() / 24)
+	return daysUntilExpiry <= thresholdDays
+}
+
+// evaluatePacketLossTrigger checks if ping packet loss exceeds threshold percentage
+func (s *AlertService) evaluatePacketLossTrigger(check *entities.MonitorCheck, thresholdPercent int) bool {
+	if check.Details == nil || check.Details.ICMP == nil {
+		return false
+	}
+	return check.Details.ICMP.PacketLoss > float64(thresholdPercent)
+}
+
+// evaluateSSLInvalidTrigger checks if the certificate failed verification
+func (s *AlertService) evaluateSSLInvalidTrigger(check *entities.MonitorCheck) bool {
+	return check.SSLValid.Valid && !check.SSLValid.Bool
+}
+
+// evaluateTLSWeakTrigger checks if the TLS setup has known weaknesses
+func (s *AlertService) evaluateTLSWeakTrigger(check *entities.MonitorCheck) bool {
+	return check.Details != nil && check.Details.SSL != nil && len(check.Details.SSL.Weaknesses) > 0
+}
+
+// evaluateSSLChangedTrigger checks if the certificate was replaced unexpectedly since the previous check
+func (s *AlertService) evaluateSSLChangedTrigger(check *entities.MonitorCheck) bool {
+	return check.Details != nil && check.Details.SSL != nil && check.Details.SSL.PreviousFingerprint != ""
+}
+
+// evaluateContentChangedTrigger checks if the content differs from the accepted baseline
+func (s *AlertService) evaluateContentChangedTrigger(check *entities.MonitorCheck) bool {
+	return check.Details != nil && check.Details.Content != nil && check.Details.Content.Changed
 }
 
 // getDownTriggerValue returns a description of the down trigger
@@ -114,14 +209,77 @@ func (s *AlertService) getSlowResponseTriggerValue(check *entities.MonitorCheck)
 }
 
 // getSSLExpiryTriggerValue returns a description of the SSL expiry trigger
-func (s *AlertService) getSSLExpiryTriggerValue(check *entities.MonitorCheck) string {
+func (s *AlertService) getSSLExpiryTriggerValue(check *entities.MonitorCheck, thresholdDays int) string {
 	if check.SSLExpiresAt.Valid {
 		daysUntilExpiry := int(time.Until(check.SSLExpiresAt.Time).Hours() / 24)
-		return fmt.Sprintf("SSL certificate expires in %d days (on %s)", daysUntilExpiry, check.SSLExpiresAt.Time.Format("2006-01-02"))
+		if daysUntilExpiry <= thresholdDays {
+			return fmt.Sprintf("SSL certificate expires in %d days (on %s)", daysUntilExpiry, check.SSLExpiresAt.Time.Format("2006-01-02"))
+		}
+	}
+	if check.Details != nil && check.Details.ClientCertificate != nil {
+		clientCertificate := check.Details.ClientCertificate
+		daysUntilExpiry := int(time.Until(clientCertificate.ExpiresAt).Hours() / 24)
+		return fmt.Sprintf("Client certificate %q expires in %d days (on %s)", clientCertificate.Name, daysUntilExpiry, clientCertificate.ExpiresAt.Format("2006-01-02"))
 	}
 	return "SSL certificate expiring soon"
 }
 
+// getDomainExpiryTriggerValue returns a description of the domain expiry trigger
+func (s *AlertService) getDomainExpiryTriggerValue(check *entities.MonitorCheck) string {
+	if check.Details != nil && check.Details.Domain != nil && check.Details.Domain.ExpiresAt != nil {
+		domain := check.Details.Domain
+		if domain.ExpiresAt.Before(time.Now()) {
+			return fmt.Sprintf("Domain %s expired on %s", domain.Domain, domain.ExpiresAt.Format("2006-01-02"))
+		}
+		daysUntilExpiry := int(time.Until(*domain.ExpiresAt).Hours() / 24)
+		return fmt.Sprintf("Domain %s expires in %d days (on %s)", domain.Domain, daysUntilExpiry, domain.ExpiresAt.Format("2006-01-02"))
+	}
+	return "Domain registration expiring soon"
+}
+
+// getPacketLossTriggerValue returns a description of the packet loss trigger
+func (s *AlertService) getPacketLossTriggerValue(check *entities.MonitorCheck) string {
+	if check.Details != nil && check.Details.ICMP != nil {
+		return fmt.Sprintf("Packet loss: %.1f%% (%d/%d received)",
+			check.Details.ICMP.PacketLoss, check.Details.ICMP.PacketsReceived, check.Details.ICMP.PacketsSent)
+	}
+	return "Packet loss detected"
+}
+
+// getSSLInvalidTriggerValue returns a description of the SSL invalid trigger
+func (s *AlertService) getSSLInvalidTriggerValue(check *entities.MonitorCheck) string {
+	if check.Details != nil && check.Details.SSL != nil && check.Details.SSL.Error != "" {
+		return fmt.Sprintf("SSL certificate is invalid: %s", check.Details.SSL.Error)
+	}
+	return "SSL certificate is invalid"
+}
+
+// getTLSWeakTriggerValue returns a description of the weak TLS trigger
+func (s *AlertService) getTLSWeakTriggerValue(check *entities.MonitorCheck) string {
+	if check.Details != nil && check.Details.SSL != nil {
+		return fmt.Sprintf("Weak TLS configuration: %s", strings.Join(check.Details.SSL.Weaknesses, "; "))
+	}
+	return "Weak TLS configuration"
+}
+
+// getSSLChangedTriggerValue returns a description of the certificate change trigger
+func (s *AlertService) getSSLChangedTriggerValue(check *entities.MonitorCheck) string {
+	if check.Details != nil && check.Details.SSL != nil {
+		return fmt.Sprintf("SSL certificate changed unexpectedly (SHA-256 %s, was %s)",
+			check.Details.SSL.Fingerprint, check.Details.SSL.PreviousFingerprint)
+	}
+	return "SSL certificate changed unexpectedly"
+}
+
+// getContentChangedTriggerValue returns a description of the content changed trigger
+func (s *AlertService) getContentChangedTriggerValue(check *entities.MonitorCheck) string {
+	if check.Details != nil && check.Details.Content != nil {
+		return fmt.Sprintf("Content changed from the accepted baseline (SHA-256 %s, baseline %s)",
+			check.Details.Content.Hash, check.Details.Content.BaselineHash)
+	}
+	return "Content changed from the accepted baseline"
+}
+
 // CreateIncident creates a new incident for a triggered alert
 func (s *AlertService) CreateIncident(monitorID, alertRuleID, triggerValue string) error {
 	// Check if there's already an open incident for this monitor+rule combination
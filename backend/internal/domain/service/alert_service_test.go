@@ -2,6 +2,7 @@ package service
 
 import (
 	"database/sql"
+	"strings"
 	"testing"
 	"time"
 
@@ -101,6 +102,7 @@ func (m *mockAlertRuleRepository) GetWithChannels(userID int, id string) (*entit
 func (m *mockAlertRuleRepository) GetAllWithChannelsByUserID(userID int) ([]*entities.AlertRuleWithChannels, error) { return nil, nil }
 func (m *mockAlertRuleRepository) GetAll() ([]*entities.AlertRule, error) { return nil, nil }
 func (m *mockAlertRuleRepository) GetAllEnabled() ([]*entities.AlertRule, error) { return nil, nil }
+func (m *mockAlertRuleRepository) GetByIDAdmin(id string) (*entities.AlertRule, error) { return nil, nil }
 
 func TestAlertService_EvaluateCheck_Down(t *testing.T) {
 	incidentRepo := newMockIncidentRepository()
@@ -219,6 +221,223 @@ func TestAlertService_EvaluateCheck_SSLExpiry(t *testing.T) {
 	}
 }
 
+func TestAlertService_EvaluateCheck_SSLExpiry_ClientCertificate(t *testing.T) {
+	incidentRepo := newMockIncidentRepository()
+	alertRepo := &mockAlertRuleRepository{}
+	service := NewAlertService(incidentRepo, alertRepo)
+
+	// Server certificate is fine, the client certificate expires in 3 days
+	check := &entities.MonitorCheck{
+		MonitorID: "test-monitor",
+		Success:   true,
+		SSLExpiresAt: sql.NullTime{
+			Time:  time.Now().Add(90 * 24 * time.Hour),
+			Valid: true,
+		},
+		Details: &entities.CheckDetails{
+			ClientCertificate: &entities.ClientCertificateCheckDetails{
+				Name:      "billing-client",
+				ExpiresAt: time.Now().Add(3*24*time.Hour + time.Hour),
+			},
+		},
+	}
+
+	rules := []*entities.AlertRule{
+		{
+			ID:             "rule-1",
+			TriggerType:    "ssl_expiry",
+			ThresholdValue: 7,
+			Enabled:        true,
+		},
+	}
+
+	triggered, err := service.EvaluateCheck(check, rules)
+	if err != nil {
+		t.Fatalf("EvaluateCheck failed: %v", err)
+	}
+
+	if len(triggered) != 1 {
+		t.Fatalf("Expected 1 triggered alert, got %d", len(triggered))
+	}
+
+	if !strings.Contains(triggered[0].TriggerValue, `Client certificate "billing-client" expires in 3 days`) {
+		t.Errorf("Unexpected trigger value: %s", triggered[0].TriggerValue)
+	}
+}
+
+func TestAlertService_EvaluateCheck_PacketLoss(t *testing.T) {
+	incidentRepo := newMockIncidentRepository()
+	alertRepo := &mockAlertRuleRepository{}
+	service := NewAlertService(incidentRepo, alertRepo)
+
+	check := &entities.MonitorCheck{
+		MonitorID: "test-monitor",
+		Success:   true,
+		Details: &entities.CheckDetails{
+			ICMP: &entities.ICMPCheckDetails{PacketsSent: 4, PacketsReceived: 2, PacketLoss: 50},
+		},
+	}
+
+	rules := []*entities.AlertRule{
+		{
+			ID:             "rule-1",
+			TriggerType:    "packet_loss",
+			ThresholdValue: 25, // Alert if more than 25% of probes are lost
+			Enabled:        true,
+		},
+	}
+
+	triggered, err := service.EvaluateCheck(check, rules)
+	if err != nil {
+		t.Fatalf("EvaluateCheck failed: %v", err)
+	}
+
+	if len(triggered) != 1 {
+		t.Fatalf("Expected 1 triggered alert, got %d", len(triggered))
+	}
+
+	if triggered[0].TriggerValue != "Packet loss: 50.0% (2/4 received)" {
+		t.Errorf("Unexpected trigger value: %s", triggered[0].TriggerValue)
+	}
+}
+
+func TestAlertService_EvaluateCheck_TLSPosture(t *testing.T) {
+	incidentRepo := newMockIncidentRepository()
+	alertRepo := &mockAlertRuleRepository{}
+	service := NewAlertService(incidentRepo, alertRepo)
+
+	check := &entities.MonitorCheck{
+		MonitorID: "test-monitor",
+		Success:   true,
+		SSLValid:  sql.NullBool{Bool: false, Valid: true},
+		Details: &entities.CheckDetails{
+			SSL: &entities.SSLCheckDetails{
+				Fingerprint:         "bbbb",
+				PreviousFingerprint: "aaaa",
+				Weaknesses:          []string{"legacy protocol TLS 1.0"},
+				Error:               "x509: certificate signed by unknown authority",
+			},
+		},
+	}
+
+	rules := []*entities.AlertRule{
+		{ID: "rule-invalid", TriggerType: "ssl_invalid", ThresholdValue: 1, Enabled: true},
+		{ID: "rule-weak", TriggerType: "tls_weak", ThresholdValue: 1, Enabled: true},
+		{ID: "rule-changed", TriggerType: "ssl_changed", ThresholdValue: 1, Enabled: true},
+	}
+
+	triggered, err := service.EvaluateCheck(check, rules)
+	if err != nil {
+		t.Fatalf("EvaluateCheck failed: %v", err)
+	}
+
+	if len(triggered) != 3 {
+		t.Fatalf("Expected 3 triggered alerts, got %d", len(triggered))
+	}
+
+	want := []string{
+		"SSL certificate is invalid: x509: certificate signed by unknown authority",
+		"Weak TLS configuration: legacy protocol TLS 1.0",
+		"SSL certificate changed unexpectedly (SHA-256 bbbb, was aaaa)",
+	}
+	for i, value := range want {
+		if triggered[i].TriggerValue != value {
+			t.Errorf("Expected trigger value %q, got %q", value, triggered[i].TriggerValue)
+		}
+	}
+
+	// A trusted, strong and unchanged certificate triggers nothing
+	check.SSLValid.Bool = true
+	check.Details.SSL = &entities.SSLCheckDetails{Fingerprint: "bbbb"}
+	triggered, err = service.EvaluateCheck(check, rules)
+	if err != nil {
+		t.Fatalf("EvaluateCheck failed: %v", err)
+	}
+	if len(triggered) != 0 {
+		t.Errorf("Expected no triggered alerts, got %d", len(triggered))
+	}
+}
+
+func TestAlertService_EvaluateCheck_DomainExpiry(t *testing.T) {
+	incidentRepo := newMockIncidentRepository()
+	alertRepo := &mockAlertRuleRepository{}
+	service := NewAlertService(incidentRepo, alertRepo)
+
+	expiresAt := time.Now().Add(10*24*time.Hour + time.Hour)
+	check := &entities.MonitorCheck{
+		MonitorID: "test-monitor",
+		Success:   true,
+		Details: &entities.CheckDetails{
+			Domain: &entities.DomainCheckDetails{Domain: "example.com", Registrar: "Example Registrar", ExpiresAt: &expiresAt},
+		},
+	}
+
+	rules := []*entities.AlertRule{
+		{ID: "rule-domain", TriggerType: "domain_expiry", ThresholdValue: 30, Enabled: true},
+	}
+
+	triggered, err := service.EvaluateCheck(check, rules)
+	if err != nil {
+		t.Fatalf("EvaluateCheck failed: %v", err)
+	}
+	if len(triggered) != 1 {
+		t.Fatalf("Expected 1 triggered alert, got %d", len(triggered))
+	}
+	want := "Domain example.com expires in 10 days (on " + expiresAt.Format("2006-01-02") + ")"
+	if triggered[0].TriggerValue != want {
+		t.Errorf("Expected trigger value %q, got %q", want, triggered[0].TriggerValue)
+	}
+
+	// A registration beyond the threshold triggers nothing
+	expiresAt = time.Now().Add(90 * 24 * time.Hour)
+	triggered, err = service.EvaluateCheck(check, rules)
+	if err != nil {
+		t.Fatalf("EvaluateCheck failed: %v", err)
+	}
+	if len(triggered) != 0 {
+		t.Errorf("Expected no triggered alerts, got %d", len(triggered))
+	}
+}
+
+func TestAlertService_EvaluateCheck_ContentChanged(t *testing.T) {
+	incidentRepo := newMockIncidentRepository()
+	alertRepo := &mockAlertRuleRepository{}
+	service := NewAlertService(incidentRepo, alertRepo)
+
+	contentDetails := &entities.ContentCheckDetails{Hash: "bbb", BaselineHash: "aaa", Changed: true}
+	check := &entities.MonitorCheck{
+		MonitorID: "test-monitor",
+		Success:   true,
+		Details:   &entities.CheckDetails{Content: contentDetails},
+	}
+
+	rules := []*entities.AlertRule{
+		{ID: "rule-content", TriggerType: "content_changed", ThresholdValue: 1, Enabled: true},
+	}
+
+	triggered, err := service.EvaluateCheck(check, rules)
+	if err != nil {
+		t.Fatalf("EvaluateCheck failed: %v", err)
+	}
+	if len(triggered) != 1 {
+		t.Fatalf("Expected 1 triggered alert, got %d", len(triggered))
+	}
+	want := "Content changed from the accepted baseline (SHA-256 bbb, baseline aaa)"
+	if triggered[0].TriggerValue != want {
+		t.Errorf("Expected trigger value %q, got %q", want, triggered[0].TriggerValue)
+	}
+
+	// Content matching the baseline triggers nothing
+	*contentDetails = entities.ContentCheckDetails{Hash: "aaa", BaselineHash: "aaa"}
+	triggered, err = service.EvaluateCheck(check, rules)
+	if err != nil {
+		t.Fatalf("EvaluateCheck failed: %v", err)
+	}
+	if len(triggered) != 0 {
+		t.Errorf("Expected no triggered alerts, got %d", len(triggered))
+	}
+}
+
 func TestAlertService_EvaluateCheck_NoTrigger(t *testing.T) {
 	incidentRepo := newMockIncidentRepository()
 	alertRepo := &mockAlertRuleRepository{}
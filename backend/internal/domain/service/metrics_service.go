@@ -28,6 +28,17 @@ type DataPoint struct {
 	Value     float64   `json:"value" db:"value"`
 }
 
+// TimingBreakdownPoint represents the average HTTP phase durations of a time bucket
+// The phases are sequential, so they can be stacked to show where the response time is spent
+type TimingBreakdownPoint struct {
+	Timestamp      time.Time `json:"timestamp" db:"timestamp"`
+	DNSLookupMs    float64   `json:"dns_lookup_ms" db:"dns_lookup_ms"`
+	TCPConnectMs   float64   `json:"tcp_connect_ms" db:"tcp_connect_ms"`
+	TLSHandshakeMs float64   `json:"tls_handshake_ms" db:"tls_handshake_ms"`
+	TTFBMs         float64   `json:"ttfb_ms" db:"ttfb_ms"`
+	TransferMs     float64   `json:"transfer_ms" db:"transfer_ms"`
+}
+
 // UptimeMetrics represents uptime statistics
 type UptimeMetrics struct {
 	Percentage    float64 `json:"percentage"`
@@ -107,24 +118,7 @@ func (s *MetricsService) GetResponseTimeHistory(monitorID string, period string)
 	startTime := time.Now().UTC().Add(-duration)
 
 	// Use time buckets for aggregation to reduce data points
-	// For 24h, use 5-minute buckets; for 7d, use 1-hour buckets; for 30d, use 6-hour buckets
-	var intervalSeconds int
-	switch period {
-	case "1h":
-		intervalSeconds = 60 // 1 minute
-	case "6h":
-		intervalSeconds = 300 // 5 minutes
-	case "12h":
-		intervalSeconds = 300 // 5 minutes
-	case "24h":
-		intervalSeconds = 300 // 5 minutes
-	case "7d", "1w":
-		intervalSeconds = 3600 // 1 hour
-	case "30d":
-		intervalSeconds = 21600 // 6 hours
-	default:
-		intervalSeconds = 300
-	}
+	intervalSeconds := bucketIntervalSeconds(period)
 
 	// Use PostgreSQL's date_trunc and EXTRACT(EPOCH) for time bucketing
 	query := `
@@ -149,6 +143,43 @@ func (s *MetricsService) GetResponseTimeHistory(monitorID string, period string)
 	return dataPoints, nil
 }
 
+// GetTimingBreakdownHistory retrieves the average HTTP phase durations over a period
+// Only checks that recorded a timing breakdown (HTTP monitors) are included
+func (s *MetricsService) GetTimingBreakdownHistory(monitorID string, period string) ([]TimingBreakdownPoint, error) {
+	duration, err := parsePeriodToDuration(period)
+	if err != nil {
+		return nil, err
+	}
+
+	startTime := time.Now().UTC().Add(-duration)
+	intervalSeconds := bucketIntervalSeconds(period)
+
+	query := `
+		SELECT 
+			TO_TIMESTAMP(FLOOR(EXTRACT(EPOCH FROM checked_at) / $1) * $1) as timestamp,
+			COALESCE(AVG(dns_lookup_ms), 0) as dns_lookup_ms,
+			COALESCE(AVG(tcp_connect_ms), 0) as tcp_connect_ms,
+			COALESCE(AVG(tls_handshake_ms), 0) as tls_handshake_ms,
+			COALESCE(AVG(ttfb_ms), 0) as ttfb_ms,
+			COALESCE(AVG(transfer_ms), 0) as transfer_ms
+		FROM monitor_checks
+		WHERE monitor_id = $2
+		  AND checked_at >= $3
+		  AND ttfb_ms IS NOT NULL
+		  AND success = true
+		GROUP BY FLOOR(EXTRACT(EPOCH FROM checked_at) / $1)
+		ORDER BY timestamp ASC
+	`
+
+	var points []TimingBreakdownPoint
+	err = s.db.Select(&points, query, intervalSeconds, monitorID, startTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timing breakdown history: %w", err)
+	}
+
+	return points, nil
+}
+
 // GetStatusCodeDistribution retrieves status code distribution over a period
 func (s *MetricsService) GetStatusCodeDistribution(monitorID string, period string) ([]StatusCodeDistribution, error) {
 	duration, err := parsePeriodToDuration(period)
@@ -273,6 +304,23 @@ func (s *MetricsService) GetGlobalUptime(userID int, period string) (*UptimeMetr
 	}, nil
 }
 
+// bucketIntervalSeconds returns the aggregation bucket size for a period
+// For 24h, use 5-minute buckets; for 7d, use 1-hour buckets; for 30d, use 6-hour buckets
+func bucketIntervalSeconds(period string) int {
+	switch period {
+	case "1h":
+		return 60 // 1 minute
+	case "6h", "12h", "24h":
+		return 300 // 5 minutes
+	case "7d", "1w":
+		return 3600 // 1 hour
+	case "30d":
+		return 21600 // 6 hours
+	default:
+		return 300
+	}
+}
+
 // parsePeriodToDuration converts period string to time.Duration
 func parsePeriodToDuration(period string) (time.Duration, error) {
 	switch period {
@@ -118,3 +118,27 @@ func TestStatusCodeDistribution_Structure(t *testing.T) {
 	assert.Equal(t, 200, dist.StatusCode)
 	assert.Equal(t, 100, dist.Count)
 }
+
+func TestBucketIntervalSeconds(t *testing.T) {
+	assert.Equal(t, 60, bucketIntervalSeconds("1h"))
+	assert.Equal(t, 300, bucketIntervalSeconds("24h"))
+	assert.Equal(t, 3600, bucketIntervalSeconds("7d"))
+	assert.Equal(t, 3600, bucketIntervalSeconds("1w"))
+	assert.Equal(t, 21600, bucketIntervalSeconds("30d"))
+	assert.Equal(t, 300, bucketIntervalSeconds("unknown"))
+}
+
+func TestTimingBreakdownPoint_Structure(t *testing.T) {
+	now := time.Now()
+	point := &TimingBreakdownPoint{
+		Timestamp:      now,
+		DNSLookupMs:    5,
+		TCPConnectMs:   10,
+		TLSHandshakeMs: 20,
+		TTFBMs:         100,
+		TransferMs:     3,
+	}
+
+	assert.Equal(t, now, point.Timestamp)
+	assert.Equal(t, 138.0, point.DNSLookupMs+point.TCPConnectMs+point.TLSHandshakeMs+point.TTFBMs+point.TransferMs)
+}
@@ -27,6 +27,8 @@ type SSLStatus struct {
 	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
 	DaysUntilExpiry int      `json:"days_until_expiry"`
 	ExpiringSoon  bool       `json:"expiring_soon"`
+	CheckedAt     time.Time  `json:"checked_at"`
+	Details       *entities.SSLCheckDetails `json:"details,omitempty"` // chain, TLS version, cipher and weaknesses
 }
 
 // GetSSLStatus retrieves the SSL certificate status for a monitor
@@ -34,7 +36,7 @@ type SSLStatus struct {
 func (s *MonitorService) GetSSLStatus(monitorID string) (*SSLStatus, error) {
 	// Get the latest check with SSL information
 	query := `
-		SELECT ssl_valid, ssl_expires_at
+		SELECT ssl_valid, ssl_expires_at, checked_at, details
 		FROM monitor_checks
 		WHERE monitor_id = $1
 		  AND ssl_valid IS NOT NULL
@@ -44,8 +46,10 @@ func (s *MonitorService) GetSSLStatus(monitorID string) (*SSLStatus, error) {
 
 	var sslValid sql.NullBool
 	var sslExpiresAt sql.NullTime
+	var checkedAt time.Time
+	var details entities.CheckDetails
 
-	err := s.db.QueryRow(query, monitorID).Scan(&sslValid, &sslExpiresAt)
+	err := s.db.QueryRow(query, monitorID).Scan(&sslValid, &sslExpiresAt, &checkedAt, &details)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// No SSL check data available yet
@@ -60,7 +64,9 @@ func (s *MonitorService) GetSSLStatus(monitorID string) (*SSLStatus, error) {
 	}
 
 	status := &SSLStatus{
-		Valid: sslValid.Bool,
+		Valid:     sslValid.Bool,
+		CheckedAt: checkedAt,
+		Details:   details.SSL,
 	}
 
 	// Calculate days until expiry if we have expiry date
@@ -0,0 +1,3 @@
+ALTER TABLE monitor_checks DROP COLUMN IF EXISTS details;
+
+ALTER TABLE monitors DROP COLUMN IF EXISTS config;
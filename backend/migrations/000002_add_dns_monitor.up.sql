@@ -0,0 +1,5 @@
+-- Add JSONB column for type-specific monitor settings (e.g. DNS record type, resolver)
+ALTER TABLE monitors ADD COLUMN IF NOT EXISTS config JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+-- Add JSONB column for type-specific check results (e.g. resolved DNS answers)
+ALTER TABLE monitor_checks ADD COLUMN IF NOT EXISTS details JSONB;
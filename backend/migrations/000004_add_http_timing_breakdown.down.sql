@@ -0,0 +1,5 @@
+ALTER TABLE monitor_checks DROP COLUMN IF EXISTS transfer_ms;
+ALTER TABLE monitor_checks DROP COLUMN IF EXISTS ttfb_ms;
+ALTER TABLE monitor_checks DROP COLUMN IF EXISTS tls_handshake_ms;
+ALTER TABLE monitor_checks DROP COLUMN IF EXISTS tcp_connect_ms;
+ALTER TABLE monitor_checks DROP COLUMN IF EXISTS dns_lookup_ms;
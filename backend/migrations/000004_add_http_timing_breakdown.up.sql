@@ -0,0 +1,6 @@
+-- Add per-phase timing of HTTP checks (milliseconds)
+ALTER TABLE monitor_checks ADD COLUMN IF NOT EXISTS dns_lookup_ms INTEGER;
+ALTER TABLE monitor_checks ADD COLUMN IF NOT EXISTS tcp_connect_ms INTEGER;
+ALTER TABLE monitor_checks ADD COLUMN IF NOT EXISTS tls_handshake_ms INTEGER;
+ALTER TABLE monitor_checks ADD COLUMN IF NOT EXISTS ttfb_ms INTEGER;
+ALTER TABLE monitor_checks ADD COLUMN IF NOT EXISTS transfer_ms INTEGER;
@@ -0,0 +1,2 @@
+UPDATE monitors SET type = 'http', enabled = false WHERE type = 'transaction';
+ALTER TABLE monitors ALTER COLUMN type TYPE VARCHAR(10);
@@ -0,0 +1,2 @@
+-- Widen monitor type for longer type names such as 'transaction'
+ALTER TABLE monitors ALTER COLUMN type TYPE VARCHAR(32);
@@ -0,0 +1,3 @@
+UPDATE monitors SET type = 'http', enabled = false WHERE type = 'push';
+DROP INDEX IF EXISTS idx_monitors_push_token;
+ALTER TABLE monitors DROP COLUMN IF EXISTS push_token;
@@ -0,0 +1,4 @@
+-- Add token for push (heartbeat) monitors, used in the public /api/push/:token URL
+ALTER TABLE monitors ADD COLUMN IF NOT EXISTS push_token VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_monitors_push_token ON monitors(push_token) WHERE push_token IS NOT NULL;
@@ -0,0 +1,2 @@
+UPDATE monitors SET type = 'http', enabled = false WHERE type IN ('postgres', 'mysql', 'redis');
+ALTER TABLE monitors DROP COLUMN IF EXISTS encrypted_secrets;
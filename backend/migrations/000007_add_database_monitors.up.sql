@@ -0,0 +1,2 @@
+-- Add encrypted credentials (e.g. database DSNs) kept out of the plain url and config columns
+ALTER TABLE monitors ADD COLUMN IF NOT EXISTS encrypted_secrets TEXT;
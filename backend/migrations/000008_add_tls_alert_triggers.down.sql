@@ -0,0 +1,4 @@
+DELETE FROM alert_rules WHERE trigger_type IN ('ssl_invalid', 'tls_weak', 'ssl_changed');
+ALTER TABLE alert_rules DROP CONSTRAINT IF EXISTS alert_rules_trigger_type_check;
+ALTER TABLE alert_rules ADD CONSTRAINT alert_rules_trigger_type_check
+    CHECK (trigger_type IN ('down', 'ssl_expiry', 'slow_response', 'packet_loss'));
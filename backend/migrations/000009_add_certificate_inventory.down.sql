@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS certificates;
+DROP TABLE IF EXISTS certificate_targets;
@@ -0,0 +1,52 @@
+-- Standalone host:port endpoints whose certificates are tracked without a monitor (SMTP, LDAPS, database TLS, ...)
+CREATE TABLE IF NOT EXISTS certificate_targets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    host VARCHAR(255) NOT NULL,
+    port INTEGER NOT NULL,
+    server_name VARCHAR(255) NOT NULL DEFAULT '',
+    starttls VARCHAR(16) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_certificate_targets_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_certificate_targets_user_id ON certificate_targets(user_id);
+
+CREATE TRIGGER update_certificate_targets_updated_at BEFORE UPDATE ON certificate_targets
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Certificate inventory: latest certificate seen on each HTTPS monitor and certificate target
+CREATE TABLE IF NOT EXISTS certificates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id INTEGER NOT NULL,
+    monitor_id UUID,
+    target_id UUID,
+    host VARCHAR(255) NOT NULL,
+    port INTEGER NOT NULL,
+    subject TEXT NOT NULL DEFAULT '',
+    issuer TEXT NOT NULL DEFAULT '',
+    sans TEXT[],
+    not_before TIMESTAMP,
+    not_after TIMESTAMP,
+    expires_at TIMESTAMP,
+    fingerprint VARCHAR(64) NOT NULL DEFAULT '',
+    valid BOOLEAN NOT NULL DEFAULT false,
+    error TEXT,
+    details JSONB,
+    checked_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_certificates_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_certificates_monitor FOREIGN KEY (monitor_id) REFERENCES monitors(id) ON DELETE CASCADE,
+    CONSTRAINT fk_certificates_target FOREIGN KEY (target_id) REFERENCES certificate_targets(id) ON DELETE CASCADE,
+    CONSTRAINT chk_certificates_source CHECK ((monitor_id IS NULL) <> (target_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_certificates_monitor_id ON certificates(monitor_id) WHERE monitor_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_certificates_target_id ON certificates(target_id) WHERE target_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_certificates_user_expires ON certificates(user_id, expires_at);
+
+CREATE TRIGGER update_certificates_updated_at BEFORE UPDATE ON certificates
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,4 @@
+-- Allow alerting on domain registrations about to lapse
+ALTER TABLE alert_rules DROP CONSTRAINT IF EXISTS alert_rules_trigger_type_check;
+ALTER TABLE alert_rules ADD CONSTRAINT alert_rules_trigger_type_check
+    CHECK (trigger_type IN ('down', 'ssl_expiry', 'slow_response', 'packet_loss', 'ssl_invalid', 'tls_weak', 'ssl_changed', 'domain_expiry'));
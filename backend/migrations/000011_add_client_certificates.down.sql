@@ -0,0 +1,2 @@
+ALTER TABLE monitors DROP COLUMN IF EXISTS client_certificate_id;
+DROP TABLE IF EXISTS client_certificates;
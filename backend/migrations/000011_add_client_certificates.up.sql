@@ -0,0 +1,24 @@
+-- Client certificates presented by monitors to servers that require mutual TLS
+CREATE TABLE IF NOT EXISTS client_certificates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    subject TEXT NOT NULL DEFAULT '',
+    issuer TEXT NOT NULL DEFAULT '',
+    fingerprint VARCHAR(64) NOT NULL,
+    not_before TIMESTAMP NOT NULL,
+    not_after TIMESTAMP NOT NULL,
+    encrypted_secrets TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_client_certificates_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_client_certificates_user_id ON client_certificates(user_id);
+
+CREATE TRIGGER update_client_certificates_updated_at BEFORE UPDATE ON client_certificates
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Deleting a certificate detaches it from the monitors using it
+ALTER TABLE monitors ADD COLUMN IF NOT EXISTS client_certificate_id UUID
+    CONSTRAINT fk_monitors_client_certificate REFERENCES client_certificates(id) ON DELETE SET NULL;
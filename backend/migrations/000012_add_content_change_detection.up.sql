@@ -0,0 +1,22 @@
+-- Content change detection: accepted baseline and latest snapshot of each HTTP monitor with config.content
+CREATE TABLE IF NOT EXISTS content_baselines (
+    monitor_id UUID PRIMARY KEY,
+    config_hash VARCHAR(64) NOT NULL,
+    baseline_hash VARCHAR(64) NOT NULL,
+    baseline_content TEXT NOT NULL,
+    accepted_at TIMESTAMP NOT NULL,
+    current_hash VARCHAR(64) NOT NULL,
+    current_content TEXT NOT NULL,
+    checked_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_content_baselines_monitor FOREIGN KEY (monitor_id) REFERENCES monitors(id) ON DELETE CASCADE
+);
+
+CREATE TRIGGER update_content_baselines_updated_at BEFORE UPDATE ON content_baselines
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Allow alerting when the content differs from the accepted baseline
+ALTER TABLE alert_rules DROP CONSTRAINT IF EXISTS alert_rules_trigger_type_check;
+ALTER TABLE alert_rules ADD CONSTRAINT alert_rules_trigger_type_check
+    CHECK (trigger_type IN ('down', 'ssl_expiry', 'slow_response', 'packet_loss', 'ssl_invalid', 'tls_weak', 'ssl_changed', 'domain_expiry', 'content_changed'));
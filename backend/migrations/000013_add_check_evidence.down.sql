@@ -0,0 +1,2 @@
+ALTER TABLE incidents DROP COLUMN IF EXISTS check_id;
+DROP TABLE IF EXISTS check_evidence;
\ No newline at end of file
@@ -0,0 +1,19 @@
+-- Check evidence: raw response of failed (and sampled successful) HTTP checks, kept shorter than the checks
+CREATE TABLE IF NOT EXISTS check_evidence (
+    check_id UUID PRIMARY KEY,
+    monitor_id UUID NOT NULL,
+    remote_ip VARCHAR(45),
+    headers JSONB NOT NULL DEFAULT '{}',
+    body_snippet TEXT NOT NULL DEFAULT '',
+    body_truncated BOOLEAN NOT NULL DEFAULT false,
+    tls JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_check_evidence_check FOREIGN KEY (check_id) REFERENCES monitor_checks(id) ON DELETE CASCADE,
+    CONSTRAINT fk_check_evidence_monitor FOREIGN KEY (monitor_id) REFERENCES monitors(id) ON DELETE CASCADE
+);
+
+-- Retention cleanup
+CREATE INDEX IF NOT EXISTS idx_check_evidence_created_at ON check_evidence(created_at);
+
+-- Check that opened an incident, its evidence is linked from the notification
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS check_id UUID REFERENCES monitor_checks(id) ON DELETE SET NULL;
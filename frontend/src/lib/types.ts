@@ -3,7 +3,7 @@ export interface Monitor {
     user_id: number;
     name: string;
     url: string;
-    type: 'http' | 'tcp' | 'ping' | 'icmp';
+    type: 'http' | 'tcp' | 'udp' | 'ping' | 'icmp' | 'dns' | 'transaction' | 'push' | 'websocket' | 'grpc' | 'smtp' | 'imap' | 'pop3' | 'postgres' | 'mysql' | 'redis' | 'docker' | 'domain';
     keyword?: string;
     check_interval: number;
     timeout: number;
@@ -12,6 +12,9 @@ export interface Monitor {
     ssl_alert_days: number;
     tags?: string[];
     expected_status_codes?: number[];
+    config?: MonitorConfig;
+    push_token?: string; // push monitors only, heartbeat URL is /api/push/<push_token>
+    client_certificate_id?: string; // presented for mutual TLS by http, transaction, grpc and tls:// tcp monitors
     last_checked_at?: string;
     created_at: string;
     updated_at: string;
@@ -28,12 +31,266 @@ export interface MonitorCheck {
     checked_at: string;
     status_code?: number;
     response_time_ms?: number;
+    dns_lookup_ms?: number;
+    tcp_connect_ms?: number;
+    tls_handshake_ms?: number;
+    ttfb_ms?: number;
+    transfer_ms?: number;
     ssl_valid?: boolean;
     ssl_expires_at?: string;
     error_message?: string;
+    details?: CheckDetails;
     success: boolean;
 }
 
+export interface HTTPRequestConfig {
+    method?: string;
+    headers?: Record<string, string>;
+    body?: string;
+    content_type?: string;
+    auth?: {
+        type: 'basic' | 'bearer';
+        username?: string;
+        password?: string; // '********' when returned by the API
+        token?: string; // '********' when returned by the API
+    };
+    assertions?: {
+        source: 'json' | 'header' | 'content_length';
+        property?: string;
+        operator: 'equals' | 'not_equals' | 'greater_than' | 'less_than' | 'contains' | 'exists' | 'matches';
+        value?: string;
+    }[];
+}
+
+export interface TransactionStep extends HTTPRequestConfig {
+    name: string;
+    url?: string;
+    expected_status_codes?: number[];
+    keyword?: string;
+    extract?: {
+        variable: string;
+        source: 'json' | 'header' | 'cookie';
+        property: string;
+    }[];
+}
+
+export interface MonitorConfig {
+    dns?: {
+        record_type: 'A' | 'AAAA' | 'CNAME' | 'MX' | 'TXT' | 'NS';
+        resolver?: string;
+        expected_values?: string[];
+        match_mode?: 'contains' | 'exact';
+    };
+    icmp?: {
+        count?: number;
+    };
+    http?: HTTPRequestConfig;
+    push?: {
+        grace_period?: number; // seconds
+    };
+    grpc?: {
+        service?: string;
+        tls?: boolean;
+        metadata?: Record<string, string>; // credential-like values are '********' when returned by the API
+    };
+    websocket?: {
+        headers?: Record<string, string>; // credential-like values are '********' when returned by the API
+        subprotocol?: string;
+        send?: string;
+        expect?: string;
+        match_mode?: 'contains' | 'regex';
+    };
+    mail?: { // smtp, imap and pop3 monitors
+        tls?: 'starttls' | 'implicit'; // plaintext when omitted
+        insecure_skip_verify?: boolean;
+        username?: string; // requires tls
+        password?: string; // '********' when returned by the API
+        expect_banner?: string;
+    };
+    database?: {
+        dsn?: string; // write-only, stored encrypted and never returned by the API
+        query?: string;
+        assertions?: {
+            property?: string;
+            operator: 'equals' | 'not_equals' | 'greater_than' | 'less_than' | 'contains' | 'exists' | 'matches';
+            value?: string;
+        }[];
+    };
+    docker?: {
+        endpoint?: string; // unix:///path/to/docker.sock or tcp://host:port, unix:///var/run/docker.sock when omitted; sockets must be enabled on the worker
+        tls?: boolean; // tcp:// endpoints only
+        ca_certificate?: string; // PEM
+        insecure_skip_verify?: boolean;
+    };
+    tcp?: {
+        send?: string;
+        expect?: string;
+        match_mode?: 'contains' | 'regex';
+    };
+    udp?: {
+        preset?: 'dns' | 'ntp' | 'snmp';
+        payload?: string; // required without a preset
+        encoding?: 'text' | 'hex'; // of payload
+        expect?: string;
+        match_mode?: 'contains' | 'regex' | 'hex';
+        community?: string; // snmp preset, 'public' when omitted; '********' when returned by the API
+    };
+    transaction?: {
+        steps: TransactionStep[];
+        secrets?: Record<string, string>; // values are '********' when returned by the API
+    };
+    connection?: { // http and transaction monitors
+        insecure_skip_verify?: boolean;
+        min_tls_version?: '1.0' | '1.1' | '1.2' | '1.3';
+        server_name?: string; // SNI and Host header
+        resolve_to?: string; // IP address pinned for the URL host
+        ip_family?: 'ipv4' | 'ipv6';
+    };
+    proxy?: { // http, transaction and tcp monitors
+        type: 'http' | 'socks5';
+        address: string; // host:port
+        username?: string;
+        password?: string; // '********' when returned by the API
+    };
+    redirect?: { // http monitors
+        follow?: boolean; // true when omitted
+        max_redirects?: number; // 5 when omitted
+        expected_final_url?: string;
+        expected_location?: string; // requires follow: false
+    };
+    content?: { // http monitors, change detection
+        selector?: string; // CSS selector
+        json_path?: string;
+        ignore?: string[]; // regexes removed before hashing
+    };
+}
+
+export interface CheckDetails {
+    dns?: {
+        record_type: string;
+        resolver: string;
+        answers: string[];
+    };
+    icmp?: {
+        packets_sent: number;
+        packets_received: number;
+        packet_loss: number;
+        min_rtt_ms: number;
+        avg_rtt_ms: number;
+        max_rtt_ms: number;
+        jitter_ms: number;
+    };
+    push?: {
+        message?: string;
+    };
+    grpc?: {
+        status?: 'SERVING' | 'NOT_SERVING' | 'UNKNOWN' | 'SERVICE_UNKNOWN';
+        code?: string;
+    };
+    http?: {
+        redirects: { // every request of the check in order
+            url: string;
+            status_code: number;
+            location?: string;
+        }[];
+        final_url: string;
+    };
+    content?: {
+        hash: string; // SHA-256 of the selected content
+        baseline_hash: string;
+        changed: boolean;
+        size: number;
+        error?: string;
+    };
+    ssl?: {
+        fingerprint: string; // SHA-256 of the leaf certificate
+        previous_fingerprint?: string; // set when the certificate changed unexpectedly
+        hostname_match: boolean;
+        tls_version: string;
+        cipher_suite: string;
+        weaknesses?: string[];
+        error?: string;
+        chain: {
+            subject: string;
+            issuer: string;
+            sans?: string[];
+            not_before: string;
+            not_after: string;
+            key_type: string;
+            key_bits: number;
+            signature_algorithm: string;
+            fingerprint: string;
+        }[];
+        revocation?: {
+            status: 'good' | 'revoked' | 'unknown';
+            source?: 'ocsp_staple' | 'ocsp' | 'crl';
+            response_ms: number;
+            revoked_at?: string;
+            reason?: string;
+            error?: string;
+        };
+    };
+    client_certificate?: {
+        id: string;
+        name: string;
+        fingerprint: string;
+        expires_at: string;
+    };
+    domain?: {
+        domain: string; // registrable domain that was looked up
+        registrar?: string;
+        expires_at?: string;
+        source?: 'rdap' | 'whois';
+    };
+    database?: {
+        connect_ms: number;
+        query_ms: number;
+        result?: string;
+    };
+    docker?: {
+        container_id: string;
+        image?: string;
+        status: 'created' | 'running' | 'paused' | 'restarting' | 'removing' | 'exited' | 'dead';
+        health?: 'starting' | 'healthy' | 'unhealthy';
+        restart_count: number;
+        exit_code?: number;
+        started_at?: string;
+    };
+    tcp?: {
+        tls: boolean;
+        response?: string;
+    };
+    websocket?: {
+        handshake_ms: number;
+        first_message_ms?: number;
+        subprotocol?: string;
+        message?: string;
+    };
+    mail?: {
+        protocol: 'smtp' | 'imap' | 'pop3';
+        banner?: string;
+        tls: boolean;
+        authenticated: boolean;
+    };
+    udp?: {
+        preset?: string;
+        response: string;
+        encoding: 'text' | 'hex';
+        size: number;
+    };
+    transaction?: {
+        steps: {
+            name: string;
+            url: string;
+            status_code?: number;
+            response_time_ms: number;
+            success: boolean;
+            error?: string;
+        }[];
+        failed_step?: number;
+    };
+}
+
 export interface MaintenanceWindow {
     id: string;
     user_id: number;
@@ -47,3 +304,79 @@ export interface MaintenanceWindow {
     updated_at: string;
 }
 
+export interface CertificateTarget {
+    id: string;
+    user_id: number;
+    name: string;
+    host: string;
+    port: number;
+    server_name?: string; // SNI and hostname to verify, host when empty
+    starttls?: '' | 'smtp' | 'postgres';
+    created_at: string;
+    updated_at: string;
+}
+
+export interface Certificate {
+    id: string;
+    user_id: number;
+    monitor_id?: string;
+    target_id?: string;
+    source_name: string; // name of the monitor or target
+    host: string;
+    port: number;
+    subject: string;
+    issuer: string;
+    sans?: string[];
+    not_before?: string;
+    not_after?: string; // leaf certificate
+    expires_at?: string; // earliest expiry in the chain
+    fingerprint: string;
+    valid: boolean;
+    error?: string;
+    details?: CheckDetails['ssl'];
+    checked_at: string;
+    created_at: string;
+    updated_at: string;
+}
+
+export interface ClientCertificate {
+    id: string;
+    user_id: number;
+    name: string;
+    subject: string;
+    issuer: string;
+    fingerprint: string; // SHA-256 of the certificate
+    not_before: string;
+    not_after: string;
+    created_at: string;
+    updated_at: string;
+}
+
+export interface ContentBaseline {
+    monitor_id: string;
+    baseline_hash: string;
+    baseline_content: string;
+    accepted_at: string;
+    current_hash: string;
+    current_content: string;
+    checked_at: string;
+    changed: boolean;
+    diff?: string; // unified diff from the baseline to the current content
+    created_at: string;
+    updated_at: string;
+}
+
+export interface CheckEvidence {
+    check_id: string;
+    monitor_id: string;
+    remote_ip?: string; // address that answered, the proxy when one is used
+    headers: Record<string, string[]>; // Set-Cookie values are redacted
+    body_snippet: string;
+    body_truncated: boolean;
+    tls?: CheckDetails['ssl'];
+    created_at: string;
+}
+
+export interface CheckWithEvidence extends MonitorCheck {
+    evidence: CheckEvidence | null; // kept for failed and sampled HTTP checks until the retention period ends
+}
@@ -0,0 +1,352 @@
+// Package content normalizes HTTP response bodies for change detection and diffs the snapshots
+package content
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+	"github.com/tidwall/gjson"
+	"golang.org/x/net/html"
+)
+
+// Extract returns the part of body compared by change detection: the elements matching the CSS
+// selector or the value at the JSON path of cfg, with the matches of the ignore regexes removed
+// A selector or path that matches nothing yields empty content
+func Extract(body []byte, cfg *entities.ContentConfig) (string, error) {
+	var text string
+	switch {
+	case cfg.Selector != "":
+		selectors, err := ParseSelector(cfg.Selector)
+		if err != nil {
+			return "", err
+		}
+		doc, err := html.Parse(bytes.NewReader(body))
+		if err != nil {
+			return "", fmt.Errorf("failed to parse HTML: %w", err)
+		}
+		var parts []string
+		for _, node := range selectNodes(doc, selectors) {
+			var buf bytes.Buffer
+			if err := html.Render(&buf, node); err != nil {
+				return "", fmt.Errorf("failed to render HTML: %w", err)
+			}
+			parts = append(parts, buf.String())
+		}
+		text = strings.Join(parts, "\n")
+
+	case cfg.JSONPath != "":
+		if !gjson.ValidBytes(body) {
+			return "", errors.New("response is not valid JSON")
+		}
+		result := gjson.GetBytes(body, cfg.JSONPath)
+		switch {
+		case !result.Exists():
+		case result.Type == gjson.String:
+			text = result.String()
+		default:
+			// One value per line so that diffs point at the changed property
+			var buf bytes.Buffer
+			if err := json.Indent(&buf, []byte(result.Raw), "", "  "); err != nil {
+				return "", fmt.Errorf("failed to format JSON: %w", err)
+			}
+			text = buf.String()
+		}
+
+	default:
+		text = string(body)
+	}
+
+	for _, pattern := range cfg.Ignore {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return "", fmt.Errorf("invalid ignore pattern %q: %w", pattern, err)
+		}
+		text = re.ReplaceAllString(text, "")
+	}
+
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.TrimSpace(text), nil
+}
+
+// Hash returns the hex encoded SHA-256 of the content
+func Hash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// ConfigHash identifies the extraction settings, a baseline taken with other settings is not comparable
+func ConfigHash(cfg *entities.ContentConfig) string {
+	data, _ := json.Marshal(cfg)
+	return Hash(string(data))
+}
+
+// Selector is a parsed CSS selector list, matched against HTML elements
+// Supported: type, *, #id, .class, [attr] and [attr=value] selectors, combined with
+// descendant (whitespace) and child (>) combinators and grouped with commas
+type Selector []complexSelector
+
+// complexSelector is a chain of compound selectors joined by combinators
+type complexSelector struct {
+	parts       []compoundSelector
+	combinators []byte // combinators[i] joins parts[i] and parts[i+1], ' ' or '>'
+}
+
+// compoundSelector matches a single element
+type compoundSelector struct {
+	tag     string // lower case, any element when empty
+	id      string
+	classes []string
+	attrs   []attributeSelector
+}
+
+// attributeSelector matches an attribute, and its exact value when hasValue is set
+type attributeSelector struct {
+	name     string
+	value    string
+	hasValue bool
+}
+
+// ParseSelector parses a CSS selector list
+func ParseSelector(s string) (Selector, error) {
+	p := &selectorParser{s: strings.TrimSpace(s)}
+	if p.s == "" {
+		return nil, errors.New("empty selector")
+	}
+
+	var selector Selector
+	for {
+		complex, err := p.parseComplex()
+		if err != nil {
+			return nil, err
+		}
+		selector = append(selector, complex)
+
+		if p.pos >= len(p.s) {
+			return selector, nil
+		}
+		// parseComplex only stops early at a comma
+		p.pos++
+		p.skipSpace()
+	}
+}
+
+type selectorParser struct {
+	s   string
+	pos int
+}
+
+func (p *selectorParser) parseComplex() (complexSelector, error) {
+	var complex complexSelector
+	for {
+		compound, err := p.parseCompound()
+		if err != nil {
+			return complex, err
+		}
+		complex.parts = append(complex.parts, compound)
+
+		hadSpace := p.skipSpace()
+		if p.pos >= len(p.s) || p.s[p.pos] == ',' {
+			return complex, nil
+		}
+
+		combinator := byte(' ')
+		if p.s[p.pos] == '>' {
+			combinator = '>'
+			p.pos++
+			p.skipSpace()
+		} else if !hadSpace {
+			return complex, fmt.Errorf("unexpected %q at position %d", p.s[p.pos], p.pos)
+		}
+		complex.combinators = append(complex.combinators, combinator)
+	}
+}
+
+func (p *selectorParser) parseCompound() (compoundSelector, error) {
+	var compound compoundSelector
+	start := p.pos
+
+	if p.pos < len(p.s) && p.s[p.pos] == '*' {
+		p.pos++
+	} else {
+		compound.tag = strings.ToLower(p.parseIdent())
+	}
+
+	for p.pos < len(p.s) {
+		switch p.s[p.pos] {
+		case '#':
+			p.pos++
+			if compound.id = p.parseIdent(); compound.id == "" {
+				return compound, fmt.Errorf("expected an id at position %d", p.pos)
+			}
+			continue
+		case '.':
+			p.pos++
+			class := p.parseIdent()
+			if class == "" {
+				return compound, fmt.Errorf("expected a class name at position %d", p.pos)
+			}
+			compound.classes = append(compound.classes, class)
+			continue
+		case '[':
+			p.pos++
+			attr, err := p.parseAttribute()
+			if err != nil {
+				return compound, err
+			}
+			compound.attrs = append(compound.attrs, attr)
+			continue
+		}
+		break
+	}
+
+	if p.pos == start {
+		if p.pos >= len(p.s) {
+			return compound, errors.New("selector ends with a combinator or comma")
+		}
+		return compound, fmt.Errorf("unexpected %q at position %d", p.s[p.pos], p.pos)
+	}
+	return compound, nil
+}
+
+func (p *selectorParser) parseAttribute() (attributeSelector, error) {
+	p.skipSpace()
+	attr := attributeSelector{name: strings.ToLower(p.parseIdent())}
+	if attr.name == "" {
+		return attr, fmt.Errorf("expected an attribute name at position %d", p.pos)
+	}
+	p.skipSpace()
+
+	if p.pos < len(p.s) && p.s[p.pos] == '=' {
+		p.pos++
+		p.skipSpace()
+		attr.hasValue = true
+		if p.pos < len(p.s) && (p.s[p.pos] == '"' || p.s[p.pos] == '\'') {
+			quote := p.s[p.pos]
+			end := strings.IndexByte(p.s[p.pos+1:], quote)
+			if end < 0 {
+				return attr, fmt.Errorf("unterminated string at position %d", p.pos)
+			}
+			attr.value = p.s[p.pos+1 : p.pos+1+end]
+			p.pos += end + 2
+		} else {
+			attr.value = p.parseIdent()
+		}
+		p.skipSpace()
+	}
+
+	if p.pos >= len(p.s) || p.s[p.pos] != ']' {
+		return attr, fmt.Errorf("expected ']' at position %d", p.pos)
+	}
+	p.pos++
+	return attr, nil
+}
+
+// parseIdent reads a name made of letters, digits, '-' and '_'
+func (p *selectorParser) parseIdent() string {
+	start := p.pos
+	for p.pos < len(p.s) {
+		ch := p.s[p.pos]
+		if !(ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9' || ch == '-' || ch == '_') {
+			break
+		}
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+// skipSpace skips whitespace and reports whether there was any
+func (p *selectorParser) skipSpace() bool {
+	start := p.pos
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+// selectNodes returns the elements matching the selector in document order,
+// elements inside a match are part of it and not returned separately
+func selectNodes(root *html.Node, selector Selector) []*html.Node {
+	var matches []*html.Node
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for _, complex := range selector {
+			if complex.matches(n, len(complex.parts)-1) {
+				matches = append(matches, n)
+				return
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(root)
+	return matches
+}
+
+// matches reports whether n matches parts[0:i+1] of the complex selector, with parts[i] matching n itself
+func (s complexSelector) matches(n *html.Node, i int) bool {
+	if !s.parts[i].matches(n) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+
+	if s.combinators[i-1] == '>' {
+		return n.Parent != nil && s.matches(n.Parent, i-1)
+	}
+	for ancestor := n.Parent; ancestor != nil; ancestor = ancestor.Parent {
+		if s.matches(ancestor, i-1) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c compoundSelector) matches(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	if c.tag != "" && n.Data != c.tag {
+		return false
+	}
+	if c.id != "" {
+		if id, _ := attribute(n, "id"); id != c.id {
+			return false
+		}
+	}
+	if len(c.classes) > 0 {
+		classes, _ := attribute(n, "class")
+		fields := strings.Fields(classes)
+		for _, class := range c.classes {
+			if !slices.Contains(fields, class) {
+				return false
+			}
+		}
+	}
+	for _, attr := range c.attrs {
+		value, ok := attribute(n, attr.name)
+		if !ok || (attr.hasValue && value != attr.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// attribute returns the value of the attribute of n and whether it is set
+func attribute(n *html.Node, name string) (string, bool) {
+	for _, attr := range n.Attr {
+		if attr.Key == name {
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
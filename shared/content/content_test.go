@@ -0,0 +1,133 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+)
+
+const testPage = `<!DOCTYPE html>
+<html><head><title>Terms</title><script>var nonce = "a1b2c3";</script></head>
+<body>
+  <nav class="menu top"><a href="/">Home</a></nav>
+  <main id="terms">
+    <h1>Terms of Service</h1>
+    <p class="clause" data-section="1">You agree to the terms.</p>
+    <p class="clause note">Last updated: 2026-10-16</p>
+  </main>
+  <footer><p>Generated at 12:00:01</p></footer>
+</body></html>`
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		cfg     entities.ContentConfig
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "whole body",
+			body: "line 1\r\nline 2\r\n",
+			want: "line 1\nline 2",
+		},
+		{
+			name: "element by type",
+			body: testPage,
+			cfg:  entities.ContentConfig{Selector: "h1"},
+			want: "<h1>Terms of Service</h1>",
+		},
+		{
+			name: "descendant, class and attribute selectors",
+			body: testPage,
+			cfg:  entities.ContentConfig{Selector: `main#terms p.clause[data-section="1"]`},
+			want: `<p class="clause" data-section="1">You agree to the terms.</p>`,
+		},
+		{
+			name: "child combinator and selector list",
+			body: testPage,
+			cfg:  entities.ContentConfig{Selector: "nav.menu > a, footer p"},
+			want: "<a href=\"/\">Home</a>\n<p>Generated at 12:00:01</p>",
+		},
+		{
+			name: "ignore patterns",
+			body: testPage,
+			cfg:  entities.ContentConfig{Selector: "p.note", Ignore: []string{`\d{4}-\d{2}-\d{2}`}},
+			want: `<p class="clause note">Last updated: </p>`,
+		},
+		{
+			name: "no match",
+			body: testPage,
+			cfg:  entities.ContentConfig{Selector: "article"},
+			want: "",
+		},
+		{
+			name: "JSON object",
+			body: `{"data":{"terms":{"version":3,"text":"Be nice"}}}`,
+			cfg:  entities.ContentConfig{JSONPath: "data.terms"},
+			want: "{\n  \"version\": 3,\n  \"text\": \"Be nice\"\n}",
+		},
+		{
+			name: "JSON string",
+			body: `{"data":{"terms":{"version":3,"text":"Be nice"}}}`,
+			cfg:  entities.ContentConfig{JSONPath: "data.terms.text"},
+			want: "Be nice",
+		},
+		{
+			name:    "invalid JSON",
+			body:    "<html>maintenance</html>",
+			cfg:     entities.ContentConfig{JSONPath: "data"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid selector",
+			body:    testPage,
+			cfg:     entities.ContentConfig{Selector: "p["},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Extract([]byte(tt.body), &tt.cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseSelector_Invalid(t *testing.T) {
+	for _, selector := range []string{"", "div >", "a,", "#", "p.", "[data-x='1'", "div ~ p", "a:hover"} {
+		if _, err := ParseSelector(selector); err == nil {
+			t.Errorf("Expected %q to be rejected", selector)
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	if Hash("a") == Hash("b") {
+		t.Errorf("Expected different hashes for different content")
+	}
+	if len(Hash("")) != 64 {
+		t.Errorf("Expected a hex encoded SHA-256")
+	}
+
+	changed := &entities.ContentConfig{Selector: "main"}
+	if ConfigHash(&entities.ContentConfig{Selector: "main"}) != ConfigHash(changed) {
+		t.Errorf("Expected equal settings to have the same hash")
+	}
+	changed.Ignore = []string{`\d+`}
+	if ConfigHash(&entities.ContentConfig{Selector: "main"}) == ConfigHash(changed) {
+		t.Errorf("Expected different settings to have different hashes")
+	}
+}
@@ -0,0 +1,158 @@
+package content
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// maxDiffCells bounds the line comparison table; larger changes are shown as a full replacement
+const maxDiffCells = 4_000_000
+
+// diffLine is one line of a diff: ' ' unchanged, '-' only in the old text, '+' only in the new text
+type diffLine struct {
+	kind     byte
+	text     string
+	old, new int // 0-based position in the old and new text
+}
+
+// Diff returns a unified diff of the lines of the old and new text, empty when they are equal
+func Diff(oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+
+	lines := diffLines(splitLines(oldText), splitLines(newText))
+
+	var out strings.Builder
+	out.WriteString("--- baseline\n+++ current\n")
+	for start := 0; start < len(lines); {
+		// Find the next change and the end of its hunk, merging changes whose context overlaps
+		first := start
+		for first < len(lines) && lines[first].kind == ' ' {
+			first++
+		}
+		if first == len(lines) {
+			break
+		}
+		end := first
+		for i := first; i < len(lines); i++ {
+			if lines[i].kind != ' ' {
+				end = i + 1
+			} else if i-end >= 2*diffContext {
+				break
+			}
+		}
+
+		hunkStart := max(first-diffContext, start)
+		hunkEnd := min(end+diffContext, len(lines))
+		writeHunk(&out, lines[hunkStart:hunkEnd])
+		start = hunkEnd
+	}
+	return out.String()
+}
+
+// writeHunk writes the header and lines of one hunk
+func writeHunk(out *strings.Builder, hunk []diffLine) {
+	oldCount, newCount := 0, 0
+	for _, line := range hunk {
+		if line.kind != '+' {
+			oldCount++
+		}
+		if line.kind != '-' {
+			newCount++
+		}
+	}
+
+	// Empty ranges start at the line before them, as in diff -u
+	oldStart, newStart := hunk[0].old, hunk[0].new
+	if oldCount > 0 {
+		oldStart++
+	}
+	if newCount > 0 {
+		newStart++
+	}
+
+	fmt.Fprintf(out, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+	for _, line := range hunk {
+		out.WriteByte(line.kind)
+		out.WriteString(line.text)
+		out.WriteByte('\n')
+	}
+}
+
+// diffLines aligns the old and new lines on their longest common subsequence
+func diffLines(oldLines, newLines []string) []diffLine {
+	// Unchanged lines at the start and end do not need the comparison table
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	var lines []diffLine
+	for i := 0; i < prefix; i++ {
+		lines = append(lines, diffLine{kind: ' ', text: oldLines[i], old: i, new: i})
+	}
+
+	a := oldLines[prefix : len(oldLines)-suffix]
+	b := newLines[prefix : len(newLines)-suffix]
+	i, j := 0, 0
+	if len(a)*len(b) <= maxDiffCells {
+		// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+		lcs := make([][]int, len(a)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(b)+1)
+		}
+		for i := len(a) - 1; i >= 0; i-- {
+			for j := len(b) - 1; j >= 0; j-- {
+				if a[i] == b[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+
+		for i < len(a) && j < len(b) {
+			switch {
+			case a[i] == b[j]:
+				lines = append(lines, diffLine{kind: ' ', text: a[i], old: prefix + i, new: prefix + j})
+				i++
+				j++
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lines = append(lines, diffLine{kind: '-', text: a[i], old: prefix + i, new: prefix + j})
+				i++
+			default:
+				lines = append(lines, diffLine{kind: '+', text: b[j], old: prefix + i, new: prefix + j})
+				j++
+			}
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, diffLine{kind: '-', text: a[i], old: prefix + i, new: prefix + j})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, diffLine{kind: '+', text: b[j], old: prefix + i, new: prefix + j})
+	}
+
+	for k := 0; k < suffix; k++ {
+		oldIndex, newIndex := len(oldLines)-suffix+k, len(newLines)-suffix+k
+		lines = append(lines, diffLine{kind: ' ', text: oldLines[oldIndex], old: oldIndex, new: newIndex})
+	}
+	return lines
+}
+
+// splitLines splits text into lines, an empty text has none
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
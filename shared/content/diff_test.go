@@ -0,0 +1,60 @@
+package content
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	if diff := Diff("same\n", "same\n"); diff != "" {
+		t.Errorf("Expected no diff for equal content, got %q", diff)
+	}
+
+	oldText := "<h1>Terms</h1>\n<p>You agree to the terms.</p>\n<p>Contact us.</p>"
+	newText := "<h1>Terms</h1>\n<p>Hacked by someone.</p>\n<p>Contact us.</p>"
+	want := `--- baseline
++++ current
+@@ -1,3 +1,3 @@
+ <h1>Terms</h1>
+-<p>You agree to the terms.</p>
++<p>Hacked by someone.</p>
+ <p>Contact us.</p>
+`
+	if diff := Diff(oldText, newText); diff != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, diff)
+	}
+}
+
+func TestDiff_Hunks(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 20; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	oldText := strings.Join(lines, "\n")
+
+	// Changes far apart get their own hunk with three lines of context
+	changed := append([]string(nil), lines...)
+	changed[1] = "line two"
+	changed[17] = "line eighteen"
+	diff := Diff(oldText, strings.Join(changed, "\n"))
+
+	if got := strings.Count(diff, "@@ -"); got != 2 {
+		t.Fatalf("Expected 2 hunks, got %d:\n%s", got, diff)
+	}
+	for _, header := range []string{"@@ -1,5 +1,5 @@", "@@ -15,6 +15,6 @@"} {
+		if !strings.Contains(diff, header) {
+			t.Errorf("Expected hunk %s in:\n%s", header, diff)
+		}
+	}
+
+	// Added and removed lines
+	diff = Diff("", "new")
+	if !strings.Contains(diff, "@@ -0,0 +1,1 @@\n+new\n") {
+		t.Errorf("Expected an added line, got:\n%s", diff)
+	}
+	diff = Diff("a\nb\nc", "a\nc")
+	if !strings.Contains(diff, "@@ -1,3 +1,2 @@\n a\n-b\n c\n") {
+		t.Errorf("Expected a removed line, got:\n%s", diff)
+	}
+}
@@ -10,7 +10,7 @@ type AlertRule struct {
 	UserID         int            `db:"user_id" json:"user_id"`
 	MonitorID      *string        `db:"monitor_id" json:"monitor_id,omitempty"`
 	Name           string         `db:"name" json:"name"`
-	TriggerType    string         `db:"trigger_type" json:"trigger_type"` // 'down', 'ssl_expiry', 'slow_response'
+	TriggerType    string         `db:"trigger_type" json:"trigger_type"` // 'down', 'ssl_expiry', 'slow_response', 'packet_loss'
 	ThresholdValue int            `db:"threshold_value" json:"threshold_value"`
 	Enabled        bool           `db:"enabled" json:"enabled"`
 	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// CertificateTarget is a standalone host:port endpoint whose certificate is tracked without a monitor,
+// e.g. SMTP, LDAPS or database TLS
+type CertificateTarget struct {
+	ID         string    `db:"id" json:"id"`
+	UserID     int       `db:"user_id" json:"user_id"`
+	Name       string    `db:"name" json:"name"`
+	Host       string    `db:"host" json:"host"`
+	Port       int       `db:"port" json:"port"`
+	ServerName string    `db:"server_name" json:"server_name,omitempty"` // SNI and hostname to verify, host when empty
+	StartTLS   string    `db:"starttls" json:"starttls,omitempty"`       // smtp or postgres to upgrade a plain connection, direct TLS when empty
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Certificate is the latest certificate seen on an HTTPS monitor or a certificate target
+type Certificate struct {
+	ID          string           `db:"id" json:"id"`
+	UserID      int              `db:"user_id" json:"user_id"`
+	MonitorID   *string          `db:"monitor_id" json:"monitor_id,omitempty"`
+	TargetID    *string          `db:"target_id" json:"target_id,omitempty"`
+	SourceName  string           `db:"source_name" json:"source_name"` // name of the monitor or target, read only
+	Host        string           `db:"host" json:"host"`
+	Port        int              `db:"port" json:"port"`
+	Subject     string           `db:"subject" json:"subject"`
+	Issuer      string           `db:"issuer" json:"issuer"`
+	SANs        pq.StringArray   `db:"sans" json:"sans,omitempty"`
+	NotBefore   *time.Time       `db:"not_before" json:"not_before,omitempty"`
+	NotAfter    *time.Time       `db:"not_after" json:"not_after,omitempty"`   // leaf certificate
+	ExpiresAt   *time.Time       `db:"expires_at" json:"expires_at,omitempty"` // earliest expiry in the chain
+	Fingerprint string           `db:"fingerprint" json:"fingerprint"`
+	Valid       bool             `db:"valid" json:"valid"`
+	Error       *string          `db:"error" json:"error,omitempty"` // connection or verification error
+	Details     *SSLCheckDetails `db:"details" json:"details,omitempty"`
+	CheckedAt   time.Time        `db:"checked_at" json:"checked_at"`
+	CreatedAt   time.Time        `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time        `db:"updated_at" json:"updated_at"`
+}
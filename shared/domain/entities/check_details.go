@@ -0,0 +1,243 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// CheckDetails represents the JSONB type-specific results of a monitor check
+type CheckDetails struct {
+	DNS  *DNSCheckDetails  `json:"dns,omitempty"`  // results of a 'dns' check
+	ICMP *ICMPCheckDetails `json:"icmp,omitempty"` // results of a 'ping'/'icmp' check
+	TCP  *TCPCheckDetails  `json:"tcp,omitempty"`  // results of a 'tcp' check
+	UDP  *UDPCheckDetails  `json:"udp,omitempty"`  // reply received by a 'udp' check
+	Push *PushCheckDetails `json:"push,omitempty"` // heartbeat received by a 'push' monitor
+	GRPC *GRPCCheckDetails `json:"grpc,omitempty"` // results of a 'grpc' check
+	Mail *MailCheckDetails `json:"mail,omitempty"` // session of an 'smtp', 'imap' or 'pop3' check
+
+	WebSocket *WebSocketCheckDetails `json:"websocket,omitempty"` // handshake and first message of a 'websocket' check
+
+	Database *DatabaseCheckDetails `json:"database,omitempty"` // results of a 'postgres', 'mysql' or 'redis' check
+	Docker   *DockerCheckDetails   `json:"docker,omitempty"`   // container state read by a 'docker' check
+
+	HTTP    *HTTPCheckDetails    `json:"http,omitempty"`    // redirect chain of an 'http' check
+	Content *ContentCheckDetails `json:"content,omitempty"` // change detection of an 'http' check
+
+	SSL *SSLCheckDetails `json:"ssl,omitempty"` // certificate chain and TLS posture of an HTTPS or mail monitor
+
+	ClientCertificate *ClientCertificateCheckDetails `json:"client_certificate,omitempty"` // client certificate presented for mutual TLS
+
+	Domain *DomainCheckDetails `json:"domain,omitempty"` // registration looked up by a 'domain' check
+
+	Transaction *TransactionCheckDetails `json:"transaction,omitempty"` // results of a 'transaction' check
+}
+
+// DNSCheckDetails holds the answers returned by a DNS record check
+type DNSCheckDetails struct {
+	RecordType string   `json:"record_type"`
+	Resolver   string   `json:"resolver"`
+	Answers    []string `json:"answers"`
+}
+
+// ICMPCheckDetails holds the round-trip statistics of a ping check
+type ICMPCheckDetails struct {
+	PacketsSent     int     `json:"packets_sent"`
+	PacketsReceived int     `json:"packets_received"`
+	PacketLoss      float64 `json:"packet_loss"` // percentage (0-100)
+	MinRTTMs        float64 `json:"min_rtt_ms"`
+	AvgRTTMs        float64 `json:"avg_rtt_ms"`
+	MaxRTTMs        float64 `json:"max_rtt_ms"`
+	JitterMs        float64 `json:"jitter_ms"`
+}
+
+// TCPCheckDetails holds the response read by a TCP send/expect check
+type TCPCheckDetails struct {
+	TLS      bool   `json:"tls"`
+	Response string `json:"response,omitempty"` // first bytes received from the server
+}
+
+// MailCheckDetails holds the session of a mail server check
+type MailCheckDetails struct {
+	Protocol      string `json:"protocol"` // smtp, imap or pop3
+	Banner        string `json:"banner,omitempty"`
+	TLS           bool   `json:"tls"`           // the session was encrypted, by STARTTLS or implicit TLS
+	Authenticated bool   `json:"authenticated"` // the login succeeded
+}
+
+// WebSocketCheckDetails holds the latencies and first message of a WebSocket check
+type WebSocketCheckDetails struct {
+	HandshakeMs    float64 `json:"handshake_ms"`
+	FirstMessageMs float64 `json:"first_message_ms,omitempty"` // from sending the message, or the end of the handshake, to the first message received
+	Subprotocol    string  `json:"subprotocol,omitempty"`      // accepted by the server
+	Message        string  `json:"message,omitempty"`          // first message received, truncated
+}
+
+// DockerCheckDetails holds the container state read from the Docker Engine API
+type DockerCheckDetails struct {
+	ContainerID  string     `json:"container_id"` // full ID, changes when the container is recreated
+	Image        string     `json:"image,omitempty"`
+	Status       string     `json:"status"`           // created, running, paused, restarting, removing, exited or dead
+	Health       string     `json:"health,omitempty"` // starting, healthy or unhealthy, empty without a healthcheck
+	RestartCount int        `json:"restart_count"`    // restarts by the restart policy, compared with the previous check
+	ExitCode     int        `json:"exit_code,omitempty"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`
+}
+
+// UDPCheckDetails holds the reply read by a UDP check
+type UDPCheckDetails struct {
+	Preset   string `json:"preset,omitempty"`
+	Response string `json:"response,omitempty"` // first bytes of the reply, hex encoded when they are not text
+	Encoding string `json:"encoding,omitempty"` // 'text' or 'hex'
+	Size     int    `json:"size"`               // size of the reply in bytes
+}
+
+// HTTPCheckDetails holds the redirects seen by an HTTP check
+type HTTPCheckDetails struct {
+	Redirects []HTTPRedirect `json:"redirects"` // every request of the check in order, the last one answered the check
+	FinalURL  string         `json:"final_url"`
+}
+
+// HTTPRedirect is one request of a redirect chain
+type HTTPRedirect struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
+	Location   string `json:"location,omitempty"` // resolved Location header of a redirect response
+}
+
+// ContentCheckDetails holds the outcome of content change detection
+type ContentCheckDetails struct {
+	Hash         string `json:"hash"`          // SHA-256 of the selected content
+	BaselineHash string `json:"baseline_hash"` // SHA-256 of the accepted baseline
+	Changed      bool   `json:"changed"`
+	Size         int    `json:"size"`            // bytes of selected content
+	Error        string `json:"error,omitempty"` // why the whole body was compared instead of the selection
+}
+
+// PushCheckDetails holds the data sent with a heartbeat
+type PushCheckDetails struct {
+	Message string `json:"message,omitempty"`
+}
+
+// GRPCCheckDetails holds the outcome of a gRPC health check
+type GRPCCheckDetails struct {
+	Status string `json:"status,omitempty"` // SERVING, NOT_SERVING, UNKNOWN or SERVICE_UNKNOWN
+	Code   string `json:"code,omitempty"`   // gRPC status code when the RPC failed
+}
+
+// DatabaseCheckDetails holds the latency and result of a database probe
+type DatabaseCheckDetails struct {
+	ConnectMs float64 `json:"connect_ms"`
+	QueryMs   float64 `json:"query_ms"`
+	Result    string  `json:"result,omitempty"` // first row or reply of the probe query, truncated
+}
+
+// ClientCertificateCheckDetails identifies the client certificate presented during a check
+// so that ssl_expiry rules can warn before it expires
+type ClientCertificateCheckDetails struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Fingerprint string    `json:"fingerprint"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// DomainCheckDetails holds the registration data of a domain
+type DomainCheckDetails struct {
+	Domain    string     `json:"domain"` // registrable domain that was looked up
+	Registrar string     `json:"registrar,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Source    string     `json:"source,omitempty"` // rdap or whois
+}
+
+// SSLCheckDetails holds the presented certificate chain and the negotiated TLS parameters
+type SSLCheckDetails struct {
+	Fingerprint         string               `json:"fingerprint"`                    // SHA-256 of the leaf certificate
+	PreviousFingerprint string               `json:"previous_fingerprint,omitempty"` // set when the leaf changed unexpectedly since the last check
+	HostnameMatch       bool                 `json:"hostname_match"`
+	TLSVersion          string               `json:"tls_version"`
+	CipherSuite         string               `json:"cipher_suite"`
+	Weaknesses          []string             `json:"weaknesses,omitempty"` // e.g. legacy protocol, short key, SHA-1 signature
+	Error               string               `json:"error,omitempty"`      // verification error when the chain is not trusted
+	Chain               []CertificateDetails `json:"chain"`                // leaf first
+	Revocation          *RevocationDetails   `json:"revocation,omitempty"`
+}
+
+// RevocationDetails holds the OCSP or CRL status of the leaf certificate
+type RevocationDetails struct {
+	Status     string     `json:"status"`           // good, revoked or unknown
+	Source     string     `json:"source,omitempty"` // ocsp_staple, ocsp or crl
+	ResponseMs float64    `json:"response_ms"`      // time spent querying the responder or downloading the CRL
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	Reason     string     `json:"reason,omitempty"`
+	Error      string     `json:"error,omitempty"` // why the status is unknown
+}
+
+// CertificateDetails describes one certificate of a presented chain
+type CertificateDetails struct {
+	Subject            string    `json:"subject"`
+	Issuer             string    `json:"issuer"`
+	SANs               []string  `json:"sans,omitempty"`
+	NotBefore          time.Time `json:"not_before"`
+	NotAfter           time.Time `json:"not_after"`
+	KeyType            string    `json:"key_type"` // RSA, ECDSA or Ed25519
+	KeyBits            int       `json:"key_bits"`
+	SignatureAlgorithm string    `json:"signature_algorithm"`
+	Fingerprint        string    `json:"fingerprint"` // SHA-256
+}
+
+// TransactionCheckDetails holds the per-step results of a transaction check
+type TransactionCheckDetails struct {
+	Steps      []TransactionStepDetails `json:"steps"`
+	FailedStep int                      `json:"failed_step,omitempty"` // 1-based index of the failing step, 0 if all passed
+}
+
+// TransactionStepDetails holds the result of a single transaction step
+type TransactionStepDetails struct {
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	StatusCode     int    `json:"status_code,omitempty"`
+	ResponseTimeMs int64  `json:"response_time_ms"`
+	Success        bool   `json:"success"`
+	Error          string `json:"error,omitempty"`
+}
+
+// Value implements the driver.Valuer interface for database serialization
+func (d CheckDetails) Value() (driver.Value, error) {
+	return json.Marshal(d)
+}
+
+// Scan implements the sql.Scanner interface for database deserialization
+func (d *CheckDetails) Scan(value interface{}) error {
+	if value == nil {
+		*d = CheckDetails{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to scan CheckDetails: value is not []byte")
+	}
+
+	return json.Unmarshal(bytes, d)
+}
+
+// Value implements the driver.Valuer interface for database serialization
+func (d SSLCheckDetails) Value() (driver.Value, error) {
+	return json.Marshal(d)
+}
+
+// Scan implements the sql.Scanner interface for database deserialization
+func (d *SSLCheckDetails) Scan(value interface{}) error {
+	if value == nil {
+		*d = SSLCheckDetails{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to scan SSLCheckDetails: value is not []byte")
+	}
+
+	return json.Unmarshal(bytes, d)
+}
@@ -0,0 +1,47 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// CheckEvidence holds the response received by an HTTP check, kept for failed checks and a sample of
+// successful ones so that a failure can be investigated without reproducing it
+type CheckEvidence struct {
+	CheckID       string           `db:"check_id" json:"check_id"`
+	MonitorID     string           `db:"monitor_id" json:"monitor_id"`
+	RemoteIP      string           `db:"remote_ip" json:"remote_ip,omitempty"` // address that answered, the proxy when one is used
+	Headers       EvidenceHeaders  `db:"headers" json:"headers"`
+	BodySnippet   string           `db:"body_snippet" json:"body_snippet"`
+	BodyTruncated bool             `db:"body_truncated" json:"body_truncated"` // the body was longer than the snippet
+	TLS           *SSLCheckDetails `db:"tls" json:"tls,omitempty"`             // negotiated connection of an HTTPS check
+	CreatedAt     time.Time        `db:"created_at" json:"created_at"`
+}
+
+// EvidenceHeaders represents the JSONB response headers of a check
+type EvidenceHeaders map[string][]string
+
+// Value implements the driver.Valuer interface for database serialization
+func (h EvidenceHeaders) Value() (driver.Value, error) {
+	if h == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(h)
+}
+
+// Scan implements the sql.Scanner interface for database deserialization
+func (h *EvidenceHeaders) Scan(value interface{}) error {
+	if value == nil {
+		*h = EvidenceHeaders{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to scan EvidenceHeaders: value is not []byte")
+	}
+
+	return json.Unmarshal(bytes, h)
+}
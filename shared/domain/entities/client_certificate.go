@@ -0,0 +1,25 @@
+package entities
+
+import "time"
+
+// ClientCertificate is a certificate and private key presented by monitors to servers that require mutual TLS
+// Only the metadata is stored in plain text; the PEM data is kept encrypted in EncryptedSecrets
+type ClientCertificate struct {
+	ID               string    `db:"id" json:"id"`
+	UserID           int       `db:"user_id" json:"user_id"`
+	Name             string    `db:"name" json:"name"`
+	Subject          string    `db:"subject" json:"subject"`
+	Issuer           string    `db:"issuer" json:"issuer"`
+	Fingerprint      string    `db:"fingerprint" json:"fingerprint"` // SHA-256 of the certificate
+	NotBefore        time.Time `db:"not_before" json:"not_before"`
+	NotAfter         time.Time `db:"not_after" json:"not_after"`
+	EncryptedSecrets string    `db:"encrypted_secrets" json:"-"` // encrypted ClientCertificateSecrets, never returned by the API
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// ClientCertificateSecrets holds the PEM encoded certificate chain and private key of a client certificate
+type ClientCertificateSecrets struct {
+	Certificate string `json:"certificate"`
+	PrivateKey  string `json:"private_key"`
+}
@@ -0,0 +1,18 @@
+package entities
+
+import "time"
+
+// ContentBaseline holds the accepted and the latest content of an HTTP monitor with change detection
+// The content has changed while CurrentHash differs from BaselineHash
+type ContentBaseline struct {
+	MonitorID       string    `db:"monitor_id" json:"monitor_id"`
+	ConfigHash      string    `db:"config_hash" json:"-"` // extraction settings the baseline was taken with
+	BaselineHash    string    `db:"baseline_hash" json:"baseline_hash"`
+	BaselineContent string    `db:"baseline_content" json:"baseline_content"`
+	AcceptedAt      time.Time `db:"accepted_at" json:"accepted_at"`
+	CurrentHash     string    `db:"current_hash" json:"current_hash"`
+	CurrentContent  string    `db:"current_content" json:"current_content"`
+	CheckedAt       time.Time `db:"checked_at" json:"checked_at"`
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+}
@@ -15,6 +15,7 @@ type Incident struct {
 	ResolvedAt   sql.NullTime `db:"resolved_at" json:"resolved_at,omitempty" swaggertype:"string"`
 	Status       string       `db:"status" json:"status"` // 'open', 'resolved'
 	TriggerValue string       `db:"trigger_value" json:"trigger_value,omitempty"`
+	CheckID      *string      `db:"check_id" json:"check_id,omitempty"` // check that opened the incident, nil when not opened by a check
 	NotifiedAt   sql.NullTime `db:"notified_at" json:"notified_at,omitempty" swaggertype:"string"`
 	CreatedAt    time.Time    `db:"created_at" json:"created_at"`
 	
@@ -21,6 +21,10 @@ type Monitor struct {
 	SSLAlertDays        int            `db:"ssl_alert_days" json:"ssl_alert_days"`    // days before expiry to alert
 	Tags                pq.StringArray `db:"tags" json:"tags,omitempty"`              // tags for filtering/organization
 	ExpectedStatusCodes pq.Int64Array  `db:"expected_status_codes" json:"expected_status_codes,omitempty"` // expected HTTP status codes
+	Config              MonitorConfig  `db:"config" json:"config"`                    // type-specific settings
+	PushToken           *string        `db:"push_token" json:"push_token,omitempty"`  // token of the heartbeat URL, 'push' monitors only
+	EncryptedSecrets    *string        `db:"encrypted_secrets" json:"-"`              // encrypted MonitorSecrets, never returned by the API
+	ClientCertificateID *string        `db:"client_certificate_id" json:"client_certificate_id,omitempty"` // presented for mutual TLS by 'http', 'transaction', 'grpc' and TLS 'tcp' monitors
 	LastCheckedAt       *time.Time     `db:"last_checked_at" json:"last_checked_at,omitempty"`
 	CreatedAt           time.Time      `db:"created_at" json:"created_at"`
 	UpdatedAt           time.Time      `db:"updated_at" json:"updated_at"`
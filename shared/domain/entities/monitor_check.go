@@ -14,8 +14,14 @@ type MonitorCheck struct {
 	CheckedAt      time.Time      `db:"checked_at" json:"checked_at"`
 	StatusCode     sql.NullInt64  `db:"status_code" json:"status_code,omitempty" swaggertype:"integer"`
 	ResponseTimeMs sql.NullInt64  `db:"response_time_ms" json:"response_time_ms,omitempty" swaggertype:"integer"`
+	DNSLookupMs    sql.NullInt64  `db:"dns_lookup_ms" json:"dns_lookup_ms,omitempty" swaggertype:"integer"`       // HTTP timing breakdown
+	TCPConnectMs   sql.NullInt64  `db:"tcp_connect_ms" json:"tcp_connect_ms,omitempty" swaggertype:"integer"`     // HTTP timing breakdown
+	TLSHandshakeMs sql.NullInt64  `db:"tls_handshake_ms" json:"tls_handshake_ms,omitempty" swaggertype:"integer"` // HTTP timing breakdown
+	TTFBMs         sql.NullInt64  `db:"ttfb_ms" json:"ttfb_ms,omitempty" swaggertype:"integer"`                   // HTTP timing breakdown
+	TransferMs     sql.NullInt64  `db:"transfer_ms" json:"transfer_ms,omitempty" swaggertype:"integer"`           // HTTP timing breakdown
 	SSLValid       sql.NullBool   `db:"ssl_valid" json:"ssl_valid,omitempty" swaggertype:"boolean"`
 	SSLExpiresAt   sql.NullTime   `db:"ssl_expires_at" json:"ssl_expires_at,omitempty" swaggertype:"string"`
 	ErrorMessage   sql.NullString `db:"error_message" json:"error_message,omitempty" swaggertype:"string"`
+	Details        *CheckDetails  `db:"details" json:"details,omitempty"` // type-specific check results
 	Success        bool           `db:"success" json:"success"`
 }
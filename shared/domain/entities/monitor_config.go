@@ -0,0 +1,576 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// MaskedSecret replaces secret values when a monitor is returned by the API
+const MaskedSecret = "********"
+
+// MonitorConfig represents the JSONB type-specific configuration for a monitor
+type MonitorConfig struct {
+	DNS  *DNSConfig  `json:"dns,omitempty"`  // settings for 'dns' monitors
+	ICMP *ICMPConfig `json:"icmp,omitempty"` // settings for 'ping'/'icmp' monitors
+	HTTP *HTTPConfig `json:"http,omitempty"` // request settings for 'http' monitors
+	TCP  *TCPConfig  `json:"tcp,omitempty"`  // send/expect settings for 'tcp' monitors
+	UDP  *UDPConfig  `json:"udp,omitempty"`  // datagram and expected reply of 'udp' monitors
+	Push *PushConfig `json:"push,omitempty"` // heartbeat settings for 'push' monitors
+	GRPC *GRPCConfig `json:"grpc,omitempty"` // health check settings for 'grpc' monitors
+	Mail *MailConfig `json:"mail,omitempty"` // TLS and login of 'smtp', 'imap' and 'pop3' monitors
+
+	WebSocket *WebSocketConfig `json:"websocket,omitempty"` // handshake and message exchange of 'websocket' monitors
+
+	Database *DatabaseConfig `json:"database,omitempty"` // probe settings for 'postgres', 'mysql' and 'redis' monitors
+	Docker   *DockerConfig   `json:"docker,omitempty"`   // Engine API endpoint of 'docker' monitors
+
+	Transaction *TransactionConfig `json:"transaction,omitempty"` // steps of 'transaction' monitors
+
+	Connection *ConnectionConfig `json:"connection,omitempty"` // TLS and addressing overrides for 'http' and 'transaction' monitors
+	Proxy      *ProxyConfig      `json:"proxy,omitempty"`      // proxy dialed by 'http', 'transaction' and 'tcp' monitors
+
+	Redirect *RedirectConfig `json:"redirect,omitempty"` // redirect policy of 'http' monitors
+	Content  *ContentConfig  `json:"content,omitempty"`  // change detection of 'http' monitors
+}
+
+// DNSConfig holds the settings for a DNS record monitor
+type DNSConfig struct {
+	RecordType     string   `json:"record_type"`               // A, AAAA, CNAME, MX, TXT or NS
+	Resolver       string   `json:"resolver,omitempty"`        // host or host:port, system resolver when empty
+	ExpectedValues []string `json:"expected_values,omitempty"` // answers the lookup must return
+	MatchMode      string   `json:"match_mode,omitempty"`      // 'contains' (default) or 'exact'
+}
+
+// ICMPConfig holds the settings for a ping monitor
+type ICMPConfig struct {
+	Count int `json:"count,omitempty"` // echo requests sent per check, 3 when unset
+}
+
+// ConnectionConfig overrides how the checker connects to the monitor host
+// ServerName and ResolveTo only apply to the host of the monitor URL, not to redirects or steps on other hosts
+type ConnectionConfig struct {
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"` // accept invalid or self-signed certificates
+	MinTLSVersion      string `json:"min_tls_version,omitempty"`      // '1.0', '1.1', '1.2' or '1.3', Go's default when empty
+	ServerName         string `json:"server_name,omitempty"`          // sent as SNI and Host header instead of the URL host
+	ResolveTo          string `json:"resolve_to,omitempty"`           // IP address to connect to instead of resolving the URL host, like curl --resolve
+	IPFamily           string `json:"ip_family,omitempty"`            // 'ipv4' or 'ipv6', either when empty
+}
+
+// ProxyConfig routes the connections of a monitor through an HTTP CONNECT or SOCKS5 proxy
+type ProxyConfig struct {
+	Type     string `json:"type"`               // 'http' (CONNECT) or 'socks5'
+	Address  string `json:"address"`            // host:port of the proxy
+	Username string `json:"username,omitempty"` // proxy credentials, optional
+	Password string `json:"password,omitempty"` // moved to MonitorSecrets before the monitor is stored, MaskedSecret when set
+}
+
+// RedirectConfig sets how an HTTP monitor follows redirects and where it must end up
+type RedirectConfig struct {
+	Follow           *bool  `json:"follow,omitempty"`             // follow redirects, true when omitted
+	MaxRedirects     int    `json:"max_redirects,omitempty"`      // redirects followed before the check fails, 5 when 0
+	ExpectedFinalURL string `json:"expected_final_url,omitempty"` // URL of the last request, after redirects
+	ExpectedLocation string `json:"expected_location,omitempty"`  // Location header of the response when redirects are not followed
+}
+
+// ContentConfig enables change detection on an HTTP monitor: the selected content is hashed and
+// compared with the last accepted baseline
+type ContentConfig struct {
+	Selector string   `json:"selector,omitempty"`  // CSS selector of the HTML elements to compare
+	JSONPath string   `json:"json_path,omitempty"` // path of the JSON value to compare, e.g. "data.terms"
+	Ignore   []string `json:"ignore,omitempty"`    // regexes whose matches are removed first (dates, nonces, ...)
+}
+
+// TCPConfig holds the optional send/expect exchange of a TCP monitor
+type TCPConfig struct {
+	Send      string `json:"send,omitempty"`       // payload written after connecting, e.g. "PING\r\n"
+	Expect    string `json:"expect,omitempty"`     // the response must contain this string or match this regex
+	MatchMode string `json:"match_mode,omitempty"` // 'contains' (default) or 'regex'
+}
+
+// WebSocketConfig holds the upgrade request and optional message exchange of a WebSocket monitor
+type WebSocketConfig struct {
+	Headers     map[string]string `json:"headers,omitempty"`     // sent with the upgrade request, e.g. Origin or Authorization
+	Subprotocol string            `json:"subprotocol,omitempty"` // requested in Sec-WebSocket-Protocol, the server must accept it
+	Send        string            `json:"send,omitempty"`        // text message sent after the handshake
+	Expect      string            `json:"expect,omitempty"`      // the first message received must contain this string or match this regex
+	MatchMode   string            `json:"match_mode,omitempty"`  // 'contains' (default) or 'regex'
+}
+
+// UDPConfig holds the datagram a UDP monitor sends and the reply it expects
+// A preset builds the datagram and checks that the reply answers it, Expect is matched in addition
+type UDPConfig struct {
+	Preset    string `json:"preset,omitempty"`     // 'dns', 'ntp' or 'snmp', replaces Payload
+	Payload   string `json:"payload,omitempty"`    // datagram sent to the target
+	Encoding  string `json:"encoding,omitempty"`   // 'text' (default) or 'hex', how Payload is written
+	Expect    string `json:"expect,omitempty"`     // the reply must contain this string, match this regex or contain these hex bytes
+	MatchMode string `json:"match_mode,omitempty"` // 'contains' (default), 'regex' or 'hex'
+	Community string `json:"community,omitempty"`  // community of the 'snmp' preset, 'public' when unset; moved to MonitorSecrets, MaskedSecret when set
+}
+
+// MailConfig holds the session settings of a mail server monitor
+type MailConfig struct {
+	TLS                string `json:"tls,omitempty"`                  // 'starttls' or 'implicit' (SMTPS, IMAPS, POP3S), plaintext when empty
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"` // accept invalid or self-signed certificates
+	Username           string `json:"username,omitempty"`             // logs in when set, requires TLS
+	Password           string `json:"password,omitempty"`             // moved to MonitorSecrets before the monitor is stored, MaskedSecret when set
+	ExpectBanner       string `json:"expect_banner,omitempty"`        // the greeting must contain this string
+}
+
+// DockerConfig holds the Docker Engine API endpoint a Docker monitor inspects its container through
+type DockerConfig struct {
+	Endpoint           string `json:"endpoint,omitempty"`             // unix:///path/to/docker.sock or tcp://host:port, unix:///var/run/docker.sock when empty; sockets must be enabled on the worker
+	TLS                bool   `json:"tls,omitempty"`                  // connect to a tcp:// endpoint with TLS, usually on port 2376
+	CACertificate      string `json:"ca_certificate,omitempty"`       // PEM certificates trusted for the daemon, system roots when empty
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"` // accept an invalid or self-signed daemon certificate
+}
+
+// PushConfig holds the settings for a push (heartbeat) monitor
+type PushConfig struct {
+	GracePeriod int `json:"grace_period,omitempty"` // seconds tolerated after the check interval, 60 when unset
+}
+
+// GRPCConfig holds the settings for a gRPC health check monitor
+type GRPCConfig struct {
+	Service  string            `json:"service,omitempty"`  // service name sent in the health check, the whole server when empty
+	TLS      bool              `json:"tls,omitempty"`      // connect with TLS instead of plaintext
+	Metadata map[string]string `json:"metadata,omitempty"` // metadata headers sent with the RPC
+}
+
+// DatabaseConfig holds the probe settings for a database monitor
+type DatabaseConfig struct {
+	DSN        string              `json:"dsn,omitempty"`   // write-only: moved to MonitorSecrets before the monitor is stored
+	Query      string              `json:"query,omitempty"` // SQL query or Redis command, SELECT 1 / PING when empty
+	Assertions []DatabaseAssertion `json:"assertions,omitempty"`
+}
+
+// DatabaseAssertion is a single check evaluated against the result of the probe query
+type DatabaseAssertion struct {
+	Property string `json:"property,omitempty"` // column of the first row, or Redis INFO field; first column / whole reply when empty
+	Operator string `json:"operator"`           // equals, not_equals, greater_than, less_than, contains, exists, matches
+	Value    string `json:"value,omitempty"`
+}
+
+// MonitorSecrets holds the credentials of a monitor, stored encrypted in the encrypted_secrets column
+type MonitorSecrets struct {
+	DSN           string `json:"dsn,omitempty"`            // connection string of a database monitor
+	MailPassword  string `json:"mail_password,omitempty"`  // login password of a mail server monitor
+	ProxyPassword string `json:"proxy_password,omitempty"` // password of the monitor proxy
+	SNMPCommunity string `json:"snmp_community,omitempty"` // community of a UDP monitor with the 'snmp' preset
+
+	HTTPPassword string            `json:"http_password,omitempty"` // basic auth password of an HTTP monitor
+	HTTPToken    string            `json:"http_token,omitempty"`    // bearer token of an HTTP monitor
+	HTTPHeaders  map[string]string `json:"http_headers,omitempty"`  // credential-like request headers of an HTTP monitor, see IsSensitiveHeader
+}
+
+// IsEmpty reports whether the monitor has no secret to store
+func (s MonitorSecrets) IsEmpty() bool {
+	return s.DSN == "" && s.MailPassword == "" && s.ProxyPassword == "" && s.SNMPCommunity == "" &&
+		s.HTTPPassword == "" && s.HTTPToken == "" && len(s.HTTPHeaders) == 0
+}
+
+// HTTPConfig holds the request settings for an HTTP monitor
+type HTTPConfig struct {
+	Method      string            `json:"method,omitempty"`       // GET when empty
+	Headers     map[string]string `json:"headers,omitempty"`      // custom request headers, credential-like values are moved to MonitorSecrets for 'http' monitors
+	Body        string            `json:"body,omitempty"`         // request body
+	ContentType string            `json:"content_type,omitempty"` // Content-Type of the body
+	Auth        *HTTPAuth         `json:"auth,omitempty"`         // credentials, moved to MonitorSecrets for 'http' monitors
+	Assertions  []HTTPAssertion   `json:"assertions,omitempty"`   // checks on the response, all must pass
+}
+
+// HTTPAuth holds the credentials sent with an HTTP monitor request
+type HTTPAuth struct {
+	Type     string `json:"type"`               // 'basic' or 'bearer'
+	Username string `json:"username,omitempty"` // basic auth
+	Password string `json:"password,omitempty"` // basic auth
+	Token    string `json:"token,omitempty"`    // bearer auth
+}
+
+// HTTPAssertion is a single check evaluated against an HTTP response
+type HTTPAssertion struct {
+	Source   string `json:"source"`             // 'json', 'header' or 'content_length'
+	Property string `json:"property,omitempty"` // GJSON path for 'json', header name for 'header'
+	Operator string `json:"operator"`           // equals, not_equals, greater_than, less_than, contains, exists, matches
+	Value    string `json:"value,omitempty"`    // expected value, regex for 'matches'
+}
+
+// TransactionConfig holds the ordered HTTP requests of a transaction monitor
+// Values extracted by a step and secrets are available to later steps as {{name}}
+type TransactionConfig struct {
+	Steps   []TransactionStep `json:"steps"`
+	Secrets map[string]string `json:"secrets,omitempty"` // e.g. login passwords, masked by the API
+}
+
+// TransactionStep is a single HTTP request of a transaction monitor
+type TransactionStep struct {
+	Name                string               `json:"name"`
+	URL                 string               `json:"url,omitempty"` // absolute, or relative to the monitor URL; the monitor URL when empty
+	HTTPConfig                               // method, headers, body, auth and assertions
+	ExpectedStatusCodes []int64              `json:"expected_status_codes,omitempty"` // 2xx/3xx when empty
+	Keyword             string               `json:"keyword,omitempty"`
+	Extract             []TransactionExtract `json:"extract,omitempty"`
+}
+
+// TransactionExtract stores a value from a step response in a variable
+type TransactionExtract struct {
+	Variable string `json:"variable"` // referenced as {{variable}} in later steps
+	Source   string `json:"source"`   // 'json', 'header' or 'cookie'
+	Property string `json:"property"` // GJSON path, header name or cookie name
+}
+
+// ErrSecretNotStored is returned by MoveSecrets and RestoreSecrets for a masked value without a stored secret,
+// e.g. when the stored secrets were encrypted with a previous key
+var ErrSecretNotStored = errors.New("masked credentials have no stored value, enter them again")
+
+// MoveSecrets moves the credentials of the config into secrets, keeping the value from previous
+// where the config holds MaskedSecret. The config keeps MaskedSecret in place of each stored
+// value, so that the API reports it as set without returning it.
+func (c *MonitorConfig) MoveSecrets(secrets *MonitorSecrets, previous MonitorSecrets) error {
+	var err error
+	if c.Proxy != nil {
+		if secrets.ProxyPassword, err = moveSecret(&c.Proxy.Password, previous.ProxyPassword); err != nil {
+			return err
+		}
+	}
+	if c.UDP != nil {
+		if secrets.SNMPCommunity, err = moveSecret(&c.UDP.Community, previous.SNMPCommunity); err != nil {
+			return err
+		}
+	}
+	if c.Mail != nil {
+		if secrets.MailPassword, err = moveSecret(&c.Mail.Password, previous.MailPassword); err != nil {
+			return err
+		}
+	}
+	if c.HTTP != nil {
+		if c.HTTP.Auth != nil {
+			if secrets.HTTPPassword, err = moveSecret(&c.HTTP.Auth.Password, previous.HTTPPassword); err != nil {
+				return err
+			}
+			if secrets.HTTPToken, err = moveSecret(&c.HTTP.Auth.Token, previous.HTTPToken); err != nil {
+				return err
+			}
+		}
+		for name, value := range c.HTTP.Headers {
+			if !IsSensitiveHeader(name) {
+				continue
+			}
+			secret, err := moveSecret(&value, previous.HTTPHeaders[name])
+			if err != nil {
+				return err
+			}
+			c.HTTP.Headers[name] = value
+			if secret != "" {
+				if secrets.HTTPHeaders == nil {
+					secrets.HTTPHeaders = make(map[string]string)
+				}
+				secrets.HTTPHeaders[name] = secret
+			}
+		}
+	}
+	return nil
+}
+
+// WithSecrets returns a copy of the request settings of an 'http' monitor with the credentials from
+// its decrypted secrets, the stored config only holds MaskedSecret in place of them
+func (c HTTPConfig) WithSecrets(secrets *MonitorSecrets) HTTPConfig {
+	if secrets == nil {
+		secrets = &MonitorSecrets{}
+	}
+	if c.Auth != nil {
+		auth := *c.Auth
+		auth.Password = secrets.HTTPPassword
+		auth.Token = secrets.HTTPToken
+		c.Auth = &auth
+	}
+	if len(c.Headers) > 0 {
+		headers := make(map[string]string, len(c.Headers))
+		for name, value := range c.Headers {
+			if IsSensitiveHeader(name) {
+				value = secrets.HTTPHeaders[name]
+			}
+			headers[name] = value
+		}
+		c.Headers = headers
+	}
+	return c
+}
+
+// moveSecret replaces a secret of the config with MaskedSecret, or with an empty string when it was
+// removed, and returns the value to store
+func moveSecret(value *string, previous string) (string, error) {
+	secret := *value
+	if secret == MaskedSecret {
+		if previous == "" {
+			return "", ErrSecretNotStored
+		}
+		secret = previous
+	}
+	*value = ""
+	if secret != "" {
+		*value = MaskedSecret
+	}
+	return secret, nil
+}
+
+// IsSensitiveHeader reports whether a request header usually carries credentials
+func IsSensitiveHeader(name string) bool {
+	name = strings.ToLower(name)
+	for _, marker := range []string{"auth", "token", "key", "secret", "cookie", "password"} {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// Masked returns a copy of the config with secret values replaced by MaskedSecret
+func (c MonitorConfig) Masked() MonitorConfig {
+	if c.HTTP != nil {
+		httpConfig := c.HTTP.masked()
+		c.HTTP = &httpConfig
+	}
+
+	if c.GRPC != nil {
+		grpcConfig := *c.GRPC
+		grpcConfig.Metadata = maskSensitiveHeaders(grpcConfig.Metadata)
+		c.GRPC = &grpcConfig
+	}
+
+	if c.Proxy != nil && c.Proxy.Password != "" {
+		proxyConfig := *c.Proxy
+		proxyConfig.Password = MaskedSecret
+		c.Proxy = &proxyConfig
+	}
+
+	if c.UDP != nil && c.UDP.Community != "" {
+		udpConfig := *c.UDP
+		udpConfig.Community = MaskedSecret
+		c.UDP = &udpConfig
+	}
+
+	if c.Mail != nil && c.Mail.Password != "" {
+		mailConfig := *c.Mail
+		mailConfig.Password = MaskedSecret
+		c.Mail = &mailConfig
+	}
+
+	if c.WebSocket != nil {
+		webSocketConfig := *c.WebSocket
+		webSocketConfig.Headers = maskSensitiveHeaders(webSocketConfig.Headers)
+		c.WebSocket = &webSocketConfig
+	}
+
+	if c.Transaction != nil {
+		transaction := *c.Transaction
+		transaction.Secrets = maskValues(transaction.Secrets)
+		steps := make([]TransactionStep, len(transaction.Steps))
+		for i, step := range transaction.Steps {
+			step.HTTPConfig = step.HTTPConfig.masked()
+			steps[i] = step
+		}
+		transaction.Steps = steps
+		c.Transaction = &transaction
+	}
+
+	return c
+}
+
+// RestoreSecrets replaces masked values with the secrets from the previous config,
+// so that a masked config returned by the API can be sent back unchanged on update
+// It returns ErrSecretNotStored for a masked transaction value that has no previous value.
+// Secrets stored in MonitorSecrets, e.g. the credentials of 'http' monitors, are restored by MoveSecrets.
+func (c *MonitorConfig) RestoreSecrets(previous MonitorConfig) error {
+	if c.GRPC != nil && previous.GRPC != nil {
+		for name, value := range c.GRPC.Metadata {
+			if value == MaskedSecret {
+				c.GRPC.Metadata[name] = previous.GRPC.Metadata[name]
+			}
+		}
+	}
+
+	if c.WebSocket != nil && previous.WebSocket != nil {
+		for name, value := range c.WebSocket.Headers {
+			if value == MaskedSecret {
+				c.WebSocket.Headers[name] = previous.WebSocket.Headers[name]
+			}
+		}
+	}
+
+	if c.Transaction != nil {
+		var previousTransaction TransactionConfig
+		if previous.Transaction != nil {
+			previousTransaction = *previous.Transaction
+		}
+		for name, value := range c.Transaction.Secrets {
+			if value == MaskedSecret {
+				if previousTransaction.Secrets[name] == "" {
+					return ErrSecretNotStored
+				}
+				c.Transaction.Secrets[name] = previousTransaction.Secrets[name]
+			}
+		}
+		// Steps are matched by key, so that adding, removing or reordering steps does not
+		// hand the credentials of a step to another request
+		previousSteps := transactionStepsByKey(previousTransaction.Steps)
+		for i := range c.Transaction.Steps {
+			step := &c.Transaction.Steps[i]
+			previousStep, ok := previousSteps[step.key()]
+			if !ok {
+				if step.HTTPConfig.hasMaskedSecrets() {
+					return ErrSecretNotStored
+				}
+				continue
+			}
+			if err := step.HTTPConfig.restoreSecrets(previousStep.HTTPConfig); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// key identifies a step across updates: its name, or its method and URL when it has no name
+func (s TransactionStep) key() string {
+	if s.Name != "" {
+		return "name:" + s.Name
+	}
+	method := strings.ToUpper(s.Method)
+	if method == "" {
+		method = "GET"
+	}
+	return "request:" + method + " " + s.URL
+}
+
+// transactionStepsByKey indexes steps by key, leaving out keys shared by several steps
+func transactionStepsByKey(steps []TransactionStep) map[string]TransactionStep {
+	byKey := make(map[string]TransactionStep, len(steps))
+	duplicates := make(map[string]bool)
+	for _, step := range steps {
+		key := step.key()
+		if _, ok := byKey[key]; ok {
+			duplicates[key] = true
+		}
+		byKey[key] = step
+	}
+	for key := range duplicates {
+		delete(byKey, key)
+	}
+	return byKey
+}
+
+// masked returns a copy of the request settings with credentials replaced by MaskedSecret
+func (c HTTPConfig) masked() HTTPConfig {
+	c.Headers = maskSensitiveHeaders(c.Headers)
+	if c.Auth != nil {
+		auth := *c.Auth
+		if auth.Password != "" {
+			auth.Password = MaskedSecret
+		}
+		if auth.Token != "" {
+			auth.Token = MaskedSecret
+		}
+		c.Auth = &auth
+	}
+	return c
+}
+
+// restoreSecrets replaces masked credentials with the values from previous, it returns
+// ErrSecretNotStored for a masked value that previous does not hold
+func (c *HTTPConfig) restoreSecrets(previous HTTPConfig) error {
+	for name, value := range c.Headers {
+		restored, err := restoreSecret(value, previous.Headers[name])
+		if err != nil {
+			return err
+		}
+		c.Headers[name] = restored
+	}
+	if c.Auth != nil {
+		var previousAuth HTTPAuth
+		if previous.Auth != nil {
+			previousAuth = *previous.Auth
+		}
+		var err error
+		if c.Auth.Password, err = restoreSecret(c.Auth.Password, previousAuth.Password); err != nil {
+			return err
+		}
+		if c.Auth.Token, err = restoreSecret(c.Auth.Token, previousAuth.Token); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreSecret returns previous when value is MaskedSecret and value otherwise
+func restoreSecret(value, previous string) (string, error) {
+	if value != MaskedSecret {
+		return value, nil
+	}
+	if previous == "" {
+		return "", ErrSecretNotStored
+	}
+	return previous, nil
+}
+
+// hasMaskedSecrets reports whether any credential of the request settings is MaskedSecret
+func (c HTTPConfig) hasMaskedSecrets() bool {
+	for _, value := range c.Headers {
+		if value == MaskedSecret {
+			return true
+		}
+	}
+	return c.Auth != nil && (c.Auth.Password == MaskedSecret || c.Auth.Token == MaskedSecret)
+}
+
+// maskSensitiveHeaders returns a copy of headers with credential-like values replaced by MaskedSecret
+func maskSensitiveHeaders(headers map[string]string) map[string]string {
+	if len(headers) == 0 {
+		return headers
+	}
+	masked := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if IsSensitiveHeader(name) && value != "" {
+			value = MaskedSecret
+		}
+		masked[name] = value
+	}
+	return masked
+}
+
+// maskValues returns a copy of values with every non-empty value replaced by MaskedSecret
+func maskValues(values map[string]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+	masked := make(map[string]string, len(values))
+	for name, value := range values {
+		if value != "" {
+			value = MaskedSecret
+		}
+		masked[name] = value
+	}
+	return masked
+}
+
+// Value implements the driver.Valuer interface for database serialization
+func (c MonitorConfig) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// Scan implements the sql.Scanner interface for database deserialization
+func (c *MonitorConfig) Scan(value interface{}) error {
+	if value == nil {
+		*c = MonitorConfig{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to scan MonitorConfig: value is not []byte")
+	}
+
+	return json.Unmarshal(bytes, c)
+}
@@ -0,0 +1,101 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMonitorConfig_RestoreSecrets_TransactionSteps(t *testing.T) {
+	previous := MonitorConfig{Transaction: &TransactionConfig{
+		Steps: []TransactionStep{
+			{Name: "login", URL: "/login", HTTPConfig: HTTPConfig{Method: "POST", Auth: &HTTPAuth{Type: "basic", Username: "monitor", Password: "login-secret"}}},
+			{URL: "/api/orders", HTTPConfig: HTTPConfig{Headers: map[string]string{"X-Api-Key": "orders-key"}}},
+		},
+		Secrets: map[string]string{"password": "s3cret"},
+	}}
+	masked := previous.Masked()
+
+	// A new step is inserted in front, the stored steps keep their own credentials
+	config := masked
+	config.Transaction.Steps = append([]TransactionStep{{Name: "health", URL: "/health"}}, config.Transaction.Steps...)
+	if err := config.RestoreSecrets(previous); err != nil {
+		t.Fatalf("RestoreSecrets() error = %v", err)
+	}
+	if got := config.Transaction.Steps[0].Auth; got != nil {
+		t.Errorf("Expected the new step to get no credentials, got %+v", got)
+	}
+	if got := config.Transaction.Steps[1].Auth.Password; got != "login-secret" {
+		t.Errorf("Expected the login step to keep its password, got %q", got)
+	}
+	if got := config.Transaction.Steps[2].Headers["X-Api-Key"]; got != "orders-key" {
+		t.Errorf("Expected the unnamed step to be matched by method and URL, got %q", got)
+	}
+	if got := config.Transaction.Secrets["password"]; got != "s3cret" {
+		t.Errorf("Expected the transaction secret to be restored, got %q", got)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*TransactionConfig)
+	}{
+		{"renamed step", func(c *TransactionConfig) { c.Steps[0].Name = "sign-in" }},
+		{"unnamed step with another URL", func(c *TransactionConfig) { c.Steps[1].URL = "/api/invoices" }},
+		{"unknown secret", func(c *TransactionConfig) { c.Secrets["token"] = MaskedSecret }},
+		{"step credential without a stored value", func(c *TransactionConfig) {
+			c.Steps[1].Auth = &HTTPAuth{Type: "bearer", Token: MaskedSecret}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := previous.Masked()
+			tt.modify(config.Transaction)
+			if err := config.RestoreSecrets(previous); !errors.Is(err, ErrSecretNotStored) {
+				t.Errorf("RestoreSecrets() error = %v, want ErrSecretNotStored", err)
+			}
+		})
+	}
+}
+
+func TestMonitorConfig_RestoreSecrets_DuplicateStepKeys(t *testing.T) {
+	previous := MonitorConfig{Transaction: &TransactionConfig{Steps: []TransactionStep{
+		{Name: "call", HTTPConfig: HTTPConfig{Auth: &HTTPAuth{Type: "bearer", Token: "first"}}},
+		{Name: "call", HTTPConfig: HTTPConfig{Auth: &HTTPAuth{Type: "bearer", Token: "second"}}},
+	}}}
+
+	config := previous.Masked()
+	if err := config.RestoreSecrets(previous); !errors.Is(err, ErrSecretNotStored) {
+		t.Errorf("Expected steps with the same name not to be matched, got error %v", err)
+	}
+}
+
+func TestMonitorConfig_MoveSecrets_HTTP(t *testing.T) {
+	config := MonitorConfig{HTTP: &HTTPConfig{
+		Headers: map[string]string{"Authorization": "Bearer abc", "Accept": "application/json"},
+		Auth:    &HTTPAuth{Type: "basic", Username: "admin", Password: "s3cret"},
+	}}
+
+	var secrets MonitorSecrets
+	if err := config.MoveSecrets(&secrets, MonitorSecrets{}); err != nil {
+		t.Fatalf("MoveSecrets() error = %v", err)
+	}
+	if secrets.HTTPPassword != "s3cret" || secrets.HTTPHeaders["Authorization"] != "Bearer abc" || len(secrets.HTTPHeaders) != 1 {
+		t.Errorf("Unexpected secrets: %+v", secrets)
+	}
+	if config.HTTP.Auth.Password != MaskedSecret || config.HTTP.Headers["Authorization"] != MaskedSecret || config.HTTP.Headers["Accept"] != "application/json" {
+		t.Errorf("Expected only credentials to be masked in the config, got %+v", config.HTTP)
+	}
+
+	// The masked config sent back unchanged keeps the stored secrets
+	var updated MonitorSecrets
+	if err := config.MoveSecrets(&updated, secrets); err != nil {
+		t.Fatalf("MoveSecrets() error = %v", err)
+	}
+	if updated.HTTPPassword != "s3cret" || updated.HTTPHeaders["Authorization"] != "Bearer abc" {
+		t.Errorf("Expected the stored secrets to be kept, got %+v", updated)
+	}
+
+	// Without stored secrets, e.g. after a key change, masked values must be entered again
+	if err := config.MoveSecrets(&MonitorSecrets{}, MonitorSecrets{}); !errors.Is(err, ErrSecretNotStored) {
+		t.Errorf("MoveSecrets() error = %v, want ErrSecretNotStored", err)
+	}
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+)
+
+// CertificateRepository defines the interface for the certificate inventory and its standalone targets
+type CertificateRepository interface {
+	CreateTarget(target *entities.CertificateTarget) error
+	GetTargetByID(id string) (*entities.CertificateTarget, error)
+	GetTargetsByUserID(userID int) ([]*entities.CertificateTarget, error)
+	DeleteTarget(id string) error
+
+	// GetMonitorsDue returns the enabled HTTPS monitors whose certificate was not inventoried since checkedBefore
+	GetMonitorsDue(checkedBefore time.Time) ([]*entities.Monitor, error)
+	// GetTargetsDue returns the targets whose certificate was not inventoried since checkedBefore
+	GetTargetsDue(checkedBefore time.Time) ([]*entities.CertificateTarget, error)
+
+	Upsert(certificate *entities.Certificate) error
+	GetByUserID(userID int) ([]*entities.Certificate, error)
+}
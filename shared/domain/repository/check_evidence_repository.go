@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+)
+
+// CheckEvidenceRepository defines the interface for the stored responses of monitor checks
+type CheckEvidenceRepository interface {
+	// Create stores the evidence of a saved check
+	Create(evidence *entities.CheckEvidence) error
+
+	// GetByCheckID returns the evidence of a check, sql.ErrNoRows when none was kept
+	GetByCheckID(checkID string) (*entities.CheckEvidence, error)
+
+	// DeleteOlderThan removes the evidence stored before cutoff and returns how many were removed
+	DeleteOlderThan(cutoff time.Time) (int64, error)
+}
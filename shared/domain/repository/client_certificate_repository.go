@@ -0,0 +1,11 @@
+package repository
+
+import "github.com/eovipmak/v-insight/shared/domain/entities"
+
+// ClientCertificateRepository defines the interface for client certificate data operations
+type ClientCertificateRepository interface {
+	Create(certificate *entities.ClientCertificate) error
+	GetByID(id string) (*entities.ClientCertificate, error)
+	GetByUserID(userID int) ([]*entities.ClientCertificate, error)
+	Delete(id string) error
+}
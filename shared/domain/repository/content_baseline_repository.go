@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+)
+
+// ContentBaselineRepository defines the interface for the content snapshots of change detection monitors
+type ContentBaselineRepository interface {
+	// GetByMonitorID returns the snapshots of a monitor, sql.ErrNoRows when no baseline was taken yet
+	GetByMonitorID(monitorID string) (*entities.ContentBaseline, error)
+
+	// Reset makes the given content the baseline and latest snapshot of a monitor
+	Reset(baseline *entities.ContentBaseline) error
+
+	// UpdateCurrent records the latest snapshot of a monitor, keeping its baseline
+	UpdateCurrent(monitorID, hash, content string, checkedAt time.Time) error
+
+	// Accept makes the latest snapshot of a monitor its baseline
+	Accept(monitorID string) error
+}
@@ -14,6 +14,9 @@ type MonitorRepository interface {
 	// GetByID retrieves a monitor by its ID
 	GetByID(id string) (*entities.Monitor, error)
 
+	// GetByPushToken retrieves a push monitor by the token of its heartbeat URL
+	GetByPushToken(token string) (*entities.Monitor, error)
+
 	// GetByUserID retrieves all monitors for a specific user
 	GetByUserID(userID int) ([]*entities.Monitor, error)
 
@@ -26,6 +29,12 @@ type MonitorRepository interface {
 	// GetChecksByMonitorID retrieves all check history for a specific monitor
 	GetChecksByMonitorID(monitorID string, limit int) ([]*entities.MonitorCheck, error)
 
+	// GetCheckByID retrieves a single check, sql.ErrNoRows when it does not exist
+	GetCheckByID(checkID string) (*entities.MonitorCheck, error)
+
+	// GetLatestSSLCheck retrieves the most recent check with SSL information for a monitor, nil if there is none
+	GetLatestSSLCheck(monitorID string) (*entities.MonitorCheck, error)
+
 	// GetStatsByMonitorID retrieves response time statistics for a monitor over the last 24 hours
 	GetStatsByMonitorID(monitorID string) ([]*entities.MonitorStat, error)
 
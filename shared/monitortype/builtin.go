@@ -0,0 +1,186 @@
+package monitortype
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+)
+
+var tcpAddressRegex = regexp.MustCompile(`^((tcp|tls)://)?[^:/]+:\d+$`)
+
+// containerNameRegex matches Docker container names and IDs, as accepted by the Engine API
+var containerNameRegex = regexp.MustCompile(`^/?[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// hostPortRegex matches the host:port target of gRPC, UDP and mail server monitors
+var hostPortRegex = regexp.MustCompile(`^[^:/]+:\d+$`)
+
+func init() {
+	Register(&Type{
+		Name:             "http",
+		Sections:         []string{"http", "connection", "proxy", "redirect", "content"},
+		CertificateCheck: true,
+		Target:           validateURLTarget,
+	})
+	Register(&Type{
+		Name:     "tcp",
+		Sections: []string{"tcp", "proxy"},
+		Target: func(target string) error {
+			if !tcpAddressRegex.MatchString(target) {
+				return errors.New("Invalid Host:Port format. Use format: host:port or tls://host:port")
+			}
+			return nil
+		},
+	})
+	Register(&Type{
+		Name:     "udp",
+		Sections: []string{"udp"},
+		Target: func(target string) error {
+			if !hostPortRegex.MatchString(target) {
+				return errors.New("Invalid Host:Port format. Use format: host:port")
+			}
+			return nil
+		},
+		Config: func(t *Type, cfg *entities.MonitorConfig) error {
+			if cfg.UDP == nil {
+				return errors.New("udp monitors require a payload or a preset")
+			}
+			return nil
+		},
+	})
+	for _, name := range []string{"ping", "icmp"} {
+		Register(&Type{
+			Name:     name,
+			Sections: []string{"icmp"},
+			Target: func(target string) error {
+				if strings.Contains(target, "://") {
+					return errors.New("Invalid Hostname/IP format. Do not include protocol (http://, etc.)")
+				}
+				return nil
+			},
+		})
+	}
+	Register(&Type{
+		Name:     "dns",
+		Sections: []string{"dns"},
+		Target: func(target string) error {
+			if strings.Contains(target, "://") || strings.Contains(target, "/") {
+				return errors.New("Invalid domain name. Do not include protocol or path")
+			}
+			return nil
+		},
+		Config: func(t *Type, cfg *entities.MonitorConfig) error {
+			if cfg.DNS == nil {
+				cfg.DNS = &entities.DNSConfig{}
+				return validateDNSConfig(cfg.DNS)
+			}
+			return nil
+		},
+	})
+	Register(&Type{
+		Name:             "transaction",
+		Sections:         []string{"transaction", "connection", "proxy"},
+		CertificateCheck: true,
+		Target:           validateURLTarget,
+		Config: func(t *Type, cfg *entities.MonitorConfig) error {
+			if cfg.Transaction == nil {
+				return errors.New("transaction monitors require at least one step")
+			}
+			return nil
+		},
+	})
+	// Push monitors are never probed, heartbeats are sent to their push URL
+	Register(&Type{
+		Name:           "push",
+		Sections:       []string{"push"},
+		TargetOptional: true,
+	})
+	Register(&Type{
+		Name:             "websocket",
+		Sections:         []string{"websocket"},
+		CertificateCheck: true,
+		Target: func(target string) error {
+			u, err := url.Parse(target)
+			if err != nil || (u.Scheme != "ws" && u.Scheme != "wss") || u.Host == "" {
+				return errors.New("Invalid WebSocket URL. Use format: ws://host/path or wss://host/path")
+			}
+			return nil
+		},
+	})
+	Register(&Type{
+		Name:     "grpc",
+		Sections: []string{"grpc"},
+		Target: func(target string) error {
+			if !hostPortRegex.MatchString(target) {
+				return errors.New("Invalid gRPC address. Use format: host:port")
+			}
+			return nil
+		},
+	})
+	for _, name := range []string{"smtp", "imap", "pop3"} {
+		Register(&Type{
+			Name:             name,
+			Sections:         []string{"mail"},
+			CertificateCheck: true, // certificate presented after STARTTLS or on the TLS port
+			Target: func(target string) error {
+				if !hostPortRegex.MatchString(target) {
+					return errors.New("Invalid Host:Port format. Use format: host:port, e.g. mail.example.com:587")
+				}
+				return nil
+			},
+		})
+	}
+	for _, database := range []struct {
+		name    string
+		schemes []string
+	}{
+		{"postgres", []string{"postgres", "postgresql"}},
+		{"mysql", []string{"mysql"}},
+		{"redis", []string{"redis", "rediss"}},
+	} {
+		Register(&Type{
+			Name:           database.name,
+			Sections:       []string{"database"},
+			TargetOptional: true,
+			DSNSchemes:     database.schemes,
+			Config: func(t *Type, cfg *entities.MonitorConfig) error {
+				if cfg.Database == nil {
+					cfg.Database = &entities.DatabaseConfig{}
+				}
+				return validateDatabaseConfig(t, cfg.Database)
+			},
+		})
+	}
+	Register(&Type{
+		Name:     "docker",
+		Sections: []string{"docker"},
+		// The container is looked up by name or ID, the endpoint is set in the docker section
+		Target: func(target string) error {
+			if !containerNameRegex.MatchString(target) {
+				return errors.New("Invalid container name. Use the name or ID of the container, e.g. web-1")
+			}
+			return nil
+		},
+	})
+	Register(&Type{
+		Name: "domain",
+		// Subdomains are reduced to the registrable domain by the worker
+		Target: func(target string) error {
+			if strings.Contains(target, "://") || strings.Contains(target, "/") || net.ParseIP(target) != nil {
+				return errors.New("Invalid domain name. Use a domain such as example.com, without protocol or path")
+			}
+			return nil
+		},
+	})
+}
+
+// validateURLTarget validates the URL probed by http and transaction monitors
+func validateURLTarget(target string) error {
+	if _, err := url.ParseRequestURI(target); err != nil {
+		return errors.New("Invalid URL format")
+	}
+	return nil
+}
@@ -0,0 +1,573 @@
+package monitortype
+
+import (
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/eovipmak/v-insight/shared/content"
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+)
+
+// grpcMetadataKeyRegex matches valid gRPC metadata keys (lowercased before use)
+var grpcMetadataKeyRegex = regexp.MustCompile(`^[0-9a-z_.-]+$`)
+
+// transactionVariableRegex matches names usable as {{name}} in transaction steps
+var transactionVariableRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// sectionValidators validate and normalize the config sections that mean the same for every type
+// accepting them; database settings depend on the engine and are validated by the type itself
+var sectionValidators = map[string]func(cfg *entities.MonitorConfig) error{
+	"dns":        func(cfg *entities.MonitorConfig) error { return validateDNSConfig(cfg.DNS) },
+	"icmp":       func(cfg *entities.MonitorConfig) error { return validateICMPConfig(cfg.ICMP) },
+	"http":       func(cfg *entities.MonitorConfig) error { return validateHTTPConfig(cfg.HTTP) },
+	"tcp":        func(cfg *entities.MonitorConfig) error { return validateTCPConfig(cfg.TCP) },
+	"udp":        func(cfg *entities.MonitorConfig) error { return validateUDPConfig(cfg.UDP) },
+	"push":       func(cfg *entities.MonitorConfig) error { return validatePushConfig(cfg.Push) },
+	"grpc":       func(cfg *entities.MonitorConfig) error { return validateGRPCConfig(cfg.GRPC) },
+	"mail":       func(cfg *entities.MonitorConfig) error { return validateMailConfig(cfg.Mail) },
+	"websocket":  func(cfg *entities.MonitorConfig) error { return validateWebSocketConfig(cfg.WebSocket) },
+	"docker":     func(cfg *entities.MonitorConfig) error { return validateDockerConfig(cfg.Docker) },
+	"connection": func(cfg *entities.MonitorConfig) error { return validateConnectionConfig(cfg.Connection) },
+	"proxy": func(cfg *entities.MonitorConfig) error {
+		if err := validateProxyConfig(cfg.Proxy); err != nil {
+			return err
+		}
+		if cfg.Connection != nil && cfg.Connection.ResolveTo != "" {
+			return errors.New("resolve_to cannot be combined with a proxy, the proxy resolves the host")
+		}
+		return nil
+	},
+	"redirect":    func(cfg *entities.MonitorConfig) error { return validateRedirectConfig(cfg.Redirect) },
+	"content":     func(cfg *entities.MonitorConfig) error { return validateContentConfig(cfg.Content) },
+	"transaction": func(cfg *entities.MonitorConfig) error { return validateTransactionConfig(cfg.Transaction) },
+}
+
+// validateDNSConfig validates the record type, match mode and resolver of a DNS monitor
+func validateDNSConfig(cfg *entities.DNSConfig) error {
+	cfg.RecordType = strings.ToUpper(strings.TrimSpace(cfg.RecordType))
+	if cfg.RecordType == "" {
+		cfg.RecordType = "A" // default
+	}
+
+	switch cfg.RecordType {
+	case "A", "AAAA", "CNAME", "MX", "TXT", "NS":
+	default:
+		return fmt.Errorf("invalid DNS record type %q. Use one of: A, AAAA, CNAME, MX, TXT, NS", cfg.RecordType)
+	}
+
+	if cfg.MatchMode != "" && cfg.MatchMode != "contains" && cfg.MatchMode != "exact" {
+		return fmt.Errorf("invalid DNS match mode %q. Use 'contains' or 'exact'", cfg.MatchMode)
+	}
+
+	cfg.Resolver = strings.TrimSpace(cfg.Resolver)
+	if strings.Contains(cfg.Resolver, "://") || strings.Contains(cfg.Resolver, "/") {
+		return errors.New("invalid DNS resolver. Use format: host or host:port")
+	}
+
+	return nil
+}
+
+// validateICMPConfig validates the probe count of a ping monitor
+func validateICMPConfig(cfg *entities.ICMPConfig) error {
+	// 0 is an unset count, the default of 3 is used
+	if cfg.Count < 0 || cfg.Count > 20 {
+		return errors.New("ping count must be between 1 and 20, or 0 for the default of 3")
+	}
+	return nil
+}
+
+// validatePushConfig validates the grace period of a push monitor
+func validatePushConfig(cfg *entities.PushConfig) error {
+	if cfg.GracePeriod < 0 || cfg.GracePeriod > 86400 {
+		return errors.New("push grace period must be between 0 and 86400 seconds")
+	}
+	return nil
+}
+
+// validateConnectionConfig validates the TLS and addressing overrides of an HTTP monitor
+func validateConnectionConfig(cfg *entities.ConnectionConfig) error {
+	switch cfg.MinTLSVersion {
+	case "", "1.0", "1.1", "1.2", "1.3":
+	default:
+		return fmt.Errorf("invalid minimum TLS version %q. Use one of: 1.0, 1.1, 1.2, 1.3", cfg.MinTLSVersion)
+	}
+
+	cfg.IPFamily = strings.ToLower(strings.TrimSpace(cfg.IPFamily))
+	if cfg.IPFamily != "" && cfg.IPFamily != "ipv4" && cfg.IPFamily != "ipv6" {
+		return fmt.Errorf("invalid IP family %q. Use 'ipv4' or 'ipv6'", cfg.IPFamily)
+	}
+
+	cfg.ServerName = strings.TrimSpace(cfg.ServerName)
+	if strings.ContainsAny(cfg.ServerName, ":/ \r\n") {
+		return errors.New("invalid server name. Use a host name without port or scheme")
+	}
+
+	cfg.ResolveTo = strings.TrimSpace(cfg.ResolveTo)
+	if cfg.ResolveTo != "" {
+		ip := net.ParseIP(cfg.ResolveTo)
+		if ip == nil {
+			return fmt.Errorf("invalid resolve_to address %q. Use an IPv4 or IPv6 address", cfg.ResolveTo)
+		}
+		if (cfg.IPFamily == "ipv4" && ip.To4() == nil) || (cfg.IPFamily == "ipv6" && ip.To4() != nil) {
+			return fmt.Errorf("resolve_to address %s does not match IP family %s", cfg.ResolveTo, cfg.IPFamily)
+		}
+	}
+
+	return nil
+}
+
+// validateProxyConfig validates the type, address and credentials of a monitor proxy
+func validateProxyConfig(cfg *entities.ProxyConfig) error {
+	cfg.Type = strings.ToLower(strings.TrimSpace(cfg.Type))
+	if cfg.Type != "http" && cfg.Type != "socks5" {
+		return fmt.Errorf("invalid proxy type %q. Use 'http' or 'socks5'", cfg.Type)
+	}
+
+	cfg.Address = strings.TrimSpace(cfg.Address)
+	host, port, err := net.SplitHostPort(cfg.Address)
+	if err != nil || host == "" || strings.ContainsAny(host, "/ ") {
+		return errors.New("invalid proxy address. Use format: host:port")
+	}
+	if portNum, err := strconv.Atoi(port); err != nil || portNum < 1 || portNum > 65535 {
+		return errors.New("invalid proxy port. Must be between 1 and 65535")
+	}
+
+	if cfg.Password != "" && cfg.Username == "" {
+		return errors.New("proxy password requires a username")
+	}
+	// SOCKS5 limits each credential to 255 bytes (RFC 1929)
+	if len(cfg.Username) > 255 || len(cfg.Password) > 255 {
+		return errors.New("proxy username and password must be at most 255 characters")
+	}
+	if strings.ContainsAny(cfg.Username+cfg.Password, "\r\n") || strings.Contains(cfg.Username, ":") {
+		return errors.New("proxy username must not contain ':' and credentials must not contain line breaks")
+	}
+
+	return nil
+}
+
+// validateRedirectConfig validates the redirect policy and expected targets of an HTTP monitor
+func validateRedirectConfig(cfg *entities.RedirectConfig) error {
+	// 0 is an unset limit, the default of 5 is used
+	if cfg.MaxRedirects < 0 || cfg.MaxRedirects > 20 {
+		return errors.New("max_redirects must be between 1 and 20, or 0 for the default of 5")
+	}
+
+	follow := cfg.Follow == nil || *cfg.Follow
+	if !follow && cfg.MaxRedirects > 0 {
+		return errors.New("max_redirects requires following redirects")
+	}
+	if follow && cfg.ExpectedLocation != "" {
+		return errors.New("expected_location requires follow to be false, use expected_final_url when following redirects")
+	}
+
+	cfg.ExpectedFinalURL = strings.TrimSpace(cfg.ExpectedFinalURL)
+	if cfg.ExpectedFinalURL != "" && !isAbsoluteHTTPURL(cfg.ExpectedFinalURL) {
+		return errors.New("invalid expected_final_url. Use an absolute http:// or https:// URL")
+	}
+
+	cfg.ExpectedLocation = strings.TrimSpace(cfg.ExpectedLocation)
+	if cfg.ExpectedLocation != "" && !isAbsoluteHTTPURL(cfg.ExpectedLocation) {
+		return errors.New("invalid expected_location. Use an absolute http:// or https:// URL")
+	}
+
+	return nil
+}
+
+// isAbsoluteHTTPURL reports whether value is an http or https URL with a host
+func isAbsoluteHTTPURL(value string) bool {
+	parsed, err := url.Parse(value)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// validateContentConfig validates the selection and ignore patterns of content change detection
+func validateContentConfig(cfg *entities.ContentConfig) error {
+	cfg.Selector = strings.TrimSpace(cfg.Selector)
+	cfg.JSONPath = strings.TrimSpace(cfg.JSONPath)
+	if cfg.Selector != "" && cfg.JSONPath != "" {
+		return errors.New("use either a content selector or a JSON path, not both")
+	}
+	if len(cfg.Selector) > 512 || len(cfg.JSONPath) > 512 {
+		return errors.New("content selector and JSON path must be at most 512 characters")
+	}
+	if cfg.Selector != "" {
+		if _, err := content.ParseSelector(cfg.Selector); err != nil {
+			return fmt.Errorf("invalid content selector: %v", err)
+		}
+	}
+
+	if len(cfg.Ignore) > 20 {
+		return errors.New("at most 20 content ignore patterns are allowed")
+	}
+	for _, pattern := range cfg.Ignore {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid content ignore pattern %q: %v", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// validateTCPConfig validates the send/expect exchange of a TCP monitor
+func validateTCPConfig(cfg *entities.TCPConfig) error {
+	switch cfg.MatchMode {
+	case "", "contains":
+	case "regex":
+		if _, err := regexp.Compile(cfg.Expect); err != nil {
+			return fmt.Errorf("invalid TCP expect regex: %v", err)
+		}
+	default:
+		return fmt.Errorf("invalid TCP match mode %q. Use 'contains' or 'regex'", cfg.MatchMode)
+	}
+
+	if len(cfg.Send) > 4096 {
+		return errors.New("TCP payload must be at most 4096 bytes")
+	}
+
+	return nil
+}
+
+// validateUDPConfig validates the datagram and expected reply of a UDP monitor
+// Hex payloads and expectations are normalized by removing whitespace
+func validateUDPConfig(cfg *entities.UDPConfig) error {
+	cfg.Preset = strings.ToLower(strings.TrimSpace(cfg.Preset))
+	switch cfg.Preset {
+	case "", "dns", "ntp", "snmp":
+	default:
+		return fmt.Errorf("invalid UDP preset %q. Use one of: dns, ntp, snmp", cfg.Preset)
+	}
+
+	if cfg.Preset == "" && cfg.Payload == "" {
+		return errors.New("udp monitors require a payload or a preset")
+	}
+	if cfg.Preset != "" && cfg.Payload != "" {
+		return errors.New("use either a UDP preset or a payload, not both")
+	}
+	if cfg.Community != "" && cfg.Preset != "snmp" {
+		return errors.New("a community is only used by the snmp preset")
+	}
+	if len(cfg.Community) > 255 || strings.ContainsAny(cfg.Community, "\r\n") {
+		return errors.New("SNMP community must be at most 255 characters without line breaks")
+	}
+
+	size := len(cfg.Payload)
+	switch cfg.Encoding {
+	case "", "text":
+	case "hex":
+		cfg.Payload = removeWhitespace(cfg.Payload)
+		payload, err := hex.DecodeString(cfg.Payload)
+		if err != nil {
+			return errors.New("invalid UDP payload. Use hex digits, optionally separated by spaces")
+		}
+		size = len(payload)
+	default:
+		return fmt.Errorf("invalid UDP payload encoding %q. Use 'text' or 'hex'", cfg.Encoding)
+	}
+	if size > 4096 {
+		return errors.New("UDP payload must be at most 4096 bytes")
+	}
+
+	switch cfg.MatchMode {
+	case "", "contains":
+	case "regex":
+		if _, err := regexp.Compile(cfg.Expect); err != nil {
+			return fmt.Errorf("invalid UDP expect regex: %v", err)
+		}
+	case "hex":
+		cfg.Expect = removeWhitespace(cfg.Expect)
+		if _, err := hex.DecodeString(cfg.Expect); err != nil || cfg.Expect == "" {
+			return errors.New("invalid UDP expect bytes. Use hex digits, optionally separated by spaces")
+		}
+	default:
+		return fmt.Errorf("invalid UDP match mode %q. Use 'contains', 'regex' or 'hex'", cfg.MatchMode)
+	}
+
+	return nil
+}
+
+// removeWhitespace removes all whitespace from s, e.g. the separators of hex bytes
+func removeWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), "")
+}
+
+// validateMailConfig validates the TLS mode and login of a mail server monitor
+func validateMailConfig(cfg *entities.MailConfig) error {
+	cfg.TLS = strings.ToLower(strings.TrimSpace(cfg.TLS))
+	switch cfg.TLS {
+	case "", "starttls", "implicit":
+	default:
+		return fmt.Errorf("invalid mail TLS mode %q. Use 'starttls' or 'implicit'", cfg.TLS)
+	}
+
+	cfg.Username = strings.TrimSpace(cfg.Username)
+	if (cfg.Username == "") != (cfg.Password == "") {
+		return errors.New("mail login requires both a username and a password")
+	}
+	if cfg.Username != "" && cfg.TLS == "" {
+		return errors.New("mail credentials are only sent over TLS, set tls to 'starttls' or 'implicit'")
+	}
+	if len(cfg.Username) > 255 || len(cfg.Password) > 255 {
+		return errors.New("mail username and password must be at most 255 characters")
+	}
+	if strings.ContainsAny(cfg.Username+cfg.Password+cfg.ExpectBanner, "\r\n") {
+		return errors.New("mail credentials and expected banner must not contain line breaks")
+	}
+
+	return nil
+}
+
+// validateWebSocketConfig validates the upgrade headers and message exchange of a WebSocket monitor
+func validateWebSocketConfig(cfg *entities.WebSocketConfig) error {
+	for name, value := range cfg.Headers {
+		if name == "" || strings.ContainsAny(name, " :\r\n") || strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("invalid HTTP header %q", name)
+		}
+		// Set by the handshake itself
+		switch http.CanonicalHeaderKey(name) {
+		case "Upgrade", "Connection", "Sec-Websocket-Key", "Sec-Websocket-Version", "Sec-Websocket-Extensions":
+			return fmt.Errorf("header %q is set by the WebSocket handshake", name)
+		case "Sec-Websocket-Protocol":
+			return errors.New("use subprotocol instead of the Sec-WebSocket-Protocol header")
+		}
+	}
+
+	cfg.Subprotocol = strings.TrimSpace(cfg.Subprotocol)
+	if strings.ContainsAny(cfg.Subprotocol, " ,;\r\n") {
+		return fmt.Errorf("invalid WebSocket subprotocol %q", cfg.Subprotocol)
+	}
+
+	switch cfg.MatchMode {
+	case "", "contains":
+	case "regex":
+		if _, err := regexp.Compile(cfg.Expect); err != nil {
+			return fmt.Errorf("invalid WebSocket expect regex: %v", err)
+		}
+	default:
+		return fmt.Errorf("invalid WebSocket match mode %q. Use 'contains' or 'regex'", cfg.MatchMode)
+	}
+
+	if len(cfg.Send) > 65536 {
+		return errors.New("WebSocket message must be at most 65536 bytes")
+	}
+
+	return nil
+}
+
+// validateDockerConfig validates the Engine API endpoint and TLS settings of a Docker monitor
+func validateDockerConfig(cfg *entities.DockerConfig) error {
+	cfg.Endpoint = strings.TrimSpace(cfg.Endpoint)
+	if cfg.Endpoint != "" {
+		u, err := url.Parse(cfg.Endpoint)
+		if err != nil {
+			return fmt.Errorf("invalid Docker endpoint %q", cfg.Endpoint)
+		}
+		switch u.Scheme {
+		case "unix":
+			if u.Host != "" || u.Path == "" {
+				return errors.New("invalid Docker socket. Use format: unix:///var/run/docker.sock")
+			}
+		case "tcp":
+			if u.Hostname() == "" || u.Port() == "" || (u.Path != "" && u.Path != "/") {
+				return errors.New("invalid Docker endpoint. Use format: tcp://host:2376")
+			}
+		default:
+			return fmt.Errorf("invalid Docker endpoint %q. Use unix:///path/to/docker.sock or tcp://host:port", cfg.Endpoint)
+		}
+	}
+
+	if cfg.TLS && !strings.HasPrefix(cfg.Endpoint, "tcp://") {
+		return errors.New("Docker TLS requires a tcp:// endpoint")
+	}
+	if !cfg.TLS && (cfg.CACertificate != "" || cfg.InsecureSkipVerify) {
+		return errors.New("ca_certificate and insecure_skip_verify require tls to be enabled")
+	}
+	if cfg.CACertificate != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(cfg.CACertificate)) {
+		return errors.New("ca_certificate must contain PEM-encoded certificates")
+	}
+
+	return nil
+}
+
+// validateGRPCConfig validates the service name and metadata of a gRPC monitor
+func validateGRPCConfig(cfg *entities.GRPCConfig) error {
+	cfg.Service = strings.TrimSpace(cfg.Service)
+
+	metadata := make(map[string]string, len(cfg.Metadata))
+	for key, value := range cfg.Metadata {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !grpcMetadataKeyRegex.MatchString(key) || strings.HasPrefix(key, "grpc-") || strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("invalid gRPC metadata %q", key)
+		}
+		metadata[key] = value
+	}
+	if len(metadata) > 0 {
+		cfg.Metadata = metadata
+	}
+
+	return nil
+}
+
+// validateHTTPConfig validates the method, headers and auth of an HTTP monitor request
+func validateHTTPConfig(cfg *entities.HTTPConfig) error {
+	cfg.Method = strings.ToUpper(strings.TrimSpace(cfg.Method))
+	switch cfg.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions:
+	default:
+		return fmt.Errorf("invalid HTTP method %q", cfg.Method)
+	}
+
+	for name, value := range cfg.Headers {
+		if name == "" || strings.ContainsAny(name, " :\r\n") || strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("invalid HTTP header %q", name)
+		}
+	}
+
+	if cfg.Auth != nil {
+		cfg.Auth.Type = strings.ToLower(strings.TrimSpace(cfg.Auth.Type))
+		switch cfg.Auth.Type {
+		case "basic":
+			if cfg.Auth.Username == "" {
+				return errors.New("basic auth requires a username")
+			}
+		case "bearer":
+			if cfg.Auth.Token == "" {
+				return errors.New("bearer auth requires a token")
+			}
+		default:
+			return fmt.Errorf("invalid auth type %q. Use 'basic' or 'bearer'", cfg.Auth.Type)
+		}
+	}
+
+	for i := range cfg.Assertions {
+		if err := validateHTTPAssertion(&cfg.Assertions[i]); err != nil {
+			return fmt.Errorf("assertion %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// validateTransactionConfig validates the steps and extracted variables of a transaction monitor
+func validateTransactionConfig(cfg *entities.TransactionConfig) error {
+	if len(cfg.Steps) == 0 || len(cfg.Steps) > 10 {
+		return errors.New("transaction monitors require between 1 and 10 steps")
+	}
+
+	for name := range cfg.Secrets {
+		if !transactionVariableRegex.MatchString(name) {
+			return fmt.Errorf("invalid secret name %q. Use letters, digits and underscores", name)
+		}
+	}
+
+	for i := range cfg.Steps {
+		step := &cfg.Steps[i]
+		if _, err := url.Parse(step.URL); err != nil {
+			return fmt.Errorf("step %d: invalid URL", i+1)
+		}
+		if err := validateHTTPConfig(&step.HTTPConfig); err != nil {
+			return fmt.Errorf("step %d: %w", i+1, err)
+		}
+		for _, extract := range step.Extract {
+			if !transactionVariableRegex.MatchString(extract.Variable) {
+				return fmt.Errorf("step %d: invalid variable name %q. Use letters, digits and underscores", i+1, extract.Variable)
+			}
+			switch extract.Source {
+			case "json", "header", "cookie":
+			default:
+				return fmt.Errorf("step %d: invalid extract source %q. Use one of: json, header, cookie", i+1, extract.Source)
+			}
+			if strings.TrimSpace(extract.Property) == "" {
+				return fmt.Errorf("step %d: extracting %s requires a property", i+1, extract.Variable)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateHTTPAssertion validates the source, operator and expected value of a response assertion
+func validateHTTPAssertion(assertion *entities.HTTPAssertion) error {
+	switch assertion.Source {
+	case "json", "header":
+		if strings.TrimSpace(assertion.Property) == "" {
+			return fmt.Errorf("%s assertions require a property", assertion.Source)
+		}
+	case "content_length":
+	default:
+		return fmt.Errorf("invalid source %q. Use one of: json, header, content_length", assertion.Source)
+	}
+
+	switch assertion.Operator {
+	case "equals", "not_equals", "contains", "exists":
+	case "greater_than", "less_than":
+		if _, err := strconv.ParseFloat(assertion.Value, 64); err != nil {
+			return fmt.Errorf("operator %s requires a numeric value", assertion.Operator)
+		}
+	case "matches":
+		if _, err := regexp.Compile(assertion.Value); err != nil {
+			return fmt.Errorf("invalid regex: %v", err)
+		}
+	default:
+		return fmt.Errorf("invalid operator %q. Use one of: equals, not_equals, greater_than, less_than, contains, exists, matches", assertion.Operator)
+	}
+
+	return nil
+}
+
+// validateDatabaseConfig validates the connection string, probe query and assertions of a database monitor
+// An empty or masked DSN keeps the stored one
+func validateDatabaseConfig(t *Type, cfg *entities.DatabaseConfig) error {
+	cfg.DSN = strings.TrimSpace(cfg.DSN)
+	cfg.Query = strings.TrimSpace(cfg.Query)
+
+	if cfg.DSN != "" && cfg.DSN != entities.MaskedSecret {
+		if _, err := t.RedactDSN(cfg.DSN); err != nil {
+			return err
+		}
+	}
+	if len(cfg.Query) > 4096 {
+		return errors.New("database query must be at most 4096 characters")
+	}
+
+	for i := range cfg.Assertions {
+		assertion := &cfg.Assertions[i]
+		assertion.Property = strings.TrimSpace(assertion.Property)
+		// Database assertions share the operators of HTTP assertions; the source is always the probe result
+		httpAssertion := entities.HTTPAssertion{Source: "content_length", Operator: assertion.Operator, Value: assertion.Value}
+		if err := validateHTTPAssertion(&httpAssertion); err != nil {
+			return fmt.Errorf("assertion %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// RedactDSN checks that dsn is a URL for the engine of a database type and returns it without
+// credentials or parameters, as shown in the monitor URL
+func (t *Type) RedactDSN(dsn string) (string, error) {
+	parsed, err := url.Parse(dsn)
+	if err != nil || parsed.Host == "" {
+		return "", fmt.Errorf("invalid connection string. Use format: %s://user:password@host:port/database", t.Name)
+	}
+
+	schemeAllowed := false
+	for _, scheme := range t.DSNSchemes {
+		if parsed.Scheme == scheme {
+			schemeAllowed = true
+		}
+	}
+	if !schemeAllowed {
+		return "", fmt.Errorf("connection string scheme must be one of: %s", strings.Join(t.DSNSchemes, ", "))
+	}
+
+	redacted := url.URL{Scheme: parsed.Scheme, Host: parsed.Host, Path: parsed.Path}
+	return redacted.String(), nil
+}
@@ -0,0 +1,183 @@
+// Package monitortype describes the monitor types: the target each one probes, the config sections
+// it accepts and how they are validated. The API validates monitors with it and the worker looks up
+// the checker of each type by the same names.
+package monitortype
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+)
+
+// Type describes a monitor type
+type Type struct {
+	Name string
+
+	// Sections lists the MonitorConfig sections the type accepts, by JSON name
+	Sections []string
+
+	// TargetOptional allows an empty monitor URL, e.g. for push monitors that are never probed
+	TargetOptional bool
+
+	// DSNSchemes lists the connection string schemes of database types, whose URL is derived
+	// from the encrypted DSN in config.database instead of being set directly
+	DSNSchemes []string
+
+	// CertificateCheck enables the SSL certificate check of https:// and wss:// targets, or of the TLS session
+	// the checker reports, e.g. after STARTTLS
+	CertificateCheck bool
+
+	// Target validates the monitor URL, nil accepts any value
+	Target func(target string) error
+
+	// Config validates and normalizes the type-specific settings after the sections have been
+	// checked, e.g. to require a section or fill in its defaults. Nil accepts any allowed sections.
+	Config func(t *Type, cfg *entities.MonitorConfig) error
+}
+
+// UsesDSN reports whether the type probes a database with a stored connection string
+func (t *Type) UsesDSN() bool {
+	return len(t.DSNSchemes) > 0
+}
+
+// Accepts reports whether the type accepts the config section
+func (t *Type) Accepts(section string) bool {
+	return slices.Contains(t.Sections, section)
+}
+
+// ValidateTarget validates the monitor URL
+func (t *Type) ValidateTarget(target string) error {
+	if t.Target == nil {
+		return nil
+	}
+	return t.Target(target)
+}
+
+// ValidateConfig rejects sections the type does not accept, then validates and normalizes the others
+func (t *Type) ValidateConfig(cfg *entities.MonitorConfig) error {
+	for _, section := range configSections(cfg) {
+		if !t.Accepts(section) {
+			return fmt.Errorf("%s settings are only supported for %s monitors", section, joinNames(typesAccepting(section)))
+		}
+		if validate := sectionValidators[section]; validate != nil {
+			if err := validate(cfg); err != nil {
+				return err
+			}
+		}
+	}
+
+	if t.Config != nil {
+		return t.Config(t, cfg)
+	}
+	return nil
+}
+
+// DropUnsupported removes the sections the type does not accept, e.g. those kept from the previous
+// type of a monitor whose type changed
+func (t *Type) DropUnsupported(cfg *entities.MonitorConfig) {
+	value := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		if !t.Accepts(sectionName(value.Type().Field(i))) {
+			value.Field(i).SetZero()
+		}
+	}
+}
+
+var (
+	mu    sync.RWMutex
+	types = make(map[string]*Type)
+	names []string // registration order
+)
+
+// Register adds a monitor type. It panics if the name is empty or already registered,
+// or if the type accepts a section that MonitorConfig does not have.
+func Register(t *Type) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if t.Name == "" {
+		panic("monitortype: Register called with an empty name")
+	}
+	if _, exists := types[t.Name]; exists {
+		panic(fmt.Sprintf("monitortype: type %q registered twice", t.Name))
+	}
+	for _, section := range t.Sections {
+		if !slices.Contains(allSections, section) {
+			panic(fmt.Sprintf("monitortype: type %q accepts unknown config section %q", t.Name, section))
+		}
+	}
+
+	types[t.Name] = t
+	names = append(names, t.Name)
+}
+
+// Get returns the monitor type with the given name
+func Get(name string) (*Type, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	t, ok := types[name]
+	return t, ok
+}
+
+// Names returns the names of the registered monitor types in registration order
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return slices.Clone(names)
+}
+
+// typesAccepting returns the names of the types accepting the config section
+func typesAccepting(section string) []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	var accepting []string
+	for _, name := range names {
+		if types[name].Accepts(section) {
+			accepting = append(accepting, name)
+		}
+	}
+	return accepting
+}
+
+// joinNames joins names as "a", "a and b" or "a, b and c"
+func joinNames(names []string) string {
+	if len(names) <= 1 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+// allSections lists the JSON names of the MonitorConfig sections
+var allSections = func() []string {
+	configType := reflect.TypeOf(entities.MonitorConfig{})
+	sections := make([]string, configType.NumField())
+	for i := range sections {
+		sections[i] = sectionName(configType.Field(i))
+	}
+	return sections
+}()
+
+// configSections returns the JSON names of the sections set in cfg
+func configSections(cfg *entities.MonitorConfig) []string {
+	value := reflect.ValueOf(cfg).Elem()
+	var sections []string
+	for i := 0; i < value.NumField(); i++ {
+		if !value.Field(i).IsNil() {
+			sections = append(sections, sectionName(value.Type().Field(i)))
+		}
+	}
+	return sections
+}
+
+// sectionName returns the JSON name of a MonitorConfig field
+func sectionName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	return name
+}
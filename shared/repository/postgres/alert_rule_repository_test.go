@@ -1,7 +1,6 @@
 package postgres
 
 import (
-	"database/sql"
 	"testing"
 	"time"
 
@@ -22,7 +21,7 @@ func TestAlertRuleRepository_Create(t *testing.T) {
 
 	rule := &entities.AlertRule{
 		UserID:         1,
-		MonitorID:      sql.NullString{String: "monitor-uuid", Valid: true},
+		MonitorID:      stringPtr("monitor-uuid"),
 		Name:           "Test Alert Rule",
 		TriggerType:    "down",
 		ThresholdValue: 3,
@@ -54,7 +53,7 @@ func TestAlertRuleRepository_GetByID(t *testing.T) {
 	expectedRule := &entities.AlertRule{
 		ID:             "rule-uuid",
 		UserID:         1,
-		MonitorID:      sql.NullString{String: "monitor-uuid", Valid: true},
+		MonitorID:      stringPtr("monitor-uuid"),
 		Name:           "Test Alert Rule",
 		TriggerType:    "down",
 		ThresholdValue: 3,
@@ -113,7 +112,7 @@ func TestAlertRuleRepository_Update(t *testing.T) {
 	rule := &entities.AlertRule{
 		ID:             "rule-uuid",
 		UserID:         1,
-		MonitorID:      sql.NullString{String: "monitor-uuid", Valid: true},
+		MonitorID:      stringPtr("monitor-uuid"),
 		Name:           "Updated Rule",
 		TriggerType:    "slow_response",
 		ThresholdValue: 5000,
@@ -198,3 +197,7 @@ func TestAlertRuleRepository_GetChannelsByRuleID(t *testing.T) {
 	assert.Equal(t, "channel-2", channelIDs[1])
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,221 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+	"github.com/eovipmak/v-insight/shared/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type certificateRepository struct {
+	db *sqlx.DB
+}
+
+// NewCertificateRepository creates a new PostgreSQL certificate repository
+func NewCertificateRepository(db *sqlx.DB) repository.CertificateRepository {
+	return &certificateRepository{db: db}
+}
+
+func (r *certificateRepository) CreateTarget(target *entities.CertificateTarget) error {
+	query := `
+		INSERT INTO certificate_targets (user_id, name, host, port, server_name, starttls, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRow(
+		query,
+		target.UserID,
+		target.Name,
+		target.Host,
+		target.Port,
+		target.ServerName,
+		target.StartTLS,
+	).Scan(&target.ID, &target.CreatedAt, &target.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create certificate target: %w", err)
+	}
+
+	return nil
+}
+
+func (r *certificateRepository) GetTargetByID(id string) (*entities.CertificateTarget, error) {
+	target := &entities.CertificateTarget{}
+	query := `
+		SELECT id, user_id, name, host, port, server_name, starttls, created_at, updated_at
+		FROM certificate_targets
+		WHERE id = $1
+	`
+
+	err := r.db.Get(target, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("certificate target not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get certificate target: %w", err)
+	}
+
+	return target, nil
+}
+
+func (r *certificateRepository) GetTargetsByUserID(userID int) ([]*entities.CertificateTarget, error) {
+	var targets []*entities.CertificateTarget
+	query := `
+		SELECT id, user_id, name, host, port, server_name, starttls, created_at, updated_at
+		FROM certificate_targets
+		WHERE user_id = $1
+		ORDER BY name ASC
+	`
+
+	err := r.db.Select(&targets, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certificate targets: %w", err)
+	}
+
+	return targets, nil
+}
+
+func (r *certificateRepository) DeleteTarget(id string) error {
+	result, err := r.db.Exec(`DELETE FROM certificate_targets WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete certificate target: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("certificate target not found")
+	}
+
+	return nil
+}
+
+func (r *certificateRepository) GetMonitorsDue(checkedBefore time.Time) ([]*entities.Monitor, error) {
+	var monitors []*entities.Monitor
+	query := `
+		SELECT m.id, m.user_id, m.name, m.url, m.type, m.keyword, m.check_interval, m.timeout, m.enabled,
+		       m.check_ssl, m.ssl_alert_days, m.tags, m.expected_status_codes, m.config, m.push_token, m.encrypted_secrets, m.client_certificate_id,
+		       m.last_checked_at, m.created_at, m.updated_at
+		FROM monitors m
+		LEFT JOIN certificates c ON c.monitor_id = m.id
+		WHERE m.enabled = true
+		  AND (
+		    (m.type IN ('http', 'transaction') AND m.url ILIKE 'https://%')
+		    OR (m.type = 'websocket' AND m.url ILIKE 'wss://%')
+		    OR (m.type = 'tcp' AND m.url ILIKE 'tls://%')
+		    OR (m.type = 'grpc' AND m.config->'grpc'->>'tls' = 'true')
+		  )
+		  AND (c.id IS NULL OR c.checked_at < $1)
+		ORDER BY c.checked_at ASC NULLS FIRST
+	`
+
+	err := r.db.Select(&monitors, query, checkedBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get monitors due for certificate check: %w", err)
+	}
+
+	return monitors, nil
+}
+
+func (r *certificateRepository) GetTargetsDue(checkedBefore time.Time) ([]*entities.CertificateTarget, error) {
+	var targets []*entities.CertificateTarget
+	query := `
+		SELECT t.id, t.user_id, t.name, t.host, t.port, t.server_name, t.starttls, t.created_at, t.updated_at
+		FROM certificate_targets t
+		LEFT JOIN certificates c ON c.target_id = t.id
+		WHERE c.id IS NULL OR c.checked_at < $1
+		ORDER BY c.checked_at ASC NULLS FIRST
+	`
+
+	err := r.db.Select(&targets, query, checkedBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certificate targets due for check: %w", err)
+	}
+
+	return targets, nil
+}
+
+// Upsert stores the latest certificate of a monitor or target, replacing the previous one
+func (r *certificateRepository) Upsert(certificate *entities.Certificate) error {
+	conflict := "(monitor_id) WHERE monitor_id IS NOT NULL"
+	if certificate.MonitorID == nil {
+		conflict = "(target_id) WHERE target_id IS NOT NULL"
+	}
+
+	query := `
+		INSERT INTO certificates (user_id, monitor_id, target_id, host, port, subject, issuer, sans,
+		                          not_before, not_after, expires_at, fingerprint, valid, error, details,
+		                          checked_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, NOW(), NOW())
+		ON CONFLICT ` + conflict + ` DO UPDATE SET
+			host = EXCLUDED.host,
+			port = EXCLUDED.port,
+			subject = EXCLUDED.subject,
+			issuer = EXCLUDED.issuer,
+			sans = EXCLUDED.sans,
+			not_before = EXCLUDED.not_before,
+			not_after = EXCLUDED.not_after,
+			expires_at = EXCLUDED.expires_at,
+			fingerprint = EXCLUDED.fingerprint,
+			valid = EXCLUDED.valid,
+			error = EXCLUDED.error,
+			details = EXCLUDED.details,
+			checked_at = EXCLUDED.checked_at
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRow(
+		query,
+		certificate.UserID,
+		certificate.MonitorID,
+		certificate.TargetID,
+		certificate.Host,
+		certificate.Port,
+		certificate.Subject,
+		certificate.Issuer,
+		certificate.SANs,
+		certificate.NotBefore,
+		certificate.NotAfter,
+		certificate.ExpiresAt,
+		certificate.Fingerprint,
+		certificate.Valid,
+		certificate.Error,
+		certificate.Details,
+		certificate.CheckedAt,
+	).Scan(&certificate.ID, &certificate.CreatedAt, &certificate.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save certificate: %w", err)
+	}
+
+	return nil
+}
+
+// GetByUserID returns the inventory of a user, soonest expiry first
+func (r *certificateRepository) GetByUserID(userID int) ([]*entities.Certificate, error) {
+	var certificates []*entities.Certificate
+	query := `
+		SELECT c.id, c.user_id, c.monitor_id, c.target_id, COALESCE(m.name, t.name, '') AS source_name,
+		       c.host, c.port, c.subject, c.issuer, c.sans, c.not_before, c.not_after, c.expires_at,
+		       c.fingerprint, c.valid, c.error, c.details, c.checked_at, c.created_at, c.updated_at
+		FROM certificates c
+		LEFT JOIN monitors m ON m.id = c.monitor_id
+		LEFT JOIN certificate_targets t ON t.id = c.target_id
+		WHERE c.user_id = $1
+		ORDER BY c.expires_at ASC NULLS LAST, source_name ASC
+	`
+
+	err := r.db.Select(&certificates, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certificates: %w", err)
+	}
+
+	return certificates, nil
+}
@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+	"github.com/eovipmak/v-insight/shared/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+// checkEvidenceRepository implements the CheckEvidenceRepository interface using PostgreSQL
+type checkEvidenceRepository struct {
+	db *sqlx.DB
+}
+
+// NewCheckEvidenceRepository creates a new PostgreSQL check evidence repository
+func NewCheckEvidenceRepository(db *sqlx.DB) repository.CheckEvidenceRepository {
+	return &checkEvidenceRepository{db: db}
+}
+
+// Create stores the evidence of a saved check, CreatedAt is the time of the check
+func (r *checkEvidenceRepository) Create(evidence *entities.CheckEvidence) error {
+	query := `
+		INSERT INTO check_evidence (check_id, monitor_id, remote_ip, headers, body_snippet, body_truncated, tls, created_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.Exec(
+		query,
+		evidence.CheckID,
+		evidence.MonitorID,
+		evidence.RemoteIP,
+		evidence.Headers,
+		evidence.BodySnippet,
+		evidence.BodyTruncated,
+		evidence.TLS,
+		evidence.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create check evidence: %w", err)
+	}
+
+	return nil
+}
+
+// GetByCheckID retrieves the evidence of a check
+func (r *checkEvidenceRepository) GetByCheckID(checkID string) (*entities.CheckEvidence, error) {
+	evidence := &entities.CheckEvidence{}
+	query := `
+		SELECT check_id, monitor_id, COALESCE(remote_ip, '') AS remote_ip, headers, body_snippet, body_truncated, tls, created_at
+		FROM check_evidence
+		WHERE check_id = $1
+	`
+
+	err := r.db.Get(evidence, query, checkID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("check evidence not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get check evidence: %w", err)
+	}
+
+	return evidence, nil
+}
+
+// DeleteOlderThan removes the evidence stored before cutoff
+func (r *checkEvidenceRepository) DeleteOlderThan(cutoff time.Time) (int64, error) {
+	query := `DELETE FROM check_evidence WHERE created_at < $1`
+
+	result, err := r.db.Exec(query, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete check evidence: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+	"github.com/eovipmak/v-insight/shared/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+// clientCertificateRepository implements the ClientCertificateRepository interface using PostgreSQL
+type clientCertificateRepository struct {
+	db *sqlx.DB
+}
+
+// NewClientCertificateRepository creates a new PostgreSQL client certificate repository
+func NewClientCertificateRepository(db *sqlx.DB) repository.ClientCertificateRepository {
+	return &clientCertificateRepository{db: db}
+}
+
+// Create stores a new client certificate
+func (r *clientCertificateRepository) Create(certificate *entities.ClientCertificate) error {
+	query := `
+		INSERT INTO client_certificates (user_id, name, subject, issuer, fingerprint, not_before, not_after, encrypted_secrets, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRow(
+		query,
+		certificate.UserID,
+		certificate.Name,
+		certificate.Subject,
+		certificate.Issuer,
+		certificate.Fingerprint,
+		certificate.NotBefore,
+		certificate.NotAfter,
+		certificate.EncryptedSecrets,
+	).Scan(&certificate.ID, &certificate.CreatedAt, &certificate.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create client certificate: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a client certificate, including its encrypted secrets, by its ID
+func (r *clientCertificateRepository) GetByID(id string) (*entities.ClientCertificate, error) {
+	certificate := &entities.ClientCertificate{}
+	query := `
+		SELECT id, user_id, name, subject, issuer, fingerprint, not_before, not_after, encrypted_secrets, created_at, updated_at
+		FROM client_certificates
+		WHERE id = $1
+	`
+
+	err := r.db.Get(certificate, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("client certificate not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get client certificate: %w", err)
+	}
+
+	return certificate, nil
+}
+
+// GetByUserID retrieves the client certificates of a user, soonest expiry first
+func (r *clientCertificateRepository) GetByUserID(userID int) ([]*entities.ClientCertificate, error) {
+	var certificates []*entities.ClientCertificate
+	query := `
+		SELECT id, user_id, name, subject, issuer, fingerprint, not_before, not_after, encrypted_secrets, created_at, updated_at
+		FROM client_certificates
+		WHERE user_id = $1
+		ORDER BY not_after ASC
+	`
+
+	err := r.db.Select(&certificates, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client certificates: %w", err)
+	}
+
+	return certificates, nil
+}
+
+// Delete deletes a client certificate; monitors using it are detached by the foreign key
+func (r *clientCertificateRepository) Delete(id string) error {
+	result, err := r.db.Exec(`DELETE FROM client_certificates WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete client certificate: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("client certificate not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+	"github.com/eovipmak/v-insight/shared/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+// contentBaselineRepository implements the ContentBaselineRepository interface using PostgreSQL
+type contentBaselineRepository struct {
+	db *sqlx.DB
+}
+
+// NewContentBaselineRepository creates a new PostgreSQL content baseline repository
+func NewContentBaselineRepository(db *sqlx.DB) repository.ContentBaselineRepository {
+	return &contentBaselineRepository{db: db}
+}
+
+// GetByMonitorID retrieves the baseline and latest snapshot of a monitor
+func (r *contentBaselineRepository) GetByMonitorID(monitorID string) (*entities.ContentBaseline, error) {
+	baseline := &entities.ContentBaseline{}
+	query := `
+		SELECT monitor_id, config_hash, baseline_hash, baseline_content, accepted_at,
+		       current_hash, current_content, checked_at, created_at, updated_at
+		FROM content_baselines
+		WHERE monitor_id = $1
+	`
+
+	err := r.db.Get(baseline, query, monitorID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("content baseline not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get content baseline: %w", err)
+	}
+
+	return baseline, nil
+}
+
+// Reset stores the content as both the baseline and the latest snapshot of a monitor
+func (r *contentBaselineRepository) Reset(baseline *entities.ContentBaseline) error {
+	query := `
+		INSERT INTO content_baselines (monitor_id, config_hash, baseline_hash, baseline_content, accepted_at,
+		                               current_hash, current_content, checked_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $3, $4, $5, NOW(), NOW())
+		ON CONFLICT (monitor_id) DO UPDATE SET
+			config_hash = EXCLUDED.config_hash,
+			baseline_hash = EXCLUDED.baseline_hash,
+			baseline_content = EXCLUDED.baseline_content,
+			accepted_at = EXCLUDED.accepted_at,
+			current_hash = EXCLUDED.current_hash,
+			current_content = EXCLUDED.current_content,
+			checked_at = EXCLUDED.checked_at
+		RETURNING created_at, updated_at
+	`
+
+	err := r.db.QueryRow(
+		query,
+		baseline.MonitorID,
+		baseline.ConfigHash,
+		baseline.BaselineHash,
+		baseline.BaselineContent,
+		baseline.AcceptedAt,
+	).Scan(&baseline.CreatedAt, &baseline.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to reset content baseline: %w", err)
+	}
+
+	baseline.CurrentHash = baseline.BaselineHash
+	baseline.CurrentContent = baseline.BaselineContent
+	baseline.CheckedAt = baseline.AcceptedAt
+	return nil
+}
+
+// UpdateCurrent records the latest snapshot without touching the baseline, so that a
+// concurrent Accept is not overwritten
+func (r *contentBaselineRepository) UpdateCurrent(monitorID, hash, content string, checkedAt time.Time) error {
+	query := `
+		UPDATE content_baselines
+		SET current_hash = $2, current_content = $3, checked_at = $4
+		WHERE monitor_id = $1
+	`
+
+	result, err := r.db.Exec(query, monitorID, hash, content, checkedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update content snapshot: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("content baseline not found")
+	}
+
+	return nil
+}
+
+// Accept makes the latest snapshot the baseline of a monitor
+func (r *contentBaselineRepository) Accept(monitorID string) error {
+	query := `
+		UPDATE content_baselines
+		SET baseline_hash = current_hash, baseline_content = current_content, accepted_at = NOW()
+		WHERE monitor_id = $1
+	`
+
+	result, err := r.db.Exec(query, monitorID)
+	if err != nil {
+		return fmt.Errorf("failed to accept content snapshot: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("content baseline not found")
+	}
+
+	return nil
+}
@@ -23,8 +23,8 @@ func NewIncidentRepository(db *sqlx.DB) repository.IncidentRepository {
 // Create creates a new incident in the database
 func (r *incidentRepository) Create(incident *entities.Incident) error {
 	query := `
-		INSERT INTO incidents (monitor_id, alert_rule_id, user_id, started_at, status, trigger_value, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		INSERT INTO incidents (monitor_id, alert_rule_id, user_id, started_at, status, trigger_value, check_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
 		RETURNING id, created_at
 	`
 
@@ -36,6 +36,7 @@ func (r *incidentRepository) Create(incident *entities.Incident) error {
 		incident.StartedAt,
 		incident.Status,
 		incident.TriggerValue,
+		incident.CheckID,
 	).Scan(&incident.ID, &incident.CreatedAt)
 
 	if err != nil {
@@ -246,6 +247,7 @@ func (r *incidentRepository) GetUnnotifiedIncidents() ([]*entities.Incident, err
 			ar.trigger_type,
 			i.status,
 			i.trigger_value,
+			i.check_id,
 			i.started_at,
 			i.created_at
 		FROM incidents i
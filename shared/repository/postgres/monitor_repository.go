@@ -23,8 +23,8 @@ func NewMonitorRepository(db *sqlx.DB) repository.MonitorRepository {
 // Create creates a new monitor in the database
 func (r *monitorRepository) Create(monitor *entities.Monitor) error {
 	query := `
-		INSERT INTO monitors (user_id, name, url, type, keyword, check_interval, timeout, enabled, check_ssl, ssl_alert_days, tags, expected_status_codes, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW(), NOW())
+		INSERT INTO monitors (user_id, name, url, type, keyword, check_interval, timeout, enabled, check_ssl, ssl_alert_days, tags, expected_status_codes, config, push_token, encrypted_secrets, client_certificate_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, NOW(), NOW())
 		RETURNING id, created_at, updated_at
 	`
 
@@ -42,6 +42,10 @@ func (r *monitorRepository) Create(monitor *entities.Monitor) error {
 		monitor.SSLAlertDays,
 		monitor.Tags,
 		monitor.ExpectedStatusCodes,
+		monitor.Config,
+		monitor.PushToken,
+		monitor.EncryptedSecrets,
+		monitor.ClientCertificateID,
 	).Scan(&monitor.ID, &monitor.CreatedAt, &monitor.UpdatedAt)
 
 	if err != nil {
@@ -56,7 +60,7 @@ func (r *monitorRepository) GetByID(id string) (*entities.Monitor, error) {
 	monitor := &entities.Monitor{}
 	query := `
 		SELECT id, user_id, name, url, type, keyword, check_interval, timeout, enabled,
-		       check_ssl, ssl_alert_days, tags, expected_status_codes, last_checked_at, created_at, updated_at
+		       check_ssl, ssl_alert_days, tags, expected_status_codes, config, push_token, encrypted_secrets, client_certificate_id, last_checked_at, created_at, updated_at
 		FROM monitors
 		WHERE id = $1
 	`
@@ -72,12 +76,33 @@ func (r *monitorRepository) GetByID(id string) (*entities.Monitor, error) {
 	return monitor, nil
 }
 
+// GetByPushToken retrieves a push monitor by the token of its heartbeat URL
+func (r *monitorRepository) GetByPushToken(token string) (*entities.Monitor, error) {
+	monitor := &entities.Monitor{}
+	query := `
+		SELECT id, user_id, name, url, type, keyword, check_interval, timeout, enabled,
+		       check_ssl, ssl_alert_days, tags, expected_status_codes, config, push_token, encrypted_secrets, client_certificate_id, last_checked_at, created_at, updated_at
+		FROM monitors
+		WHERE push_token = $1 AND type = 'push'
+	`
+
+	err := r.db.Get(monitor, query, token)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("monitor not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get monitor: %w", err)
+	}
+
+	return monitor, nil
+}
+
 // GetByUserID retrieves all monitors for a specific user
 func (r *monitorRepository) GetByUserID(userID int) ([]*entities.Monitor, error) {
 	var monitors []*entities.Monitor
 	query := `
 		SELECT id, user_id, name, url, type, keyword, check_interval, timeout, enabled,
-		       check_ssl, ssl_alert_days, tags, expected_status_codes, last_checked_at, created_at, updated_at
+		       check_ssl, ssl_alert_days, tags, expected_status_codes, config, push_token, encrypted_secrets, client_certificate_id, last_checked_at, created_at, updated_at
 		FROM monitors
 		WHERE user_id = $1
 		ORDER BY created_at DESC
@@ -96,7 +121,7 @@ func (r *monitorRepository) GetAll() ([]*entities.Monitor, error) {
 	var monitors []*entities.Monitor
 	query := `
 		SELECT id, user_id, name, url, type, keyword, check_interval, timeout, enabled,
-		       check_ssl, ssl_alert_days, tags, expected_status_codes, last_checked_at, created_at, updated_at
+		       check_ssl, ssl_alert_days, tags, expected_status_codes, config, push_token, encrypted_secrets, client_certificate_id, last_checked_at, created_at, updated_at
 		FROM monitors
 		ORDER BY created_at DESC
 	`
@@ -114,8 +139,8 @@ func (r *monitorRepository) Update(monitor *entities.Monitor) error {
 	query := `
 		UPDATE monitors
 		SET name = $1, url = $2, type = $3, keyword = $4, check_interval = $5, timeout = $6, enabled = $7,
-		    check_ssl = $8, ssl_alert_days = $9, tags = $10, expected_status_codes = $11, updated_at = NOW()
-		WHERE id = $12
+		    check_ssl = $8, ssl_alert_days = $9, tags = $10, expected_status_codes = $11, config = $12, push_token = $13, encrypted_secrets = $14, client_certificate_id = $15, updated_at = NOW()
+		WHERE id = $16
 		RETURNING updated_at
 	`
 
@@ -132,6 +157,10 @@ func (r *monitorRepository) Update(monitor *entities.Monitor) error {
 		monitor.SSLAlertDays,
 		monitor.Tags,
 		monitor.ExpectedStatusCodes,
+		monitor.Config,
+		monitor.PushToken,
+		monitor.EncryptedSecrets,
+		monitor.ClientCertificateID,
 		monitor.ID,
 	).Scan(&monitor.UpdatedAt)
 
@@ -176,8 +205,9 @@ func (r *monitorRepository) GetChecksByMonitorID(monitorID string, limit int) ([
 	}
 	
 	query := `
-		SELECT id, monitor_id, checked_at, status_code, response_time_ms, 
-		       ssl_valid, ssl_expires_at, error_message, success
+		SELECT id, monitor_id, checked_at, status_code, response_time_ms,
+		       dns_lookup_ms, tcp_connect_ms, tls_handshake_ms, ttfb_ms, transfer_ms,
+		       ssl_valid, ssl_expires_at, error_message, details, success
 		FROM monitor_checks
 		WHERE monitor_id = $1
 		ORDER BY checked_at DESC
@@ -192,6 +222,53 @@ func (r *monitorRepository) GetChecksByMonitorID(monitorID string, limit int) ([
 	return checks, nil
 }
 
+// GetCheckByID retrieves a single check
+func (r *monitorRepository) GetCheckByID(checkID string) (*entities.MonitorCheck, error) {
+	check := &entities.MonitorCheck{}
+	query := `
+		SELECT id, monitor_id, checked_at, status_code, response_time_ms,
+		       dns_lookup_ms, tcp_connect_ms, tls_handshake_ms, ttfb_ms, transfer_ms,
+		       ssl_valid, ssl_expires_at, error_message, details, success
+		FROM monitor_checks
+		WHERE id = $1
+	`
+
+	err := r.db.Get(check, query, checkID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("monitor check not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get monitor check: %w", err)
+	}
+
+	return check, nil
+}
+
+// GetLatestSSLCheck retrieves the most recent check with SSL information for a monitor
+func (r *monitorRepository) GetLatestSSLCheck(monitorID string) (*entities.MonitorCheck, error) {
+	check := &entities.MonitorCheck{}
+	query := `
+		SELECT id, monitor_id, checked_at, status_code, response_time_ms,
+		       dns_lookup_ms, tcp_connect_ms, tls_handshake_ms, ttfb_ms, transfer_ms,
+		       ssl_valid, ssl_expires_at, error_message, details, success
+		FROM monitor_checks
+		WHERE monitor_id = $1
+		  AND ssl_valid IS NOT NULL
+		ORDER BY checked_at DESC
+		LIMIT 1
+	`
+
+	err := r.db.Get(check, query, monitorID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No SSL check yet, not an error
+		}
+		return nil, fmt.Errorf("failed to get latest SSL check: %w", err)
+	}
+
+	return check, nil
+}
+
 // GetMonitorsNeedingCheck retrieves enabled monitors that need to be checked
 // A monitor needs checking if:
 // - It is enabled
@@ -201,7 +278,7 @@ func (r *monitorRepository) GetMonitorsNeedingCheck(now time.Time) ([]*entities.
 	var monitors []*entities.Monitor
 	query := `
 		SELECT id, user_id, name, url, type, keyword, check_interval, timeout, enabled,
-		       check_ssl, ssl_alert_days, tags, expected_status_codes, last_checked_at, created_at, updated_at
+		       check_ssl, ssl_alert_days, tags, expected_status_codes, config, push_token, encrypted_secrets, client_certificate_id, last_checked_at, created_at, updated_at
 		FROM monitors
 		WHERE enabled = true
 		  AND (
@@ -223,10 +300,11 @@ func (r *monitorRepository) GetMonitorsNeedingCheck(now time.Time) ([]*entities.
 func (r *monitorRepository) SaveCheck(check *entities.MonitorCheck) error {
 	query := `
 		INSERT INTO monitor_checks (
-			monitor_id, checked_at, status_code, response_time_ms, 
-			ssl_valid, ssl_expires_at, error_message, success
+			monitor_id, checked_at, status_code, response_time_ms,
+			dns_lookup_ms, tcp_connect_ms, tls_handshake_ms, ttfb_ms, transfer_ms,
+			ssl_valid, ssl_expires_at, error_message, details, success
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		RETURNING id
 	`
 
@@ -236,9 +314,15 @@ func (r *monitorRepository) SaveCheck(check *entities.MonitorCheck) error {
 		check.CheckedAt,
 		check.StatusCode,
 		check.ResponseTimeMs,
+		check.DNSLookupMs,
+		check.TCPConnectMs,
+		check.TLSHandshakeMs,
+		check.TTFBMs,
+		check.TransferMs,
 		check.SSLValid,
 		check.SSLExpiresAt,
 		check.ErrorMessage,
+		check.Details,
 		check.Success,
 	).Scan(&check.ID)
 
@@ -305,7 +389,7 @@ func (r *monitorRepository) GetLatestMonitorChecks(duration time.Duration) ([]*e
 	query := `
 		SELECT DISTINCT ON (mc.monitor_id)
 			mc.id, mc.monitor_id, m.user_id, m.type as monitor_type, mc.checked_at, mc.status_code, mc.response_time_ms,
-			mc.ssl_valid, mc.ssl_expires_at, mc.error_message, mc.success
+			mc.ssl_valid, mc.ssl_expires_at, mc.error_message, mc.details, mc.success
 		FROM monitor_checks mc
 		JOIN monitors m ON mc.monitor_id = m.id
 		WHERE mc.checked_at >= $1
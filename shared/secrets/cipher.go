@@ -0,0 +1,89 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Cipher encrypts monitor secrets (DSNs, passwords) before they are stored in the database
+// It uses AES-256-GCM with a key derived from the configured passphrase
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher creates a cipher from a passphrase (SECRETS_ENCRYPTION_KEY)
+func NewCipher(passphrase string) (*Cipher, error) {
+	if passphrase == "" {
+		return nil, errors.New("encryption key is required")
+	}
+
+	key := sha256.Sum256([]byte(passphrase))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return &Cipher{aead: aead}, nil
+}
+
+// Encrypt encrypts plaintext and returns it base64 encoded with a random nonce prepended
+func (c *Cipher) Encrypt(plaintext []byte) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, plaintext, nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt reverses Encrypt; it fails if the value was encrypted with another key or modified
+func (c *Cipher) Decrypt(encoded string) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode secret: %w", err)
+	}
+
+	nonceSize := c.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, errors.New("failed to decrypt secret: value too short")
+	}
+
+	plaintext, err := c.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt secret: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// EncryptJSON encodes v as JSON and encrypts it
+func (c *Cipher) EncryptJSON(v interface{}) (string, error) {
+	plaintext, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode secret: %w", err)
+	}
+	return c.Encrypt(plaintext)
+}
+
+// DecryptJSON decrypts a value produced by EncryptJSON into v
+func (c *Cipher) DecryptJSON(encoded string, v interface{}) error {
+	plaintext, err := c.Decrypt(encoded)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(plaintext, v); err != nil {
+		return fmt.Errorf("failed to decode secret: %w", err)
+	}
+	return nil
+}
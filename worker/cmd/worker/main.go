@@ -12,6 +12,7 @@ import (
 	"github.com/eovipmak/v-insight/worker/internal"
 	"github.com/eovipmak/v-insight/worker/internal/config"
 	"github.com/eovipmak/v-insight/shared/repository/postgres"
+	"github.com/eovipmak/v-insight/shared/secrets"
 	"github.com/eovipmak/v-insight/worker/internal/database"
 	"github.com/eovipmak/v-insight/worker/internal/executor"
 	"github.com/eovipmak/v-insight/worker/internal/jobs"
@@ -83,19 +84,30 @@ func main() {
 	alertRuleRepo := postgres.NewAlertRuleRepository(db.DB)
 	incidentRepo := postgres.NewIncidentRepository(db.DB)
 	alertChannelRepo := postgres.NewAlertChannelRepository(db.DB)
+	certificateRepo := postgres.NewCertificateRepository(db.DB)
+	clientCertificateRepo := postgres.NewClientCertificateRepository(db.DB)
+	contentBaselineRepo := postgres.NewContentBaselineRepository(db.DB)
+	checkEvidenceRepo := postgres.NewCheckEvidenceRepository(db.DB)
+
+	// Database monitor DSNs and client certificates are encrypted by the backend with the same key
+	secretsCipher, err := secrets.NewCipher(cfg.Security.EncryptionKey)
+	if err != nil {
+		log.Fatalf("Failed to initialize secrets cipher: %v", err)
+	}
 
 	// Register jobs
-	healthCheckJob := jobs.NewHealthCheckJob(monitorRepo)
-	sslCheckJob := jobs.NewSSLCheckJob(db)
+	healthCheckJob := jobs.NewHealthCheckJob(monitorRepo, clientCertificateRepo, contentBaselineRepo, checkEvidenceRepo, secretsCipher, cfg.Domain, cfg.Evidence, cfg.Docker)
+	sslCheckJob := jobs.NewSSLCheckJob(certificateRepo, clientCertificateRepo, secretsCipher)
 	alertEvaluatorJob := jobs.NewAlertEvaluatorJob(alertRuleRepo, incidentRepo, monitorRepo)
-	notificationJob := jobs.NewNotificationJob(incidentRepo, alertChannelRepo, cfg.SMTP)
+	notificationJob := jobs.NewNotificationJob(incidentRepo, alertChannelRepo, checkEvidenceRepo, cfg.SMTP, cfg.Worker.AppURL)
+	evidenceRetentionJob := jobs.NewEvidenceRetentionJob(checkEvidenceRepo, cfg.Evidence.Retention)
 
 	// Schedule health check job to run every 30 seconds
 	if err := sched.AddJob("*/30 * * * * *", healthCheckJob); err != nil {
 		log.Fatalf("Failed to schedule health check job: %v", err)
 	}
 
-	// Schedule SSL check job to run every 5 minutes
+	// Schedule SSL check job to run every 5 minutes, each certificate is refreshed every 12 hours
 	if err := sched.AddJob("*/5 * * * *", sslCheckJob); err != nil {
 		log.Fatalf("Failed to schedule SSL check job: %v", err)
 	}
@@ -110,6 +122,11 @@ func main() {
 		log.Fatalf("Failed to schedule notification job: %v", err)
 	}
 
+	// Schedule evidence retention job to run every hour
+	if err := sched.AddJob("0 * * * *", evidenceRetentionJob); err != nil {
+		log.Fatalf("Failed to schedule evidence retention job: %v", err)
+	}
+
 	// Start the scheduler
 	sched.Start()
 	defer sched.Stop()
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -14,6 +15,10 @@ type Config struct {
 	Database DatabaseConfig
 	Worker   WorkerConfig
 	SMTP     SMTPConfig
+	Security SecurityConfig
+	Domain   DomainConfig
+	Evidence EvidenceConfig
+	Docker   DockerConfig
 }
 
 // DatabaseConfig holds database connection configuration
@@ -31,8 +36,9 @@ type DatabaseConfig struct {
 
 // WorkerConfig holds worker service configuration
 type WorkerConfig struct {
-	Port string
-	Env  string
+	Port   string
+	Env    string
+	AppURL string // public URL of the frontend, used for links in notifications
 }
 
 // SMTPConfig holds email configuration
@@ -44,6 +50,29 @@ type SMTPConfig struct {
 	From     string
 }
 
+// SecurityConfig holds settings for stored monitor credentials
+type SecurityConfig struct {
+	EncryptionKey string // must match the backend's SECRETS_ENCRYPTION_KEY
+}
+
+// DomainConfig holds the registration data servers used by domain monitors
+type DomainConfig struct {
+	RDAPURL     string // RDAP base URL for every domain, IANA bootstrap when empty
+	WHOISServer string // WHOIS host[:port] for every domain, IANA referral when empty
+}
+
+// EvidenceConfig holds what is kept of the responses of HTTP checks
+type EvidenceConfig struct {
+	BodyBytes         int           // bytes of the response body kept
+	SuccessSampleRate float64       // fraction (0-1) of successful checks kept, failed checks are always kept
+	Retention         time.Duration // evidence older than this is deleted
+}
+
+// DockerConfig holds the local Docker daemons docker monitors may inspect
+type DockerConfig struct {
+	Sockets []string // unix socket paths allowed as monitor endpoints, none when empty
+}
+
 // Load loads configuration from environment variables
 func Load() (*Config, error) {
 	// Try to load .env file (ignore errors as it may not exist in production)
@@ -62,8 +91,9 @@ func Load() (*Config, error) {
 			ConnMaxLifetime: getEnvAsDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute),
 		},
 		Worker: WorkerConfig{
-			Port: getEnv("PORT", "8081"),
-			Env:  getEnv("ENV", "development"),
+			Port:   getEnv("PORT", "8081"),
+			Env:    getEnv("ENV", "development"),
+			AppURL: strings.TrimRight(getEnv("APP_URL", "http://localhost:3000"), "/"),
 		},
 		SMTP: SMTPConfig{
 			Host:     getEnv("SMTP_HOST", ""),
@@ -72,6 +102,21 @@ func Load() (*Config, error) {
 			Password: getEnv("SMTP_PASSWORD", ""),
 			From:     getEnv("SMTP_FROM", "alerts@v-insight.com"),
 		},
+		Security: SecurityConfig{
+			EncryptionKey: getEnv("SECRETS_ENCRYPTION_KEY", "dev-encryption-key-change-in-production"),
+		},
+		Domain: DomainConfig{
+			RDAPURL:     getEnv("DOMAIN_RDAP_URL", ""),
+			WHOISServer: getEnv("DOMAIN_WHOIS_SERVER", ""),
+		},
+		Evidence: EvidenceConfig{
+			BodyBytes:         getEnvAsInt("EVIDENCE_BODY_KB", 16) * 1024,
+			SuccessSampleRate: getEnvAsFloat("EVIDENCE_SUCCESS_SAMPLE_RATE", 0),
+			Retention:         time.Duration(getEnvAsInt("EVIDENCE_RETENTION_DAYS", 7)) * 24 * time.Hour,
+		},
+		Docker: DockerConfig{
+			Sockets: getEnvAsList("DOCKER_SOCKETS"),
+		},
 	}
 
 	return cfg, nil
@@ -99,6 +144,30 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return value
 }
 
+// getEnvAsFloat gets an environment variable as a float or returns a default value
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvAsList gets a comma-separated environment variable as a list, empty entries are skipped
+func getEnvAsList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 // getEnvAsDuration gets an environment variable as a duration or returns a default value
 func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	valueStr := os.Getenv(key)
@@ -131,5 +200,14 @@ func (c *Config) Validate() error {
 	if c.Database.DBName == "" {
 		return fmt.Errorf("database name is required")
 	}
+	if c.Evidence.BodyBytes < 0 {
+		return fmt.Errorf("EVIDENCE_BODY_KB must not be negative")
+	}
+	if c.Evidence.SuccessSampleRate < 0 || c.Evidence.SuccessSampleRate > 1 {
+		return fmt.Errorf("EVIDENCE_SUCCESS_SAMPLE_RATE must be between 0 and 1")
+	}
+	if c.Evidence.Retention <= 0 {
+		return fmt.Errorf("EVIDENCE_RETENTION_DAYS must be positive")
+	}
 	return nil
 }
@@ -0,0 +1,115 @@
+package executor
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+	"github.com/eovipmak/v-insight/shared/monitortype"
+)
+
+// Checker checks the monitors of one monitor type
+type Checker interface {
+	// Type describes the monitor type: its target, config schema and validation
+	Type() *monitortype.Type
+	// Check probes the monitor once
+	Check(ctx context.Context, req *CheckRequest) *CheckResult
+}
+
+// CheckRequest is a monitor to check, with the credentials loaded for it
+type CheckRequest struct {
+	Monitor           *entities.Monitor
+	ClientCertificate *tls.Certificate         // presented for mutual TLS, nil when the monitor has none
+	Secrets           *entities.MonitorSecrets // decrypted secrets, nil when the monitor has none
+	CheckedAt         time.Time
+}
+
+// Timeout returns the timeout of the monitor
+func (r *CheckRequest) Timeout() time.Duration {
+	return time.Duration(r.Monitor.Timeout) * time.Second
+}
+
+// Proxy returns the proxy of the monitor with its password, nil when the monitor connects directly
+func (r *CheckRequest) Proxy() *entities.ProxyConfig {
+	return MonitorProxy(r.Monitor, r.Secrets)
+}
+
+// HTTPConfig returns the request settings of the monitor with its credentials, nil when it has none
+func (r *CheckRequest) HTTPConfig() *entities.HTTPConfig {
+	if r.Monitor.Config.HTTP == nil {
+		return nil
+	}
+	httpConfig := r.Monitor.Config.HTTP.WithSecrets(r.Secrets)
+	return &httpConfig
+}
+
+// CheckResult is the outcome of a check, the same for every monitor type
+type CheckResult struct {
+	Success      bool
+	ResponseTime time.Duration
+	StatusCode   int // HTTP status code, 0 for other protocols
+	Error        error
+	Details      *entities.CheckDetails // type-specific details stored with the check
+
+	// Skipped is set when there is nothing to record yet, e.g. a push monitor within its grace period
+	Skipped bool
+
+	// HTTP is the response of http monitors, used for timing, content change detection and evidence
+	HTTP *HTTPCheckResult
+
+	// SSL is the certificate of a TLS session negotiated by the check itself, e.g. after STARTTLS,
+	// recorded instead of checking an https:// URL
+	SSL *SSLCheckResult
+}
+
+// Registry holds the checker of each monitor type
+type Registry struct {
+	checkers map[string]Checker
+}
+
+// NewRegistry creates a registry with the given checkers, it panics if two check the same type
+func NewRegistry(checkers ...Checker) *Registry {
+	r := &Registry{checkers: make(map[string]Checker)}
+	for _, checker := range checkers {
+		if err := r.Register(checker); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// Register adds the checker of a monitor type
+// The type must be registered with monitortype so that the API accepts its monitors
+func (r *Registry) Register(checker Checker) error {
+	t := checker.Type()
+	if registered, ok := monitortype.Get(t.Name); !ok || registered != t {
+		return fmt.Errorf("monitor type %q is not registered", t.Name)
+	}
+	if _, exists := r.checkers[t.Name]; exists {
+		return fmt.Errorf("a checker for monitor type %q is already registered", t.Name)
+	}
+	r.checkers[t.Name] = checker
+	return nil
+}
+
+// Get returns the checker of a monitor type
+func (r *Registry) Get(monitorType string) (Checker, bool) {
+	checker, ok := r.checkers[monitorType]
+	return checker, ok
+}
+
+// builtinType returns a monitor type registered by the monitortype package
+func builtinType(name string) *monitortype.Type {
+	t, ok := monitortype.Get(name)
+	if !ok {
+		panic(fmt.Sprintf("executor: monitor type %q is not registered", name))
+	}
+	return t
+}
+
+// DurationToMs converts a duration to fractional milliseconds rounded to microsecond precision
+func DurationToMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
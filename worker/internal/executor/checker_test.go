@@ -0,0 +1,50 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/eovipmak/v-insight/shared/monitortype"
+)
+
+// unregisteredChecker checks a monitor type the API does not know about
+type unregisteredChecker struct{}
+
+func (unregisteredChecker) Type() *monitortype.Type {
+	return &monitortype.Type{Name: "gopher"}
+}
+
+func (unregisteredChecker) Check(ctx context.Context, req *CheckRequest) *CheckResult {
+	return &CheckResult{Success: true}
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry(NewHTTPMonitorChecker(NewHTTPChecker()), NewPushMonitorChecker())
+
+	checker, ok := registry.Get("http")
+	if !ok {
+		t.Fatal("Expected the http checker to be registered")
+	}
+	if checker.Type().Name != "http" {
+		t.Errorf("Expected the checker of http monitors, got %s", checker.Type().Name)
+	}
+	if _, ok := registry.Get("tcp"); ok {
+		t.Errorf("Expected no checker for tcp monitors")
+	}
+
+	if err := registry.Register(NewPushMonitorChecker()); err == nil {
+		t.Errorf("Expected an error when registering a second push checker")
+	}
+	if err := registry.Register(unregisteredChecker{}); err == nil {
+		t.Errorf("Expected an error for a monitor type unknown to monitortype")
+	}
+}
+
+func TestNewRegistry_DuplicatePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected NewRegistry to panic on duplicate checkers")
+		}
+	}()
+	NewRegistry(NewDNSMonitorChecker(NewDNSChecker()), NewDNSMonitorChecker(NewDNSChecker()))
+}
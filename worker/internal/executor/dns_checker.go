@@ -0,0 +1,233 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+)
+
+// DNSCheckResult represents the result of a DNS record check
+type DNSCheckResult struct {
+	Answers      []string
+	Resolver     string
+	ResponseTime time.Duration
+	Error        error
+	Success      bool
+}
+
+// DNSChecker performs DNS record checks
+type DNSChecker struct{}
+
+// NewDNSChecker creates a new DNS checker
+func NewDNSChecker() *DNSChecker {
+	return &DNSChecker{}
+}
+
+// Check resolves the configured record type for host and compares the answers with the expected values
+// When no expected values are configured, the check succeeds if the lookup returns at least one answer
+func (c *DNSChecker) Check(ctx context.Context, host string, cfg entities.DNSConfig, timeout time.Duration) DNSCheckResult {
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	recordType := strings.ToUpper(cfg.RecordType)
+	if recordType == "" {
+		recordType = "A"
+	}
+
+	resolver, resolverAddr := newResolver(cfg.Resolver)
+
+	startTime := time.Now()
+	answers, err := lookupRecords(checkCtx, resolver, recordType, host)
+	responseTime := time.Since(startTime)
+
+	if err != nil {
+		return DNSCheckResult{
+			Resolver:     resolverAddr,
+			ResponseTime: responseTime,
+			Error:        fmt.Errorf("DNS lookup failed: %w", err),
+			Success:      false,
+		}
+	}
+
+	if err := compareDNSAnswers(recordType, answers, cfg.ExpectedValues, cfg.MatchMode == "exact"); err != nil {
+		return DNSCheckResult{
+			Answers:      answers,
+			Resolver:     resolverAddr,
+			ResponseTime: responseTime,
+			Error:        err,
+			Success:      false,
+		}
+	}
+
+	return DNSCheckResult{
+		Answers:      answers,
+		Resolver:     resolverAddr,
+		ResponseTime: responseTime,
+		Error:        nil,
+		Success:      true,
+	}
+}
+
+// newResolver returns a resolver that queries the given server, or the system resolver if server is empty
+func newResolver(server string) (*net.Resolver, string) {
+	if server == "" {
+		return net.DefaultResolver, "system"
+	}
+
+	// Default to port 53 if not specified
+	address := server
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, "53")
+	}
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			dialer := net.Dialer{}
+			return dialer.DialContext(ctx, network, address)
+		},
+	}, address
+}
+
+// lookupRecords resolves records of the given type and returns them in a normalized string form
+func lookupRecords(ctx context.Context, resolver *net.Resolver, recordType, host string) ([]string, error) {
+	var answers []string
+
+	switch recordType {
+	case "A", "AAAA":
+		network := "ip4"
+		if recordType == "AAAA" {
+			network = "ip6"
+		}
+		ips, err := resolver.LookupIP(ctx, network, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			answers = append(answers, ip.String())
+		}
+	case "CNAME":
+		cname, err := resolver.LookupCNAME(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, normalizeDNSName(cname))
+	case "MX":
+		records, err := resolver.LookupMX(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, mx := range records {
+			answers = append(answers, fmt.Sprintf("%d %s", mx.Pref, normalizeDNSName(mx.Host)))
+		}
+	case "TXT":
+		records, err := resolver.LookupTXT(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, records...)
+	case "NS":
+		records, err := resolver.LookupNS(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range records {
+			answers = append(answers, normalizeDNSName(ns.Host))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported record type: %s", recordType)
+	}
+
+	if len(answers) == 0 {
+		return nil, fmt.Errorf("no %s records found for %s", recordType, host)
+	}
+
+	return answers, nil
+}
+
+// compareDNSAnswers verifies that every expected value is present in the answers
+// In exact mode, answers that are not expected are also reported as drift
+func compareDNSAnswers(recordType string, answers, expected []string, exact bool) error {
+	if len(expected) == 0 {
+		return nil
+	}
+
+	var missing []string
+	for _, want := range expected {
+		found := false
+		for _, got := range answers {
+			if dnsAnswerMatches(recordType, got, want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, want)
+		}
+	}
+
+	var unexpected []string
+	if exact {
+		for _, got := range answers {
+			found := false
+			for _, want := range expected {
+				if dnsAnswerMatches(recordType, got, want) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				unexpected = append(unexpected, got)
+			}
+		}
+	}
+
+	if len(missing) == 0 && len(unexpected) == 0 {
+		return nil
+	}
+
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, fmt.Sprintf("missing [%s]", strings.Join(missing, ", ")))
+	}
+	if len(unexpected) > 0 {
+		problems = append(problems, fmt.Sprintf("unexpected [%s]", strings.Join(unexpected, ", ")))
+	}
+
+	return fmt.Errorf("DNS %s answers do not match: %s (got [%s])",
+		recordType, strings.Join(problems, ", "), strings.Join(answers, ", "))
+}
+
+// dnsAnswerMatches compares a resolved answer with an expected value for the given record type
+func dnsAnswerMatches(recordType, answer, expected string) bool {
+	expected = strings.TrimSpace(expected)
+
+	switch recordType {
+	case "A", "AAAA":
+		ip := net.ParseIP(expected)
+		return ip != nil && ip.String() == answer
+	case "TXT":
+		return answer == expected
+	case "MX":
+		// Allow the expected value to omit the preference ("mail.example.com")
+		expected = normalizeDNSName(expected)
+		if answer == expected {
+			return true
+		}
+		if idx := strings.Index(answer, " "); idx != -1 {
+			return answer[idx+1:] == expected
+		}
+		return false
+	default:
+		return answer == normalizeDNSName(expected)
+	}
+}
+
+// normalizeDNSName lowercases a domain name and strips the trailing root dot
+func normalizeDNSName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
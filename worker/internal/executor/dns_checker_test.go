@@ -0,0 +1,163 @@
+package executor
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// startTestDNSServer starts a UDP DNS server on localhost that answers A and MX queries
+func startTestDNSServer(t *testing.T) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to create test DNS server: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return // Test might have finished
+			}
+
+			var query dnsmessage.Message
+			if err := query.Unpack(buf[:n]); err != nil || len(query.Questions) == 0 {
+				continue
+			}
+			question := query.Questions[0]
+
+			resp := dnsmessage.Message{
+				Header:    dnsmessage.Header{ID: query.ID, Response: true, Authoritative: true},
+				Questions: query.Questions,
+			}
+			header := dnsmessage.ResourceHeader{Name: question.Name, Type: question.Type, Class: dnsmessage.ClassINET, TTL: 60}
+
+			if strings.HasPrefix(question.Name.String(), "example.test.") {
+				switch question.Type {
+				case dnsmessage.TypeA:
+					resp.Answers = append(resp.Answers,
+						dnsmessage.Resource{Header: header, Body: &dnsmessage.AResource{A: [4]byte{192, 0, 2, 10}}},
+						dnsmessage.Resource{Header: header, Body: &dnsmessage.AResource{A: [4]byte{192, 0, 2, 11}}},
+					)
+				case dnsmessage.TypeMX:
+					resp.Answers = append(resp.Answers,
+						dnsmessage.Resource{Header: header, Body: &dnsmessage.MXResource{Pref: 10, MX: dnsmessage.MustNewName("mail.example.test.")}},
+					)
+				}
+			} else {
+				resp.RCode = dnsmessage.RCodeNameError
+			}
+
+			packed, err := resp.Pack()
+			if err != nil {
+				continue
+			}
+			conn.WriteTo(packed, addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func TestDNSChecker_Check_Success(t *testing.T) {
+	resolver := startTestDNSServer(t)
+	checker := NewDNSChecker()
+
+	result := checker.Check(context.Background(), "example.test", entities.DNSConfig{
+		RecordType:     "A",
+		Resolver:       resolver,
+		ExpectedValues: []string{"192.0.2.10"},
+	}, 5*time.Second)
+
+	if !result.Success {
+		t.Errorf("Expected success, got failure: %v", result.Error)
+	}
+	if len(result.Answers) != 2 {
+		t.Errorf("Expected 2 answers, got %v", result.Answers)
+	}
+	if result.Resolver != resolver {
+		t.Errorf("Expected resolver %s, got %s", resolver, result.Resolver)
+	}
+	if result.ResponseTime <= 0 {
+		t.Errorf("Expected positive response time, got %v", result.ResponseTime)
+	}
+}
+
+func TestDNSChecker_Check_ExactModeDrift(t *testing.T) {
+	resolver := startTestDNSServer(t)
+	checker := NewDNSChecker()
+
+	result := checker.Check(context.Background(), "example.test", entities.DNSConfig{
+		RecordType:     "A",
+		Resolver:       resolver,
+		ExpectedValues: []string{"192.0.2.10"},
+		MatchMode:      "exact",
+	}, 5*time.Second)
+
+	if result.Success {
+		t.Errorf("Expected failure for unexpected answer in exact mode, got success")
+	}
+	if result.Error == nil || !strings.Contains(result.Error.Error(), "unexpected [192.0.2.11]") {
+		t.Errorf("Expected drift error naming the unexpected answer, got: %v", result.Error)
+	}
+}
+
+func TestDNSChecker_Check_MissingExpectedValue(t *testing.T) {
+	resolver := startTestDNSServer(t)
+	checker := NewDNSChecker()
+
+	result := checker.Check(context.Background(), "example.test", entities.DNSConfig{
+		RecordType:     "A",
+		Resolver:       resolver,
+		ExpectedValues: []string{"198.51.100.1"},
+	}, 5*time.Second)
+
+	if result.Success {
+		t.Errorf("Expected failure for missing expected answer, got success")
+	}
+	if result.Error == nil || !strings.Contains(result.Error.Error(), "missing [198.51.100.1]") {
+		t.Errorf("Expected error naming the missing answer, got: %v", result.Error)
+	}
+}
+
+func TestDNSChecker_Check_MX(t *testing.T) {
+	resolver := startTestDNSServer(t)
+	checker := NewDNSChecker()
+
+	result := checker.Check(context.Background(), "example.test", entities.DNSConfig{
+		RecordType:     "MX",
+		Resolver:       resolver,
+		ExpectedValues: []string{"mail.example.test."},
+	}, 5*time.Second)
+
+	if !result.Success {
+		t.Errorf("Expected success, got failure: %v", result.Error)
+	}
+	if len(result.Answers) != 1 || result.Answers[0] != "10 mail.example.test" {
+		t.Errorf("Expected answer '10 mail.example.test', got %v", result.Answers)
+	}
+}
+
+func TestDNSChecker_Check_NXDomain(t *testing.T) {
+	resolver := startTestDNSServer(t)
+	checker := NewDNSChecker()
+
+	result := checker.Check(context.Background(), "missing.test", entities.DNSConfig{
+		RecordType: "A",
+		Resolver:   resolver,
+	}, 5*time.Second)
+
+	if result.Success {
+		t.Errorf("Expected failure for non-existent domain, got success")
+	}
+	if result.Error == nil {
+		t.Errorf("Expected error for non-existent domain, got nil")
+	}
+}
@@ -0,0 +1,227 @@
+package executor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+)
+
+const (
+	// defaultDockerEndpoint is the socket of the local Docker daemon
+	defaultDockerEndpoint = "unix:///var/run/docker.sock"
+	// maxDockerResponseSize limits the inspect response read from the Engine API
+	maxDockerResponseSize = 4 << 20
+)
+
+// DockerContainer is the part of the Engine API container inspect response used by the check
+type DockerContainer struct {
+	ID           string `json:"Id"`
+	Name         string `json:"Name"`
+	RestartCount int    `json:"RestartCount"`
+	State        struct {
+		Status     string    `json:"Status"` // created, running, paused, restarting, removing, exited or dead
+		OOMKilled  bool      `json:"OOMKilled"`
+		ExitCode   int       `json:"ExitCode"`
+		Error      string    `json:"Error"`
+		StartedAt  time.Time `json:"StartedAt"`
+		FinishedAt time.Time `json:"FinishedAt"`
+		Health     *struct {
+			Status        string `json:"Status"` // starting, healthy or unhealthy
+			FailingStreak int    `json:"FailingStreak"`
+		} `json:"Health"` // nil when the container has no healthcheck
+	} `json:"State"`
+	Config struct {
+		Image string `json:"Image"`
+	} `json:"Config"`
+}
+
+// DockerCheckResult represents the result of a Docker container check
+type DockerCheckResult struct {
+	ResponseTime time.Duration
+	Container    *DockerContainer // nil when the container could not be inspected
+	Error        error
+	Success      bool
+}
+
+// DockerChecker checks containers through the Docker Engine API
+type DockerChecker struct {
+	sockets           map[string]bool  // unix socket paths allowed as endpoints
+	rootCAs           *x509.CertPool   // trusted roots for TLS endpoints, system roots when nil
+	clientCertificate *tls.Certificate // presented to TLS endpoints when set
+}
+
+// NewDockerChecker creates a new Docker checker
+// Monitors may only use the unix sockets listed in sockets: a socket gives access to every container
+// of its daemon, e.g. those of the platform itself when the worker host's socket is mounted
+func NewDockerChecker(sockets []string) *DockerChecker {
+	allowed := make(map[string]bool, len(sockets))
+	for _, socket := range sockets {
+		allowed[filepath.Clean(socket)] = true
+	}
+	return &DockerChecker{sockets: allowed}
+}
+
+// WithClientCertificate returns a copy of the checker that presents certificate to TLS endpoints
+func (c *DockerChecker) WithClientCertificate(certificate *tls.Certificate) *DockerChecker {
+	checker := *c
+	checker.clientCertificate = certificate
+	return &checker
+}
+
+// Check inspects the named container through the endpoint of cfg. The container is down when it
+// is not running, when its healthcheck reports it unhealthy, or when previous is the last check of
+// the same container and the restart count has increased since, e.g. a container crash-looping
+// between two checks.
+func (c *DockerChecker) Check(ctx context.Context, name string, cfg entities.DockerConfig, previous *entities.DockerCheckDetails, timeout time.Duration) DockerCheckResult {
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, baseURL, err := c.newClient(cfg)
+	if err != nil {
+		return DockerCheckResult{Error: err, Success: false}
+	}
+	defer client.CloseIdleConnections()
+
+	startTime := time.Now()
+	container, err := inspectContainer(checkCtx, client, baseURL, name)
+	result := DockerCheckResult{ResponseTime: time.Since(startTime), Container: container}
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
+	state := container.State
+	switch {
+	case state.Status != "running":
+		result.Error = fmt.Errorf("container is %s", describeContainerState(container))
+	case state.Health != nil && state.Health.Status == "unhealthy":
+		// The healthcheck output is not reported, it may print anything from inside the container
+		result.Error = fmt.Errorf("container is unhealthy after %d failed health checks", state.Health.FailingStreak)
+	case previous != nil && previous.ContainerID == container.ID && container.RestartCount > previous.RestartCount:
+		result.Error = fmt.Errorf("container restarted since the last check (restart count %d, was %d), running since %s",
+			container.RestartCount, previous.RestartCount, state.StartedAt.UTC().Format(time.RFC3339))
+	default:
+		result.Success = true
+	}
+	return result
+}
+
+// newClient returns an HTTP client connected to the Engine API endpoint and the base URL of its requests
+func (c *DockerChecker) newClient(cfg entities.DockerConfig) (*http.Client, string, error) {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = defaultDockerEndpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid Docker endpoint: %w", err)
+	}
+
+	transport := &http.Transport{DisableKeepAlives: true}
+	switch u.Scheme {
+	case "unix":
+		socket := filepath.Clean(u.Path)
+		if !c.sockets[socket] {
+			return nil, "", fmt.Errorf("Docker socket %s is not enabled on this worker (DOCKER_SOCKETS)", socket)
+		}
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socket)
+		}
+		// The host is ignored by the daemon, the request goes to the socket
+		return &http.Client{Transport: transport}, "http://docker", nil
+	case "tcp":
+		if !cfg.TLS {
+			return &http.Client{Transport: transport}, "http://" + u.Host, nil
+		}
+		tlsConfig := newClientTLSConfig(u.Hostname(), c.rootCAs, c.clientCertificate)
+		if cfg.CACertificate != "" {
+			tlsConfig.RootCAs = x509.NewCertPool()
+			if !tlsConfig.RootCAs.AppendCertsFromPEM([]byte(cfg.CACertificate)) {
+				return nil, "", errors.New("invalid Docker CA certificate")
+			}
+		}
+		tlsConfig.InsecureSkipVerify = cfg.InsecureSkipVerify
+		transport.TLSClientConfig = tlsConfig
+		return &http.Client{Transport: transport}, "https://" + u.Host, nil
+	default:
+		return nil, "", fmt.Errorf("unsupported Docker endpoint %q", endpoint)
+	}
+}
+
+// inspectContainer reads the state of the container from the Engine API
+func inspectContainer(ctx context.Context, client *http.Client, baseURL, name string) (*DockerContainer, error) {
+	name = strings.TrimPrefix(name, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/containers/"+url.PathEscape(name)+"/json", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach the Docker API: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDockerResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the Docker API response: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("container %q not found", name)
+	default:
+		// Errors are returned as {"message": "..."}
+		var apiError struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &apiError) == nil && apiError.Message != "" {
+			return nil, fmt.Errorf("Docker API responded %s: %s", resp.Status, truncateForError(apiError.Message))
+		}
+		return nil, fmt.Errorf("Docker API responded %s", resp.Status)
+	}
+
+	var container DockerContainer
+	if err := json.Unmarshal(body, &container); err != nil {
+		return nil, fmt.Errorf("invalid Docker API response: %w", err)
+	}
+	return &container, nil
+}
+
+// describeContainerState summarizes the state of a container that is not running,
+// e.g. "exited (exit code 137, OOM killed, restarted 3 times)"
+func describeContainerState(container *DockerContainer) string {
+	state := container.State
+	var notes []string
+	switch state.Status {
+	case "exited", "dead", "restarting":
+		notes = append(notes, fmt.Sprintf("exit code %d", state.ExitCode))
+	}
+	if state.OOMKilled {
+		notes = append(notes, "OOM killed")
+	}
+	if container.RestartCount > 0 {
+		notes = append(notes, fmt.Sprintf("restarted %d times", container.RestartCount))
+	}
+	if state.Error != "" {
+		notes = append(notes, truncateForError(state.Error))
+	}
+
+	if len(notes) == 0 {
+		return state.Status
+	}
+	return fmt.Sprintf("%s (%s)", state.Status, strings.Join(notes, ", "))
+}
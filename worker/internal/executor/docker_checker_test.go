@@ -0,0 +1,173 @@
+package executor
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+)
+
+// testContainers are served by the fake Engine API, keyed by container name
+var testContainers = map[string]string{
+	"web":        `{"Id":"aaa111","Name":"/web","RestartCount":0,"State":{"Status":"running","StartedAt":"2026-10-16T10:00:00Z"},"Config":{"Image":"nginx:1.27"}}`,
+	"api":        `{"Id":"bbb222","Name":"/api","RestartCount":5,"State":{"Status":"running","StartedAt":"2026-10-16T10:05:00Z","Health":{"Status":"healthy","FailingStreak":0}},"Config":{"Image":"api:2.1"}}`,
+	"warming":    `{"Id":"ccc333","Name":"/warming","RestartCount":0,"State":{"Status":"running","Health":{"Status":"starting"}},"Config":{"Image":"api:2.1"}}`,
+	"sick":       `{"Id":"ddd444","Name":"/sick","RestartCount":0,"State":{"Status":"running","Health":{"Status":"unhealthy","FailingStreak":3,"Log":[{"ExitCode":1,"Output":"ok"},{"ExitCode":7,"Output":"curl: (7) Failed to connect to localhost port 8080\n"}]}},"Config":{"Image":"api:2.1"}}`,
+	"oom":        `{"Id":"eee555","Name":"/oom","RestartCount":0,"State":{"Status":"exited","OOMKilled":true,"ExitCode":137,"FinishedAt":"2026-10-16T10:10:00Z"},"Config":{"Image":"worker:1.0"}}`,
+	"crashing":   `{"Id":"fff666","Name":"/crashing","RestartCount":12,"State":{"Status":"restarting","ExitCode":1},"Config":{"Image":"worker:1.0"}}`,
+	"paused":     `{"Id":"ggg777","Name":"/paused","RestartCount":0,"State":{"Status":"paused"},"Config":{"Image":"worker:1.0"}}`,
+	"never-seen": `{"Id":"hhh888","Name":"/never-seen","RestartCount":0,"State":{"Status":"created"},"Config":{"Image":"worker:1.0"}}`,
+}
+
+// dockerAPIHandler serves container inspect requests for testContainers
+func dockerAPIHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/containers/"), "/json")
+		if r.Method != http.MethodGet || !ok {
+			http.NotFound(w, r)
+			return
+		}
+		container, ok := testContainers[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"message":"No such container: %s"}`, name)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(container))
+	})
+}
+
+// startDockerSocket serves the fake Engine API on a unix socket and returns its path
+func startDockerSocket(t *testing.T) string {
+	t.Helper()
+
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("Failed to create socket: %v", err)
+	}
+	server := httptest.NewUnstartedServer(dockerAPIHandler())
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+
+	return socket
+}
+
+func TestDockerChecker_Check(t *testing.T) {
+	socket := startDockerSocket(t)
+	cfg := entities.DockerConfig{Endpoint: "unix://" + socket}
+
+	tests := []struct {
+		name      string
+		container string
+		previous  *entities.DockerCheckDetails
+		success   bool
+		errText   string
+	}{
+		{"running", "web", nil, true, ""},
+		{"leading slash", "/web", nil, true, ""},
+		{"healthy", "api", nil, true, ""},
+		{"health starting", "warming", nil, true, ""},
+		{"unhealthy", "sick", nil, false, "container is unhealthy after 3 failed health checks"},
+		{"exited", "oom", nil, false, "container is exited (exit code 137, OOM killed)"},
+		{"restarting", "crashing", nil, false, "container is restarting (exit code 1, restarted 12 times)"},
+		{"paused", "paused", nil, false, "container is paused"},
+		{"created", "never-seen", nil, false, "container is created"},
+		{"not found", "db", nil, false, `container "db" not found`},
+		{"same restart count", "api", &entities.DockerCheckDetails{ContainerID: "bbb222", RestartCount: 5}, true, ""},
+		{"restarted since last check", "api", &entities.DockerCheckDetails{ContainerID: "bbb222", RestartCount: 3}, false, "container restarted since the last check (restart count 5, was 3), running since 2026-10-16T10:05:00Z"},
+		{"recreated container", "api", &entities.DockerCheckDetails{ContainerID: "old999", RestartCount: 0}, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewDockerChecker([]string{socket}).Check(context.Background(), tt.container, cfg, tt.previous, time.Second)
+			if result.Success != tt.success {
+				t.Fatalf("Expected success=%v, got %v (error: %v)", tt.success, result.Success, result.Error)
+			}
+			if tt.errText != "" && (result.Error == nil || result.Error.Error() != tt.errText) {
+				t.Errorf("Expected error %q, got %v", tt.errText, result.Error)
+			}
+			if tt.name != "not found" && result.Container == nil {
+				t.Errorf("Expected the container state to be returned")
+			}
+		})
+	}
+}
+
+func TestDockerChecker_Check_Unreachable(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "missing.sock")
+	cfg := entities.DockerConfig{Endpoint: "unix://" + socket}
+
+	result := NewDockerChecker([]string{socket}).Check(context.Background(), "web", cfg, nil, time.Second)
+	if result.Success || result.Error == nil || !strings.Contains(result.Error.Error(), "failed to reach the Docker API") {
+		t.Errorf("Expected a connection error, got %+v", result)
+	}
+}
+
+func TestDockerChecker_Check_SocketNotAllowed(t *testing.T) {
+	socket := startDockerSocket(t)
+
+	tests := []struct {
+		name     string
+		endpoint string
+		sockets  []string
+	}{
+		{"no sockets allowed", "unix://" + socket, nil},
+		{"other socket allowed", "unix://" + socket, []string{"/var/run/docker.sock"}},
+		{"path outside the allowed socket", "unix://" + socket + "/../other.sock", []string{socket}},
+		{"default socket not allowed", "", []string{socket}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewDockerChecker(tt.sockets).Check(context.Background(), "web", entities.DockerConfig{Endpoint: tt.endpoint}, nil, time.Second)
+			if result.Success || result.Error == nil || !strings.Contains(result.Error.Error(), "is not enabled on this worker") {
+				t.Errorf("Expected the socket to be rejected, got %+v", result)
+			}
+		})
+	}
+}
+
+func TestDockerChecker_Check_TLS(t *testing.T) {
+	clientCertificate, clientCAs := newTestClientCertificate(t)
+	server := httptest.NewUnstartedServer(dockerAPIHandler())
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	endpoint := "tcp://" + server.Listener.Addr().String()
+	caCertificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	tests := []struct {
+		name        string
+		cfg         entities.DockerConfig
+		certificate *tls.Certificate
+		success     bool
+	}{
+		{"trusted daemon", entities.DockerConfig{Endpoint: endpoint, TLS: true, CACertificate: caCertificate}, clientCertificate, true},
+		{"untrusted daemon", entities.DockerConfig{Endpoint: endpoint, TLS: true}, clientCertificate, false},
+		{"skip verification", entities.DockerConfig{Endpoint: endpoint, TLS: true, InsecureSkipVerify: true}, clientCertificate, true},
+		{"no client certificate", entities.DockerConfig{Endpoint: endpoint, TLS: true, CACertificate: caCertificate}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewDockerChecker(nil).WithClientCertificate(tt.certificate).Check(context.Background(), "web", tt.cfg, nil, time.Second)
+			if result.Success != tt.success {
+				t.Errorf("Expected success=%v, got %v (error: %v)", tt.success, result.Success, result.Error)
+			}
+		})
+	}
+}
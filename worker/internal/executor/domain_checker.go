@@ -0,0 +1,513 @@
+package executor
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+const (
+	// ianaRDAPBootstrapURL lists the RDAP servers of every TLD (RFC 9224)
+	ianaRDAPBootstrapURL = "https://data.iana.org/rdap/dns.json"
+	// ianaWHOISServer refers WHOIS queries to the server of each TLD
+	ianaWHOISServer = "whois.iana.org:43"
+	// rdapBootstrapTTL is how long the RDAP bootstrap file is cached
+	rdapBootstrapTTL = 24 * time.Hour
+	// rdapBootstrapTimeout limits the download of the RDAP bootstrap file, shared by every waiting check
+	rdapBootstrapTimeout = 30 * time.Second
+	// domainLookupTTL is how long the expiry and registrar of a domain are cached, registrations
+	// change rarely and registries rate-limit RDAP and WHOIS queries
+	domainLookupTTL = 6 * time.Hour
+	// maxDomainResponseSize limits RDAP and WHOIS responses
+	maxDomainResponseSize = 1 << 20
+)
+
+// whoisExpiryFields are the WHOIS keys holding the expiry date, most specific first
+var whoisExpiryFields = []string{
+	"registry expiry date",
+	"registrar registration expiration date",
+	"expiration date",
+	"expiry date",
+	"expire date",
+	"expires on",
+	"expires",
+	"paid-till",
+	"renewal date",
+}
+
+// whoisDateLayouts are the date formats used by WHOIS servers
+var whoisDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02",
+	"2006.01.02",
+	"2006/01/02",
+	"02-Jan-2006",
+	"02.01.2006",
+	"January 02 2006",
+}
+
+// DomainCheckResult represents the result of a domain registration lookup
+type DomainCheckResult struct {
+	Domain       string // registrable domain that was looked up
+	Registrar    string
+	ExpiresAt    time.Time
+	DaysUntil    int
+	Source       string // rdap or whois
+	ResponseTime time.Duration
+	Error        error
+	Success      bool
+}
+
+// DomainChecker looks up the registration expiry of domains through RDAP, falling back to WHOIS
+type DomainChecker struct {
+	rdapURL      string // RDAP base URL used for every domain, IANA bootstrap when empty
+	whoisServer  string // WHOIS host[:port] used for every domain, IANA referral when empty
+	bootstrapURL string // RDAP bootstrap file listing the server of each TLD
+	client       *http.Client
+
+	mu                sync.Mutex
+	bootstrap         map[string]string // TLD to RDAP base URL
+	bootstrapLoadedAt time.Time
+	bootstrapLoad     *rdapBootstrapLoad      // download in progress, nil when none runs
+	lookups           map[string]domainLookup // registrable domain to its last successful lookup
+}
+
+// rdapBootstrapLoad is a download of the RDAP bootstrap file that concurrent checks wait for
+type rdapBootstrapLoad struct {
+	done      chan struct{} // closed when the download finished
+	bootstrap map[string]string
+	err       error
+}
+
+// domainLookup is a cached registration lookup
+type domainLookup struct {
+	result   DomainCheckResult // Registrar, ExpiresAt and Source
+	cachedAt time.Time
+}
+
+// NewDomainChecker creates a new domain checker
+// rdapURL and whoisServer override the servers found through IANA, e.g. to use a local stand-in
+func NewDomainChecker(rdapURL, whoisServer string) *DomainChecker {
+	return &DomainChecker{
+		rdapURL:      strings.TrimSuffix(rdapURL, "/"),
+		whoisServer:  whoisServer,
+		bootstrapURL: ianaRDAPBootstrapURL,
+		client:       &http.Client{},
+		lookups:      make(map[string]domainLookup),
+	}
+}
+
+// Check looks up the registrable domain of name and reports its expiry
+// The check fails when the domain cannot be looked up or has already expired
+// Lookups that found an expiry date are reused for domainLookupTTL.
+func (c *DomainChecker) Check(ctx context.Context, name string, timeout time.Duration) DomainCheckResult {
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	domain, err := registrableDomain(name)
+	if err != nil {
+		return DomainCheckResult{Error: err}
+	}
+
+	startTime := time.Now()
+	result, ok := c.cachedLookup(domain)
+	if !ok {
+		var rdapErr error
+		result, rdapErr = c.lookupRDAP(checkCtx, domain)
+		if rdapErr != nil {
+			var whoisErr error
+			result, whoisErr = c.lookupWHOIS(checkCtx, domain)
+			if whoisErr != nil {
+				return DomainCheckResult{
+					Domain:       domain,
+					ResponseTime: time.Since(startTime),
+					Error:        fmt.Errorf("domain lookup failed: RDAP: %v; WHOIS: %v", rdapErr, whoisErr),
+				}
+			}
+		}
+		if !result.ExpiresAt.IsZero() {
+			c.cacheLookup(domain, result)
+		}
+	}
+	result.Domain = domain
+	result.ResponseTime = time.Since(startTime)
+
+	if result.ExpiresAt.IsZero() {
+		result.Error = fmt.Errorf("no expiry date found in %s response", strings.ToUpper(result.Source))
+		return result
+	}
+
+	result.DaysUntil = int(time.Until(result.ExpiresAt).Hours() / 24)
+	if time.Now().After(result.ExpiresAt) {
+		result.Error = fmt.Errorf("domain expired on %s", result.ExpiresAt.Format("2006-01-02"))
+		return result
+	}
+
+	result.Success = true
+	return result
+}
+
+// cachedLookup returns the lookup of domain cached within domainLookupTTL
+func (c *DomainChecker) cachedLookup(domain string) (DomainCheckResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	lookup, ok := c.lookups[domain]
+	if !ok || time.Since(lookup.cachedAt) > domainLookupTTL {
+		return DomainCheckResult{}, false
+	}
+	return lookup.result, true
+}
+
+// cacheLookup stores the lookup of domain and drops the expired ones
+func (c *DomainChecker) cacheLookup(domain string, result DomainCheckResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name, lookup := range c.lookups {
+		if time.Since(lookup.cachedAt) > domainLookupTTL {
+			delete(c.lookups, name)
+		}
+	}
+	c.lookups[domain] = domainLookup{
+		result:   DomainCheckResult{Registrar: result.Registrar, ExpiresAt: result.ExpiresAt, Source: result.Source},
+		cachedAt: time.Now(),
+	}
+}
+
+// registrableDomain reduces a host name or URL to the domain registered under its public suffix
+func registrableDomain(name string) (string, error) {
+	host := strings.TrimSpace(name)
+	if strings.Contains(host, "://") {
+		parsed, err := url.Parse(host)
+		if err != nil {
+			return "", fmt.Errorf("invalid domain: %w", err)
+		}
+		host = parsed.Hostname()
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+
+	if host == "" || net.ParseIP(host) != nil {
+		return "", fmt.Errorf("invalid domain %q", name)
+	}
+
+	domain, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		return "", fmt.Errorf("invalid domain %q: %w", name, err)
+	}
+	return domain, nil
+}
+
+// rdapResponse holds the parts of an RDAP domain response that are used (RFC 9083)
+type rdapResponse struct {
+	Events []struct {
+		Action string `json:"eventAction"`
+		Date   string `json:"eventDate"`
+	} `json:"events"`
+	Entities []struct {
+		Roles      []string          `json:"roles"`
+		VCardArray []json.RawMessage `json:"vcardArray"`
+	} `json:"entities"`
+}
+
+// lookupRDAP queries the RDAP server of the domain's TLD
+func (c *DomainChecker) lookupRDAP(ctx context.Context, domain string) (DomainCheckResult, error) {
+	result := DomainCheckResult{Source: "rdap"}
+
+	baseURL, err := c.rdapBaseURL(ctx, domain)
+	if err != nil {
+		return result, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/domain/"+domain, nil)
+	if err != nil {
+		return result, err
+	}
+	req.Header.Set("Accept", "application/rdap+json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return result, errors.New("domain not found")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return result, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	var body rdapResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxDomainResponseSize)).Decode(&body); err != nil {
+		return result, fmt.Errorf("invalid RDAP response: %w", err)
+	}
+
+	for _, event := range body.Events {
+		if event.Action != "expiration" {
+			continue
+		}
+		if expiresAt, err := time.Parse(time.RFC3339, event.Date); err == nil {
+			result.ExpiresAt = expiresAt
+		}
+	}
+
+	for _, entity := range body.Entities {
+		for _, role := range entity.Roles {
+			if role == "registrar" {
+				result.Registrar = vcardFullName(entity.VCardArray)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// rdapBaseURL returns the configured RDAP server, or the one IANA lists for the domain's TLD
+func (c *DomainChecker) rdapBaseURL(ctx context.Context, domain string) (string, error) {
+	if c.rdapURL != "" {
+		return c.rdapURL, nil
+	}
+
+	bootstrap, err := c.rdapBootstrap(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to load RDAP bootstrap: %w", err)
+	}
+
+	tld := domain[strings.LastIndex(domain, ".")+1:]
+	baseURL, ok := bootstrap[tld]
+	if !ok {
+		return "", fmt.Errorf("no RDAP server for .%s", tld)
+	}
+	return baseURL, nil
+}
+
+// rdapBootstrap returns the cached RDAP bootstrap, downloading it when missing or older than
+// rdapBootstrapTTL. Concurrent checks share one download, which runs without holding the lock
+// and is not cancelled when a waiting check gives up.
+func (c *DomainChecker) rdapBootstrap(ctx context.Context) (map[string]string, error) {
+	c.mu.Lock()
+	if c.bootstrap != nil && time.Since(c.bootstrapLoadedAt) <= rdapBootstrapTTL {
+		bootstrap := c.bootstrap
+		c.mu.Unlock()
+		return bootstrap, nil
+	}
+	load := c.bootstrapLoad
+	if load == nil {
+		load = &rdapBootstrapLoad{done: make(chan struct{})}
+		c.bootstrapLoad = load
+		go c.runRDAPBootstrapLoad(load)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-load.done:
+		return load.bootstrap, load.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// runRDAPBootstrapLoad downloads the RDAP bootstrap and swaps it in, the previous bootstrap is
+// kept when the download fails
+func (c *DomainChecker) runRDAPBootstrapLoad(load *rdapBootstrapLoad) {
+	ctx, cancel := context.WithTimeout(context.Background(), rdapBootstrapTimeout)
+	defer cancel()
+
+	bootstrap, err := c.loadRDAPBootstrap(ctx)
+
+	c.mu.Lock()
+	if err == nil {
+		c.bootstrap = bootstrap
+		c.bootstrapLoadedAt = time.Now()
+	} else if c.bootstrap != nil {
+		bootstrap, err = c.bootstrap, nil
+	}
+	c.bootstrapLoad = nil
+	c.mu.Unlock()
+
+	load.bootstrap, load.err = bootstrap, err
+	close(load.done)
+}
+
+// loadRDAPBootstrap downloads the list of RDAP servers per TLD
+func (c *DomainChecker) loadRDAPBootstrap(ctx context.Context) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.bootstrapURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	// Each service is a pair of [TLDs, base URLs]
+	var body struct {
+		Services [][][]string `json:"services"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxDomainResponseSize)).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	bootstrap := make(map[string]string)
+	for _, service := range body.Services {
+		if len(service) != 2 || len(service[1]) == 0 {
+			continue
+		}
+		for _, tld := range service[0] {
+			bootstrap[strings.ToLower(tld)] = strings.TrimSuffix(service[1][0], "/")
+		}
+	}
+	return bootstrap, nil
+}
+
+// vcardFullName returns the "fn" property of a jCard (RFC 7095)
+func vcardFullName(vcard []json.RawMessage) string {
+	if len(vcard) < 2 {
+		return ""
+	}
+
+	var properties [][]interface{}
+	if err := json.Unmarshal(vcard[1], &properties); err != nil {
+		return ""
+	}
+	for _, property := range properties {
+		if len(property) < 4 {
+			continue
+		}
+		if name, _ := property[0].(string); name == "fn" {
+			value, _ := property[3].(string)
+			return value
+		}
+	}
+	return ""
+}
+
+// lookupWHOIS queries the WHOIS server of the domain's TLD on port 43
+func (c *DomainChecker) lookupWHOIS(ctx context.Context, domain string) (DomainCheckResult, error) {
+	result := DomainCheckResult{Source: "whois"}
+
+	server := c.whoisServer
+	if server == "" {
+		tld := domain[strings.LastIndex(domain, ".")+1:]
+		referral, err := queryWHOIS(ctx, ianaWHOISServer, tld)
+		if err != nil {
+			return result, err
+		}
+		fields := parseWHOIS(referral)
+		server = fields["refer"]
+		if server == "" {
+			server = fields["whois"]
+		}
+		if server == "" {
+			return result, fmt.Errorf("no WHOIS server for .%s", tld)
+		}
+	}
+
+	response, err := queryWHOIS(ctx, server, domain)
+	if err != nil {
+		return result, err
+	}
+
+	fields := parseWHOIS(response)
+	for _, key := range whoisExpiryFields {
+		if value, ok := fields[key]; ok {
+			if expiresAt, ok := parseWHOISDate(value); ok {
+				result.ExpiresAt = expiresAt
+				break
+			}
+		}
+	}
+	if result.ExpiresAt.IsZero() && strings.Contains(strings.ToLower(response), "no match") {
+		return result, errors.New("domain not found")
+	}
+	result.Registrar = fields["registrar"]
+
+	return result, nil
+}
+
+// queryWHOIS sends a query to a WHOIS server (RFC 3912) and returns the whole response
+func queryWHOIS(ctx context.Context, server, query string) (string, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "43")
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", server)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if _, err := io.WriteString(conn, query+"\r\n"); err != nil {
+		return "", err
+	}
+
+	response, err := io.ReadAll(io.LimitReader(conn, maxDomainResponseSize))
+	if err != nil {
+		return "", err
+	}
+	return string(response), nil
+}
+
+// parseWHOIS collects the "key: value" lines of a WHOIS response, keys lowercased, first value kept
+func parseWHOIS(response string) map[string]string {
+	fields := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(response))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "%") || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ">>>") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if _, exists := fields[key]; !exists && value != "" {
+			fields[key] = value
+		}
+	}
+	return fields
+}
+
+// parseWHOISDate parses the date formats used by common WHOIS servers
+func parseWHOISDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range whoisDateLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, true
+		}
+	}
+	// Some servers append a zone name or comment after the date, e.g. "2025-08-13 (YYYY-MM-DD)"
+	if fields := strings.Fields(value); len(fields) > 1 {
+		return parseWHOISDate(fields[0])
+	}
+	return time.Time{}, false
+}
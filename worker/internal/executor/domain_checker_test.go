@@ -0,0 +1,261 @@
+package executor
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// startWHOISServer answers every WHOIS query with response, and sends the queries received on the returned channel
+func startWHOISServer(t *testing.T, response string) (string, <-chan string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to create WHOIS server: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	queries := make(chan string, 10)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			query, _ := bufio.NewReader(conn).ReadString('\n')
+			queries <- strings.TrimSpace(query)
+			fmt.Fprint(conn, response)
+			conn.Close()
+		}
+	}()
+
+	return listener.Addr().String(), queries
+}
+
+func TestDomainChecker_RDAP(t *testing.T) {
+	expiresAt := time.Now().Add(200 * 24 * time.Hour).UTC().Truncate(time.Second)
+	var requestedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/rdap+json")
+		fmt.Fprintf(w, `{
+			"objectClassName": "domain",
+			"ldhName": "EXAMPLE.TEST",
+			"events": [
+				{"eventAction": "registration", "eventDate": "2001-01-01T00:00:00Z"},
+				{"eventAction": "expiration", "eventDate": %q}
+			],
+			"entities": [
+				{"roles": ["registrar"], "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Example Registrar, Inc."]]]}
+			]
+		}`, expiresAt.Format(time.RFC3339))
+	}))
+	defer server.Close()
+
+	checker := NewDomainChecker(server.URL, "127.0.0.1:1")
+	result := checker.Check(context.Background(), "https://www.example.test/login", 5*time.Second)
+
+	if !result.Success || result.Error != nil {
+		t.Fatalf("Expected success, got error: %v", result.Error)
+	}
+	if requestedPath != "/domain/example.test" {
+		t.Errorf("Expected lookup of the registrable domain, got %s", requestedPath)
+	}
+	if result.Domain != "example.test" || result.Source != "rdap" {
+		t.Errorf("Expected example.test from rdap, got %s from %s", result.Domain, result.Source)
+	}
+	if !result.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("Expected expiry %v, got %v", expiresAt, result.ExpiresAt)
+	}
+	if result.Registrar != "Example Registrar, Inc." {
+		t.Errorf("Expected registrar from vCard, got %q", result.Registrar)
+	}
+	if result.DaysUntil < 198 || result.DaysUntil > 200 {
+		t.Errorf("Expected about 199 days until expiry, got %d", result.DaysUntil)
+	}
+}
+
+func TestDomainChecker_WHOISFallback(t *testing.T) {
+	rdap := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer rdap.Close()
+
+	expiresAt := time.Now().AddDate(1, 0, 0).UTC().Truncate(time.Second)
+	whoisAddr, queries := startWHOISServer(t, fmt.Sprintf(
+		"   Domain Name: EXAMPLE.TEST\r\n   Registrar: Example Registrar, Inc.\r\n   Registry Expiry Date: %s\r\n>>> Last update of whois database <<<\r\n",
+		expiresAt.Format("2006-01-02T15:04:05Z")))
+
+	checker := NewDomainChecker(rdap.URL, whoisAddr)
+	result := checker.Check(context.Background(), "mail.example.test", 5*time.Second)
+
+	if !result.Success || result.Error != nil {
+		t.Fatalf("Expected success, got error: %v", result.Error)
+	}
+	if result.Source != "whois" || result.Registrar != "Example Registrar, Inc." {
+		t.Errorf("Expected registrar from whois, got %q from %s", result.Registrar, result.Source)
+	}
+	if !result.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("Expected expiry %v, got %v", expiresAt, result.ExpiresAt)
+	}
+	if query := <-queries; query != "example.test" {
+		t.Errorf("Expected a query for example.test, got %q", query)
+	}
+}
+
+func TestDomainChecker_CachesLookups(t *testing.T) {
+	expiresAt := time.Now().AddDate(1, 0, 0).UTC().Truncate(time.Second)
+	var requests atomic.Int32
+	rdap := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		fmt.Fprintf(w, `{"events": [{"eventAction": "expiration", "eventDate": %q}]}`, expiresAt.Format(time.RFC3339))
+	}))
+	defer rdap.Close()
+
+	checker := NewDomainChecker(rdap.URL, "127.0.0.1:1")
+	for _, name := range []string{"example.test", "www.example.test"} {
+		if result := checker.Check(context.Background(), name, 5*time.Second); !result.Success || !result.ExpiresAt.Equal(expiresAt) {
+			t.Fatalf("Expected success with the expiry, got %+v", result)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("Expected the second check to reuse the lookup, got %d RDAP requests", got)
+	}
+
+	// An expired cache entry is looked up again
+	checker.lookups["example.test"] = domainLookup{result: checker.lookups["example.test"].result, cachedAt: time.Now().Add(-domainLookupTTL - time.Minute)}
+	checker.Check(context.Background(), "example.test", 5*time.Second)
+	if got := requests.Load(); got != 2 {
+		t.Errorf("Expected a new lookup after the cache expired, got %d RDAP requests", got)
+	}
+}
+
+func TestDomainChecker_RDAPBootstrapSharedDownload(t *testing.T) {
+	expiresAt := time.Now().AddDate(1, 0, 0).UTC().Truncate(time.Second)
+	rdap := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"events": [{"eventAction": "expiration", "eventDate": %q}]}`, expiresAt.Format(time.RFC3339))
+	}))
+	defer rdap.Close()
+
+	var downloads atomic.Int32
+	release := make(chan struct{})
+	bootstrap := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads.Add(1)
+		<-release
+		fmt.Fprintf(w, `{"services": [[["test"], [%q]]]}`, rdap.URL+"/")
+	}))
+	defer bootstrap.Close()
+
+	checker := NewDomainChecker("", "127.0.0.1:1")
+	checker.bootstrapURL = bootstrap.URL
+
+	// A check giving up while the download runs does not cancel it for the others
+	if result := checker.Check(context.Background(), "first.test", 50*time.Millisecond); result.Success {
+		t.Fatalf("Expected the check to time out while the bootstrap downloads")
+	}
+
+	var wg sync.WaitGroup
+	results := make([]DomainCheckResult, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = checker.Check(context.Background(), fmt.Sprintf("domain%d.test", i), 5*time.Second)
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i, result := range results {
+		if !result.Success {
+			t.Errorf("Check %d failed: %v", i, result.Error)
+		}
+	}
+	if got := downloads.Load(); got != 1 {
+		t.Errorf("Expected one bootstrap download, got %d", got)
+	}
+}
+
+func TestDomainChecker_Failures(t *testing.T) {
+	rdap := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer rdap.Close()
+
+	t.Run("expired", func(t *testing.T) {
+		whoisAddr, _ := startWHOISServer(t, "Registrar: Example Registrar\nExpiration Date: 02-Jan-2020\n")
+		result := NewDomainChecker(rdap.URL, whoisAddr).Check(context.Background(), "example.test", 5*time.Second)
+
+		if result.Success || result.Error == nil || !strings.Contains(result.Error.Error(), "expired") {
+			t.Errorf("Expected expired domain to fail, got success=%v error=%v", result.Success, result.Error)
+		}
+		if result.DaysUntil >= 0 {
+			t.Errorf("Expected negative days until expiry, got %d", result.DaysUntil)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		whoisAddr, _ := startWHOISServer(t, "No match for \"EXAMPLE.TEST\".\n")
+		result := NewDomainChecker(rdap.URL, whoisAddr).Check(context.Background(), "example.test", 5*time.Second)
+
+		if result.Success || result.Error == nil {
+			t.Errorf("Expected unknown domain to fail")
+		}
+	})
+
+	t.Run("IP address", func(t *testing.T) {
+		result := NewDomainChecker(rdap.URL, "").Check(context.Background(), "192.0.2.1", 5*time.Second)
+		if result.Success || result.Error == nil {
+			t.Errorf("Expected IP address to be rejected")
+		}
+	})
+}
+
+func TestRegistrableDomain(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"example.com", "example.com"},
+		{"www.Example.COM.", "example.com"},
+		{"https://shop.example.co.uk/cart", "example.co.uk"},
+		{"a.b.example.test", "example.test"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := registrableDomain(tt.name)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseWHOISDate(t *testing.T) {
+	want := time.Date(2026, 8, 13, 0, 0, 0, 0, time.UTC)
+	for _, value := range []string{"2026-08-13", "2026-08-13T00:00:00Z", "13-Aug-2026", "2026.08.13", "2026-08-13 (YYYY-MM-DD)"} {
+		got, ok := parseWHOISDate(value)
+		if !ok || !got.Equal(want) {
+			t.Errorf("parseWHOISDate(%q) = %v, %v; want %v", value, got, ok, want)
+		}
+	}
+
+	if _, ok := parseWHOISDate("not a date"); ok {
+		t.Errorf("Expected invalid date to be rejected")
+	}
+}
@@ -0,0 +1,103 @@
+package executor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCCheckResult represents the result of a gRPC health check
+type GRPCCheckResult struct {
+	ResponseTime time.Duration
+	Status       string // serving status reported by the server, empty when the RPC failed
+	Code         string // gRPC status code of the RPC
+	Error        error
+	Success      bool
+}
+
+// GRPCChecker performs checks using the standard grpc.health.v1 health checking protocol
+type GRPCChecker struct {
+	rootCAs           *x509.CertPool   // trusted roots for TLS connections, system roots when nil
+	clientCertificate *tls.Certificate // presented for mutual TLS when set
+}
+
+// NewGRPCChecker creates a new gRPC checker
+func NewGRPCChecker() *GRPCChecker {
+	return &GRPCChecker{}
+}
+
+// WithClientCertificate returns a copy of the checker that presents certificate on TLS connections
+func (c *GRPCChecker) WithClientCertificate(certificate *tls.Certificate) *GRPCChecker {
+	checker := *c
+	checker.clientCertificate = certificate
+	return &checker
+}
+
+// Check calls grpc.health.v1.Health/Check on the given host:port
+// Only SERVING counts as up; any other status or an RPC error fails the check
+func (c *GRPCChecker) Check(ctx context.Context, address string, cfg entities.GRPCConfig, timeout time.Duration) GRPCCheckResult {
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	transportCredentials := insecure.NewCredentials()
+	if cfg.TLS {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return GRPCCheckResult{Error: fmt.Errorf("invalid address: %w", err), Success: false}
+		}
+		transportCredentials = credentials.NewTLS(newClientTLSConfig(host, c.rootCAs, c.clientCertificate))
+	}
+
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(transportCredentials))
+	if err != nil {
+		return GRPCCheckResult{Error: fmt.Errorf("failed to create gRPC client: %w", err), Success: false}
+	}
+	defer conn.Close()
+
+	if len(cfg.Metadata) > 0 {
+		pairs := make([]string, 0, len(cfg.Metadata)*2)
+		for key, value := range cfg.Metadata {
+			pairs = append(pairs, strings.ToLower(key), value)
+		}
+		checkCtx = metadata.NewOutgoingContext(checkCtx, metadata.Pairs(pairs...))
+	}
+
+	startTime := time.Now()
+	resp, err := healthpb.NewHealthClient(conn).Check(checkCtx, &healthpb.HealthCheckRequest{Service: cfg.Service})
+	responseTime := time.Since(startTime)
+
+	if err != nil {
+		st := status.Convert(err)
+		return GRPCCheckResult{
+			ResponseTime: responseTime,
+			Code:         st.Code().String(),
+			Error:        fmt.Errorf("health check RPC failed: %s: %s", st.Code(), st.Message()),
+			Success:      false,
+		}
+	}
+
+	result := GRPCCheckResult{
+		ResponseTime: responseTime,
+		Status:       resp.GetStatus().String(),
+		Code:         "OK",
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		result.Error = fmt.Errorf("service status %s", result.Status)
+		return result
+	}
+
+	result.Success = true
+	return result
+}
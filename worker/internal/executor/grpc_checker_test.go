@@ -0,0 +1,157 @@
+package executor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// startHealthServer starts a gRPC server exposing the standard health service
+func startHealthServer(t *testing.T, opts ...grpc.ServerOption) (string, *health.Server) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+
+	server := grpc.NewServer(opts...)
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String(), healthServer
+}
+
+func TestGRPCChecker_Check_Status(t *testing.T) {
+	address, healthServer := startHealthServer(t)
+	healthServer.SetServingStatus("orders.v1.Orders", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("billing.v1.Billing", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	checker := NewGRPCChecker()
+
+	tests := []struct {
+		name        string
+		service     string
+		wantSuccess bool
+		wantError   string
+	}{
+		{"whole server", "", true, ""},
+		{"serving service", "orders.v1.Orders", true, ""},
+		{"not serving service", "billing.v1.Billing", false, "service status NOT_SERVING"},
+		{"unknown service", "unknown.v1.Unknown", false, "NotFound"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := checker.Check(context.Background(), address, entities.GRPCConfig{Service: tt.service}, 2*time.Second)
+			if result.Success != tt.wantSuccess {
+				t.Errorf("Expected success=%v, got %v (error: %v)", tt.wantSuccess, result.Success, result.Error)
+			}
+			if tt.wantError != "" && (result.Error == nil || !strings.Contains(result.Error.Error(), tt.wantError)) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantError, result.Error)
+			}
+		})
+	}
+}
+
+func TestGRPCChecker_Check_Metadata(t *testing.T) {
+	// Reject calls without the expected API key
+	interceptor := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get("x-api-key"); len(values) != 1 || values[0] != "secret" {
+			return nil, status.Error(codes.Unauthenticated, "missing api key")
+		}
+		return handler(ctx, req)
+	}
+	address, _ := startHealthServer(t, grpc.UnaryInterceptor(interceptor))
+
+	checker := NewGRPCChecker()
+
+	result := checker.Check(context.Background(), address, entities.GRPCConfig{Metadata: map[string]string{"X-Api-Key": "secret"}}, 2*time.Second)
+	if !result.Success {
+		t.Errorf("Expected success with metadata, got: %v", result.Error)
+	}
+
+	result = checker.Check(context.Background(), address, entities.GRPCConfig{}, 2*time.Second)
+	if result.Success {
+		t.Errorf("Expected failure without metadata, got success")
+	}
+	if result.Code != codes.Unauthenticated.String() {
+		t.Errorf("Expected code Unauthenticated, got %q", result.Code)
+	}
+}
+
+func TestGRPCChecker_Check_TLS(t *testing.T) {
+	// Borrow the httptest certificate, which is valid for 127.0.0.1
+	httpServer := httptest.NewTLSServer(nil)
+	certificates := httpServer.TLS.Certificates
+	roots := x509.NewCertPool()
+	roots.AddCert(httpServer.Certificate())
+	httpServer.Close()
+
+	address, _ := startHealthServer(t, grpc.Creds(credentials.NewTLS(&tls.Config{Certificates: certificates})))
+
+	checker := &GRPCChecker{rootCAs: roots}
+	result := checker.Check(context.Background(), address, entities.GRPCConfig{TLS: true}, 2*time.Second)
+	if !result.Success {
+		t.Errorf("Expected TLS check to succeed, got: %v", result.Error)
+	}
+
+	// Plaintext against a TLS server must fail
+	result = checker.Check(context.Background(), address, entities.GRPCConfig{}, 2*time.Second)
+	if result.Success {
+		t.Errorf("Expected plaintext check against TLS server to fail")
+	}
+}
+
+func TestGRPCChecker_Check_ConnectionRefused(t *testing.T) {
+	checker := NewGRPCChecker()
+	result := checker.Check(context.Background(), "127.0.0.1:1", entities.GRPCConfig{}, 2*time.Second)
+
+	if result.Success {
+		t.Errorf("Expected failure for connection refused, got success")
+	}
+	if result.Code != codes.Unavailable.String() {
+		t.Errorf("Expected code Unavailable, got %q (error: %v)", result.Code, result.Error)
+	}
+}
+
+func TestGRPCChecker_Check_ClientCertificate(t *testing.T) {
+	clientCertificate, clientCAs := newTestClientCertificate(t)
+	httpServer := httptest.NewTLSServer(nil)
+	certificates := httpServer.TLS.Certificates
+	roots := x509.NewCertPool()
+	roots.AddCert(httpServer.Certificate())
+	httpServer.Close()
+
+	address, _ := startHealthServer(t, grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: certificates,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	})))
+
+	checker := &GRPCChecker{rootCAs: roots}
+	result := checker.Check(context.Background(), address, entities.GRPCConfig{TLS: true}, 2*time.Second)
+	if result.Success {
+		t.Errorf("Expected the server to reject a connection without client certificate")
+	}
+
+	result = checker.WithClientCertificate(clientCertificate).Check(context.Background(), address, entities.GRPCConfig{TLS: true}, 2*time.Second)
+	if !result.Success {
+		t.Errorf("Expected success with client certificate, got: %v", result.Error)
+	}
+}
@@ -0,0 +1,144 @@
+package executor
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+	"github.com/tidwall/gjson"
+)
+
+// assertionActual is the value an assertion is evaluated against
+type assertionActual struct {
+	value  string
+	exists bool
+}
+
+// evaluateAssertions checks every assertion against the response and returns an error
+// describing the first assertion that fails. When expand is set it resolves the expected values
+// before comparing, the error describes the assertion as configured.
+func evaluateAssertions(resp *http.Response, body []byte, assertions []entities.HTTPAssertion, expand func(string) string) error {
+	for _, assertion := range assertions {
+		actual, err := resolveAssertionActual(resp, body, assertion)
+		if err != nil {
+			return fmt.Errorf("assertion failed: %s: %w", describeAssertion(assertion), err)
+		}
+
+		expected := assertion.Value
+		if expand != nil {
+			expected = expand(expected)
+		}
+		ok, err := compareAssertion(actual, assertion.Operator, expected)
+		if err != nil {
+			return fmt.Errorf("assertion failed: %s: %w", describeAssertion(assertion), err)
+		}
+		if !ok {
+			got := "<missing>"
+			if actual.exists {
+				got = fmt.Sprintf("%q", truncateForError(actual.value))
+			}
+			return fmt.Errorf("assertion failed: %s (got %s)", describeAssertion(assertion), got)
+		}
+	}
+
+	return nil
+}
+
+// resolveAssertionActual extracts the value an assertion refers to from the response
+func resolveAssertionActual(resp *http.Response, body []byte, assertion entities.HTTPAssertion) (assertionActual, error) {
+	switch assertion.Source {
+	case "json":
+		if !gjson.ValidBytes(body) {
+			return assertionActual{}, fmt.Errorf("response body is not valid JSON")
+		}
+		result := gjson.GetBytes(body, assertion.Property)
+		return assertionActual{value: result.String(), exists: result.Exists()}, nil
+	case "header":
+		values, ok := resp.Header[http.CanonicalHeaderKey(assertion.Property)]
+		return assertionActual{value: strings.Join(values, ", "), exists: ok}, nil
+	case "content_length":
+		length := resp.ContentLength
+		if length < 0 {
+			// Unknown length (chunked), fall back to the bytes read
+			length = int64(len(body))
+		}
+		return assertionActual{value: strconv.FormatInt(length, 10), exists: true}, nil
+	default:
+		return assertionActual{}, fmt.Errorf("unsupported source %q", assertion.Source)
+	}
+}
+
+// compareAssertion applies the operator to the actual and expected values
+func compareAssertion(actual assertionActual, operator, expected string) (bool, error) {
+	if operator == "exists" {
+		return actual.exists, nil
+	}
+	if !actual.exists {
+		// A missing value can only satisfy not_equals
+		return operator == "not_equals", nil
+	}
+
+	switch operator {
+	case "equals":
+		return valuesEqual(actual.value, expected), nil
+	case "not_equals":
+		return !valuesEqual(actual.value, expected), nil
+	case "contains":
+		return strings.Contains(actual.value, expected), nil
+	case "greater_than", "less_than":
+		got, err := strconv.ParseFloat(actual.value, 64)
+		if err != nil {
+			return false, fmt.Errorf("value %q is not a number", truncateForError(actual.value))
+		}
+		want, err := strconv.ParseFloat(expected, 64)
+		if err != nil {
+			return false, fmt.Errorf("expected value %q is not a number", expected)
+		}
+		if operator == "greater_than" {
+			return got > want, nil
+		}
+		return got < want, nil
+	case "matches":
+		re, err := regexp.Compile(expected)
+		if err != nil {
+			return false, fmt.Errorf("invalid regex: %w", err)
+		}
+		return re.MatchString(actual.value), nil
+	default:
+		return false, fmt.Errorf("unsupported operator %q", operator)
+	}
+}
+
+// valuesEqual compares two values numerically when both are numbers, otherwise as strings
+func valuesEqual(actual, expected string) bool {
+	if actual == expected {
+		return true
+	}
+	a, errA := strconv.ParseFloat(actual, 64)
+	b, errB := strconv.ParseFloat(expected, 64)
+	return errA == nil && errB == nil && a == b
+}
+
+// describeAssertion formats an assertion for error messages, e.g. json "status" equals "ok"
+func describeAssertion(assertion entities.HTTPAssertion) string {
+	target := assertion.Source
+	if assertion.Property != "" {
+		target = fmt.Sprintf("%s %q", assertion.Source, assertion.Property)
+	}
+	if assertion.Operator == "exists" {
+		return fmt.Sprintf("%s exists", target)
+	}
+	return fmt.Sprintf("%s %s %q", target, strings.ReplaceAll(assertion.Operator, "_", " "), assertion.Value)
+}
+
+// truncateForError shortens long values so error messages stay readable
+func truncateForError(value string) string {
+	const maxLen = 100
+	if len(value) > maxLen {
+		return value[:maxLen] + "..."
+	}
+	return value
+}
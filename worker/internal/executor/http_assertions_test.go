@@ -0,0 +1,65 @@
+package executor
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+)
+
+func TestEvaluateAssertions(t *testing.T) {
+	body := []byte(`{"status":"ok","version":"1.4.2","queue":{"depth":12},"workers":[{"name":"a"},{"name":"b"}]}`)
+	resp := &http.Response{
+		Header:        http.Header{"Content-Type": []string{"application/json; charset=utf-8"}},
+		ContentLength: int64(len(body)),
+	}
+
+	tests := []struct {
+		name      string
+		assertion entities.HTTPAssertion
+		wantErr   string
+	}{
+		{"json equals", entities.HTTPAssertion{Source: "json", Property: "status", Operator: "equals", Value: "ok"}, ""},
+		{"json equals mismatch", entities.HTTPAssertion{Source: "json", Property: "status", Operator: "equals", Value: "healthy"}, `json "status" equals "healthy" (got "ok")`},
+		{"json not equals", entities.HTTPAssertion{Source: "json", Property: "status", Operator: "not_equals", Value: "degraded"}, ""},
+		{"json numeric equals", entities.HTTPAssertion{Source: "json", Property: "queue.depth", Operator: "equals", Value: "12.0"}, ""},
+		{"json less than", entities.HTTPAssertion{Source: "json", Property: "queue.depth", Operator: "less_than", Value: "100"}, ""},
+		{"json greater than mismatch", entities.HTTPAssertion{Source: "json", Property: "queue.depth", Operator: "greater_than", Value: "100"}, `(got "12")`},
+		{"json not a number", entities.HTTPAssertion{Source: "json", Property: "status", Operator: "greater_than", Value: "1"}, "is not a number"},
+		{"json array length", entities.HTTPAssertion{Source: "json", Property: "workers.#", Operator: "equals", Value: "2"}, ""},
+		{"json exists", entities.HTTPAssertion{Source: "json", Property: "workers.1.name", Operator: "exists"}, ""},
+		{"json missing", entities.HTTPAssertion{Source: "json", Property: "uptime", Operator: "exists"}, `json "uptime" exists (got <missing>)`},
+		{"json matches", entities.HTTPAssertion{Source: "json", Property: "version", Operator: "matches", Value: `^1\.\d+\.\d+$`}, ""},
+		{"header contains", entities.HTTPAssertion{Source: "header", Property: "content-type", Operator: "contains", Value: "application/json"}, ""},
+		{"header missing", entities.HTTPAssertion{Source: "header", Property: "X-Cache", Operator: "equals", Value: "HIT"}, "(got <missing>)"},
+		{"content length", entities.HTTPAssertion{Source: "content_length", Operator: "greater_than", Value: "10"}, ""},
+		{"content length too small", entities.HTTPAssertion{Source: "content_length", Operator: "less_than", Value: "10"}, "content_length less than"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := evaluateAssertions(resp, body, []entities.HTTPAssertion{tt.assertion}, nil)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected assertion to pass, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestEvaluateAssertions_InvalidJSON(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}, ContentLength: -1}
+
+	err := evaluateAssertions(resp, []byte("<html>"), []entities.HTTPAssertion{
+		{Source: "json", Property: "status", Operator: "equals", Value: "ok"},
+	}, nil)
+	if err == nil || !strings.Contains(err.Error(), "not valid JSON") {
+		t.Errorf("Expected invalid JSON error, got: %v", err)
+	}
+}
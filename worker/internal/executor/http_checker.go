@@ -2,41 +2,278 @@ package executor
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/http/httptrace"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
 )
 
 // HTTPCheckResult represents the result of an HTTP health check
 type HTTPCheckResult struct {
 	StatusCode   int
 	ResponseTime time.Duration
+	Timing       HTTPTiming
+	Headers      http.Header   // response headers, nil if no response was received
+	Body         []byte        // first 1MB of the response body
+	Redirects    []RedirectHop // every request of the check when it was redirected, nil otherwise
+	RemoteIP     string        // address of the last connection used, the proxy when one is configured
+	TLS          *TLSInfo      // connection that answered an HTTPS request, nil for plain HTTP
 	Error        error
 	Success      bool
 }
 
+// HTTPTiming is the breakdown of an HTTP check into sequential phases
+// Phases of every request in a redirect chain are summed; reused connections report zero DNS, connect and TLS time
+type HTTPTiming struct {
+	DNSLookup    time.Duration // resolving the host name
+	TCPConnect   time.Duration // establishing the TCP connection
+	TLSHandshake time.Duration // TLS handshake
+	TTFB         time.Duration // from the request being written to the first response byte
+	Transfer     time.Duration // reading the response body
+}
+
+// RedirectHop is one request of a redirect chain
+type RedirectHop struct {
+	URL        string
+	StatusCode int
+	Location   string // resolved Location header of a redirect response
+}
+
+// TLSInfo describes the TLS connection a response was received on
+type TLSInfo struct {
+	Version       string
+	CipherSuite   string
+	ServerName    string // SNI sent during the handshake
+	HostnameMatch bool
+	Chain         []CertificateInfo // certificates presented by the server, leaf first
+	Weaknesses    []string
+}
+
+// newTLSInfo describes a negotiated connection
+func newTLSInfo(state *tls.ConnectionState) *TLSInfo {
+	info := &TLSInfo{
+		Version:     tls.VersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+		ServerName:  state.ServerName,
+		Weaknesses:  tlsWeaknesses(*state),
+	}
+	for _, cert := range state.PeerCertificates {
+		info.Chain = append(info.Chain, describeCertificate(cert))
+	}
+	if len(state.PeerCertificates) > 0 && state.ServerName != "" {
+		info.HostnameMatch = state.PeerCertificates[0].VerifyHostname(state.ServerName) == nil
+	}
+	return info
+}
+
+// DefaultMaxRedirects is the number of redirects followed when a monitor does not set one
+const DefaultMaxRedirects = 5
+
 // HTTPChecker performs HTTP health checks
 type HTTPChecker struct {
 	client *http.Client
+
+	pinnedHost   string // monitor host that hostOverride applies to
+	hostOverride string // Host header sent to pinnedHost unless the request sets one
+
+	redirect *entities.RedirectConfig // expected final URL and Location, nil when not checked
+
+	// expand resolves {{variable}} placeholders of the keyword and assertion values before they
+	// are compared; error messages show the placeholders so that they never contain secrets
+	expand func(string) string
 }
 
 // NewHTTPChecker creates a new HTTP checker
 func NewHTTPChecker() *HTTPChecker {
 	return &HTTPChecker{
 		client: &http.Client{
-			CheckRedirect: func(req *http.Request, via []*http.Request) error {
-				// Allow up to 5 redirects
-				if len(via) >= 5 {
-					return fmt.Errorf("stopped after 5 redirects")
-				}
-				return nil
-			},
+			CheckRedirect: redirectPolicy(true, DefaultMaxRedirects),
 		},
 	}
 }
 
+// redirectPolicy returns the CheckRedirect function of a client that follows up to maxRedirects redirects,
+// or none so that the redirect response itself answers the check
+func redirectPolicy(follow bool, maxRedirects int) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if !follow {
+			return http.ErrUseLastResponse
+		}
+		if len(via) > maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		return nil
+	}
+}
+
+// WithRedirectPolicy returns a copy of the checker that follows redirects as set in cfg and
+// fails checks that do not end at the expected URL or Location
+func (c *HTTPChecker) WithRedirectPolicy(cfg *entities.RedirectConfig) *HTTPChecker {
+	if cfg == nil {
+		return c
+	}
+
+	maxRedirects := cfg.MaxRedirects
+	if maxRedirects <= 0 {
+		maxRedirects = DefaultMaxRedirects
+	}
+
+	client := *c.client
+	client.CheckRedirect = redirectPolicy(cfg.Follow == nil || *cfg.Follow, maxRedirects)
+	checker := *c
+	checker.client = &client
+	checker.redirect = cfg
+	return &checker
+}
+
+// withCookieJar returns a copy of the checker whose client stores cookies in jar
+func (c *HTTPChecker) withCookieJar(jar http.CookieJar) *HTTPChecker {
+	client := *c.client
+	client.Jar = jar
+	checker := *c
+	checker.client = &client
+	return &checker
+}
+
+// withPlaceholders returns a copy of the checker that resolves placeholders with expand
+func (c *HTTPChecker) withPlaceholders(expand func(string) string) *HTTPChecker {
+	checker := *c
+	checker.expand = expand
+	return &checker
+}
+
+// tlsVersions maps the accepted min_tls_version values to their crypto/tls constants
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ConnectionOptions are the per-monitor settings of an HTTP checker transport
+type ConnectionOptions struct {
+	Config            *entities.ConnectionConfig // TLS and addressing overrides
+	PinnedHost        string                     // monitor URL host the SNI, Host and address overrides apply to
+	ClientCertificate *tls.Certificate           // presented when the server requests one (mutual TLS)
+	Proxy             *entities.ProxyConfig      // HTTP CONNECT or SOCKS5 proxy, direct connections when nil
+}
+
+// WithConnection returns a copy of the checker with its own transport configured from opts
+// Keep-alives are disabled because the transport only lives for one check
+func (c *HTTPChecker) WithConnection(opts ConnectionOptions) (*HTTPChecker, error) {
+	if opts.Config == nil && opts.ClientCertificate == nil && opts.Proxy == nil {
+		return c, nil
+	}
+	cfg := opts.Config
+	if cfg == nil {
+		cfg = &entities.ConnectionConfig{}
+	}
+	pinnedHost := opts.PinnedHost
+
+	network := "tcp"
+	switch strings.ToLower(cfg.IPFamily) {
+	case "":
+	case "ipv4":
+		network = "tcp4"
+	case "ipv6":
+		network = "tcp6"
+	default:
+		return nil, fmt.Errorf("unsupported IP family %q", cfg.IPFamily)
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+	if opts.ClientCertificate != nil {
+		tlsConfig.Certificates = []tls.Certificate{*opts.ClientCertificate}
+	}
+	if cfg.MinTLSVersion != "" {
+		version, ok := tlsVersions[cfg.MinTLSVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported minimum TLS version %q", cfg.MinTLSVersion)
+		}
+		tlsConfig.MinVersion = version
+	}
+
+	if cfg.ResolveTo != "" && net.ParseIP(cfg.ResolveTo) == nil {
+		return nil, fmt.Errorf("resolve_to %q is not an IP address", cfg.ResolveTo)
+	}
+
+	isPinned := func(addr string) bool {
+		host, _, err := net.SplitHostPort(addr)
+		return err == nil && strings.EqualFold(host, pinnedHost)
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DisableKeepAlives = true
+	transport.TLSClientConfig = tlsConfig
+	transport.DialContext = func(ctx context.Context, _, addr string) (net.Conn, error) {
+		if cfg.ResolveTo != "" && isPinned(addr) {
+			_, port, _ := net.SplitHostPort(addr)
+			addr = net.JoinHostPort(cfg.ResolveTo, port)
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}
+
+	if opts.Proxy != nil {
+		proxyURL, err := newProxyURL(opts.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	// The transport always sends the URL host as SNI, so the handshake is done here
+	// to override it for the monitor host only. Through a proxy the transport does the
+	// handshake itself after tunnelling, so the override then applies to every host.
+	if cfg.ServerName != "" && opts.Proxy != nil {
+		tlsConfig.ServerName = cfg.ServerName
+	} else if cfg.ServerName != "" {
+		transport.DialTLSContext = func(ctx context.Context, _, addr string) (net.Conn, error) {
+			rawConn, err := transport.DialContext(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+
+			conf := transport.TLSClientConfig.Clone()
+			conf.ServerName, _, _ = net.SplitHostPort(addr)
+			if isPinned(addr) {
+				conf.ServerName = cfg.ServerName
+			}
+
+			trace := httptrace.ContextClientTrace(ctx)
+			if trace != nil && trace.TLSHandshakeStart != nil {
+				trace.TLSHandshakeStart()
+			}
+			conn := tls.Client(rawConn, conf)
+			err = conn.HandshakeContext(ctx)
+			if trace != nil && trace.TLSHandshakeDone != nil {
+				trace.TLSHandshakeDone(conn.ConnectionState(), err)
+			}
+			if err != nil {
+				rawConn.Close()
+				return nil, err
+			}
+			return conn, nil
+		}
+	}
+
+	client := *c.client
+	client.Transport = transport
+	checker := *c
+	checker.client = &client
+	checker.pinnedHost = pinnedHost
+	checker.hostOverride = cfg.ServerName
+	return &checker, nil
+}
+
 // CheckURL performs an HTTP health check on the given URL
 // Returns status code, response time, and any error encountered
 func (c *HTTPChecker) CheckURL(ctx context.Context, url string, timeout time.Duration, keyword string) HTTPCheckResult {
@@ -46,12 +283,18 @@ func (c *HTTPChecker) CheckURL(ctx context.Context, url string, timeout time.Dur
 // CheckURLWithExpectedCodes performs an HTTP health check on the given URL with custom expected status codes
 // If expectedCodes is nil or empty, defaults to 2xx and 3xx status codes as successful
 func (c *HTTPChecker) CheckURLWithExpectedCodes(ctx context.Context, url string, timeout time.Duration, keyword string, expectedCodes []int64) HTTPCheckResult {
+	return c.CheckRequest(ctx, url, timeout, keyword, expectedCodes, nil)
+}
+
+// CheckRequest performs an HTTP health check using the method, headers, body and auth from cfg
+// A nil cfg sends a plain GET request
+func (c *HTTPChecker) CheckRequest(ctx context.Context, url string, timeout time.Duration, keyword string, expectedCodes []int64, cfg *entities.HTTPConfig) HTTPCheckResult {
 	// Create a context with timeout
 	checkCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	// Create HTTP request
-	req, err := http.NewRequestWithContext(checkCtx, http.MethodGet, url, nil)
+	req, err := newMonitorRequest(checkCtx, url, cfg)
 	if err != nil {
 		return HTTPCheckResult{
 			StatusCode:   0,
@@ -61,8 +304,17 @@ func (c *HTTPChecker) CheckURLWithExpectedCodes(ctx context.Context, url string,
 		}
 	}
 
-	// Set user agent
-	req.Header.Set("User-Agent", "V-Insight-Monitor/1.0")
+	// An SNI override is also sent as the Host header, unless the request sets its own
+	if c.hostOverride != "" && req.Host == req.URL.Host && strings.EqualFold(req.URL.Hostname(), c.pinnedHost) {
+		req.Host = c.hostOverride
+		if port := req.URL.Port(); port != "" {
+			req.Host = net.JoinHostPort(c.hostOverride, port)
+		}
+	}
+
+	// Record the phases of the request
+	recorder := &httpTimingRecorder{}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), recorder.clientTrace()))
 
 	// Measure response time
 	startTime := time.Now()
@@ -70,15 +322,45 @@ func (c *HTTPChecker) CheckURLWithExpectedCodes(ctx context.Context, url string,
 	responseTime := time.Since(startTime)
 
 	if err != nil {
-		return HTTPCheckResult{
+		result := HTTPCheckResult{
 			StatusCode:   0,
 			ResponseTime: responseTime,
+			Timing:       recorder.timing(),
+			RemoteIP:     recorder.remoteIP(),
 			Error:        fmt.Errorf("HTTP request failed: %w", err),
 			Success:      false,
 		}
+		// When the redirect policy stops the check, the last response is still returned
+		if resp != nil {
+			resp.Body.Close()
+			result.Redirects = redirectChain(resp)
+			result.Headers = resp.Header
+			if resp.TLS != nil {
+				result.TLS = newTLSInfo(resp.TLS)
+			}
+		}
+		return result
 	}
 	defer resp.Body.Close()
 
+	// Read body to measure the transfer (limit to 1MB to prevent OOM)
+	transferStart := time.Now()
+	bodyBytes, bodyErr := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	recorder.setTransfer(time.Since(transferStart))
+
+	result := HTTPCheckResult{
+		StatusCode:   resp.StatusCode,
+		ResponseTime: responseTime,
+		Timing:       recorder.timing(),
+		Headers:      resp.Header,
+		Body:         bodyBytes,
+		Redirects:    redirectChain(resp),
+		RemoteIP:     recorder.remoteIP(),
+	}
+	if resp.TLS != nil {
+		result.TLS = newTLSInfo(resp.TLS)
+	}
+
 	// Determine success based on expected status codes
 	var success bool
 	if len(expectedCodes) > 0 {
@@ -96,32 +378,267 @@ func (c *HTTPChecker) CheckURLWithExpectedCodes(ctx context.Context, url string,
 	}
 
 	if success && keyword != "" {
-		// Read body and check for keyword (limit to 1MB to prevent OOM)
-		bodyReader := io.LimitReader(resp.Body, 1024*1024)
-		bodyBytes, err := io.ReadAll(bodyReader)
-		if err != nil {
-			return HTTPCheckResult{
-				StatusCode:   resp.StatusCode,
-				ResponseTime: responseTime,
-				Error:        fmt.Errorf("failed to read response body: %w", err),
-				Success:      false,
+		if bodyErr != nil {
+			result.Error = fmt.Errorf("failed to read response body: %w", bodyErr)
+			return result
+		}
+
+		expected := keyword
+		if c.expand != nil {
+			expected = c.expand(keyword)
+		}
+		if !strings.Contains(string(bodyBytes), expected) {
+			result.Error = fmt.Errorf("keyword '%s' not found in response", keyword)
+			return result
+		}
+	}
+
+	if success && c.redirect != nil {
+		if err := checkRedirectTarget(resp, c.redirect); err != nil {
+			result.Error = err
+			return result
+		}
+	}
+
+	if success && cfg != nil && len(cfg.Assertions) > 0 {
+		if bodyErr != nil {
+			result.Error = fmt.Errorf("failed to read response body: %w", bodyErr)
+			return result
+		}
+
+		if err := evaluateAssertions(resp, bodyBytes, cfg.Assertions, c.expand); err != nil {
+			result.Error = err
+			return result
+		}
+	}
+
+	result.Success = success
+	return result
+}
+
+// redirectChain returns every request that led to resp, in order, or nil when the check was not redirected
+func redirectChain(resp *http.Response) []RedirectHop {
+	var hops []RedirectHop
+	for r := resp; r != nil; r = r.Request.Response {
+		hop := RedirectHop{URL: r.Request.URL.String(), StatusCode: r.StatusCode}
+		if isRedirect(r.StatusCode) {
+			if location, err := r.Location(); err == nil {
+				hop.Location = location.String()
 			}
 		}
+		hops = append([]RedirectHop{hop}, hops...)
+	}
+
+	if len(hops) == 1 && hops[0].Location == "" {
+		return nil
+	}
+	return hops
+}
+
+// isRedirect reports whether the status code is a redirect the client can follow
+func isRedirect(statusCode int) bool {
+	switch statusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// checkRedirectTarget verifies that the check ended at the expected URL and, when redirects
+// are not followed, that the response redirects to the expected Location
+func checkRedirectTarget(resp *http.Response, cfg *entities.RedirectConfig) error {
+	if cfg.ExpectedFinalURL != "" && !sameURL(resp.Request.URL, cfg.ExpectedFinalURL) {
+		return fmt.Errorf("final URL %s does not match expected %s", resp.Request.URL, cfg.ExpectedFinalURL)
+	}
+
+	if cfg.ExpectedLocation != "" {
+		location, err := resp.Location()
+		if err != nil {
+			return fmt.Errorf("response has no Location header, expected %s", cfg.ExpectedLocation)
+		}
+		if !sameURL(location, cfg.ExpectedLocation) {
+			return fmt.Errorf("redirect location %s does not match expected %s", location, cfg.ExpectedLocation)
+		}
+	}
+
+	return nil
+}
+
+// sameURL compares a URL with the expected one, ignoring the case of the scheme and host,
+// the fragment and a missing root path
+func sameURL(actual *url.URL, expected string) bool {
+	want, err := url.Parse(expected)
+	if err != nil {
+		return false
+	}
+
+	normalize := func(u *url.URL) string {
+		n := *u
+		n.Scheme = strings.ToLower(n.Scheme)
+		n.Host = strings.ToLower(n.Host)
+		n.Fragment, n.RawFragment = "", ""
+		if n.Path == "" {
+			n.Path = "/"
+		}
+		return n.String()
+	}
+	return normalize(actual) == normalize(want)
+}
+
+// httpTimingRecorder accumulates phase durations reported by httptrace, and the connection they were measured on
+// Callbacks may run on different goroutines, so access is guarded by a mutex
+type httpTimingRecorder struct {
+	mu           sync.Mutex
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	wroteRequest time.Time
+	remoteAddr   net.Addr // connection of the latest request
+	result       HTTPTiming
+}
 
-		if !strings.Contains(string(bodyBytes), keyword) {
-			return HTTPCheckResult{
-				StatusCode:   resp.StatusCode,
-				ResponseTime: responseTime,
-				Error:        fmt.Errorf("keyword '%s' not found in response", keyword),
-				Success:      false,
+// clientTrace returns the httptrace hooks that feed the recorder
+func (r *httpTimingRecorder) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			if !r.dnsStart.IsZero() {
+				r.result.DNSLookup += time.Since(r.dnsStart)
+				r.dnsStart = time.Time{}
+			}
+		},
+		ConnectStart: func(string, string) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			// With parallel dials only the first attempt starts the clock
+			if r.connectStart.IsZero() {
+				r.connectStart = time.Now()
 			}
+		},
+		ConnectDone: func(_, _ string, err error) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			if err == nil && !r.connectStart.IsZero() {
+				r.result.TCPConnect += time.Since(r.connectStart)
+				r.connectStart = time.Time{}
+			}
+		},
+		TLSHandshakeStart: func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			if !r.tlsStart.IsZero() {
+				r.result.TLSHandshake += time.Since(r.tlsStart)
+				r.tlsStart = time.Time{}
+			}
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.remoteAddr = info.Conn.RemoteAddr()
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.wroteRequest = time.Now()
+		},
+		GotFirstResponseByte: func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			if !r.wroteRequest.IsZero() {
+				r.result.TTFB += time.Since(r.wroteRequest)
+				r.wroteRequest = time.Time{}
+			}
+		},
+	}
+}
+
+// setTransfer records the time spent reading the response body
+func (r *httpTimingRecorder) setTransfer(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.result.Transfer = d
+}
+
+// remoteIP returns the IP address of the latest connection, empty when none was established
+func (r *httpTimingRecorder) remoteIP() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.remoteAddr == nil {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(r.remoteAddr.String()); err == nil {
+		return host
+	}
+	return r.remoteAddr.String()
+}
+
+// timing returns a snapshot of the recorded phases
+func (r *httpTimingRecorder) timing() HTTPTiming {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.result
+}
+
+// newMonitorRequest builds the HTTP request for a check from the monitor's request settings
+func newMonitorRequest(ctx context.Context, url string, cfg *entities.HTTPConfig) (*http.Request, error) {
+	method := http.MethodGet
+	var body io.Reader
+	if cfg != nil {
+		if cfg.Method != "" {
+			method = strings.ToUpper(cfg.Method)
+		}
+		if cfg.Body != "" {
+			body = strings.NewReader(cfg.Body)
 		}
 	}
 
-	return HTTPCheckResult{
-		StatusCode:   resp.StatusCode,
-		ResponseTime: responseTime,
-		Error:        nil,
-		Success:      success,
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set user agent (custom headers below may override it)
+	req.Header.Set("User-Agent", "V-Insight-Monitor/1.0")
+
+	if cfg == nil {
+		return req, nil
+	}
+
+	if cfg.ContentType != "" {
+		req.Header.Set("Content-Type", cfg.ContentType)
+	}
+
+	for name, value := range cfg.Headers {
+		// The Host header is taken from req.Host, not from the header map
+		if strings.EqualFold(name, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(name, value)
 	}
+
+	if cfg.Auth != nil {
+		switch strings.ToLower(cfg.Auth.Type) {
+		case "basic":
+			req.SetBasicAuth(cfg.Auth.Username, cfg.Auth.Password)
+		case "bearer":
+			req.Header.Set("Authorization", "Bearer "+cfg.Auth.Token)
+		default:
+			return nil, fmt.Errorf("unsupported auth type: %s", cfg.Auth.Type)
+		}
+	}
+
+	return req, nil
 }
@@ -2,10 +2,18 @@ package executor
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
 )
 
 func TestHTTPChecker_CheckURL_Success(t *testing.T) {
@@ -79,6 +87,72 @@ func TestHTTPChecker_CheckURL_Redirect(t *testing.T) {
 	}
 }
 
+func TestHTTPChecker_WithRedirectPolicy(t *testing.T) {
+	// /vanity -> /hop -> /final, /loop redirects to itself
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/vanity":
+			http.Redirect(w, r, "/hop", http.StatusMovedPermanently)
+		case "/hop":
+			http.Redirect(w, r, "/final", http.StatusFound)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	noFollow := false
+	tests := []struct {
+		name          string
+		path          string
+		cfg           *entities.RedirectConfig
+		expectSuccess bool
+		expectStatus  int
+		expectHops    int
+	}{
+		{"follows and records the chain", "/vanity", nil, true, http.StatusOK, 3},
+		{"expected final URL", "/vanity", &entities.RedirectConfig{ExpectedFinalURL: server.URL + "/final"}, true, http.StatusOK, 3},
+		{"unexpected final URL", "/vanity", &entities.RedirectConfig{ExpectedFinalURL: server.URL + "/home"}, false, http.StatusOK, 3},
+		{"max redirects exceeded", "/vanity", &entities.RedirectConfig{MaxRedirects: 1}, false, 0, 2},
+		{"max redirects reached", "/vanity", &entities.RedirectConfig{MaxRedirects: 2}, true, http.StatusOK, 3},
+		{"redirect loop", "/loop", nil, false, 0, DefaultMaxRedirects + 1},
+		{"not followed", "/vanity", &entities.RedirectConfig{Follow: &noFollow}, true, http.StatusMovedPermanently, 1},
+		{"expected location", "/vanity", &entities.RedirectConfig{Follow: &noFollow, ExpectedLocation: server.URL + "/hop"}, true, http.StatusMovedPermanently, 1},
+		{"unexpected location", "/vanity", &entities.RedirectConfig{Follow: &noFollow, ExpectedLocation: server.URL + "/parking"}, false, http.StatusMovedPermanently, 1},
+		{"no location", "/final", &entities.RedirectConfig{Follow: &noFollow, ExpectedLocation: server.URL + "/hop"}, false, http.StatusOK, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewHTTPChecker().WithRedirectPolicy(tt.cfg).CheckURL(context.Background(), server.URL+tt.path, 5*time.Second, "")
+
+			if result.Success != tt.expectSuccess {
+				t.Errorf("Expected success=%v, got %v (error: %v)", tt.expectSuccess, result.Success, result.Error)
+			}
+			if result.StatusCode != tt.expectStatus {
+				t.Errorf("Expected status code %d, got %d", tt.expectStatus, result.StatusCode)
+			}
+			if len(result.Redirects) != tt.expectHops {
+				t.Fatalf("Expected %d requests in the redirect chain, got %+v", tt.expectHops, result.Redirects)
+			}
+		})
+	}
+
+	result := NewHTTPChecker().CheckURL(context.Background(), server.URL+"/vanity", 5*time.Second, "")
+	expected := []RedirectHop{
+		{URL: server.URL + "/vanity", StatusCode: http.StatusMovedPermanently, Location: server.URL + "/hop"},
+		{URL: server.URL + "/hop", StatusCode: http.StatusFound, Location: server.URL + "/final"},
+		{URL: server.URL + "/final", StatusCode: http.StatusOK},
+	}
+	for i, hop := range expected {
+		if result.Redirects[i] != hop {
+			t.Errorf("Expected hop %d to be %+v, got %+v", i, hop, result.Redirects[i])
+		}
+	}
+}
+
 func TestHTTPChecker_CheckURL_Timeout(t *testing.T) {
 	// Create a test server that delays response
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -183,3 +257,320 @@ func TestHTTPChecker_CheckURL_Keyword(t *testing.T) {
 		t.Errorf("Expected error for non-matching keyword, got nil")
 	}
 }
+
+func TestHTTPChecker_CheckRequest_MethodHeadersBody(t *testing.T) {
+	var receivedMethod, receivedContentType, receivedHeader, receivedBody, receivedUserAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedMethod = r.Method
+		receivedContentType = r.Header.Get("Content-Type")
+		receivedHeader = r.Header.Get("X-Request-Source")
+		receivedUserAgent = r.Header.Get("User-Agent")
+		body, _ := io.ReadAll(r.Body)
+		receivedBody = string(body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	checker := NewHTTPChecker()
+	ctx := context.Background()
+
+	result := checker.CheckRequest(ctx, server.URL, 5*time.Second, "", []int64{201}, &entities.HTTPConfig{
+		Method:      "post",
+		Headers:     map[string]string{"X-Request-Source": "monitor", "User-Agent": "Custom-Agent"},
+		Body:        `{"ping":true}`,
+		ContentType: "application/json",
+	})
+
+	if !result.Success {
+		t.Errorf("Expected success, got error: %v", result.Error)
+	}
+	if receivedMethod != http.MethodPost {
+		t.Errorf("Expected method POST, got %s", receivedMethod)
+	}
+	if receivedContentType != "application/json" {
+		t.Errorf("Expected Content-Type 'application/json', got '%s'", receivedContentType)
+	}
+	if receivedHeader != "monitor" {
+		t.Errorf("Expected custom header 'monitor', got '%s'", receivedHeader)
+	}
+	if receivedBody != `{"ping":true}` {
+		t.Errorf("Expected request body to be sent, got '%s'", receivedBody)
+	}
+	if receivedUserAgent != "Custom-Agent" {
+		t.Errorf("Expected custom User-Agent to override the default, got '%s'", receivedUserAgent)
+	}
+}
+
+func TestHTTPChecker_CheckRequest_Auth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); ok && user == "admin" && pass == "secret" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		if r.Header.Get("Authorization") == "Bearer token-123" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	checker := NewHTTPChecker()
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		auth    *entities.HTTPAuth
+		success bool
+	}{
+		{"basic", &entities.HTTPAuth{Type: "basic", Username: "admin", Password: "secret"}, true},
+		{"bearer", &entities.HTTPAuth{Type: "bearer", Token: "token-123"}, true},
+		{"wrong password", &entities.HTTPAuth{Type: "basic", Username: "admin", Password: "wrong"}, false},
+		{"no auth", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := checker.CheckRequest(ctx, server.URL, 5*time.Second, "", nil, &entities.HTTPConfig{Auth: tt.auth})
+			if result.Success != tt.success {
+				t.Errorf("Expected success=%v, got %v (status %d)", tt.success, result.Success, result.StatusCode)
+			}
+		})
+	}
+}
+
+func TestHTTPChecker_CheckURL_TimingBreakdown(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+
+	checker := NewHTTPChecker()
+	// Trust the test certificate, which is issued for example.com
+	transport := server.Client().Transport.(*http.Transport).Clone()
+	transport.TLSClientConfig.ServerName = "example.com"
+	checker.client.Transport = transport
+	ctx := context.Background()
+
+	// Use a host name so that a DNS lookup happens
+	url := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	result := checker.CheckURL(ctx, url, 5*time.Second, "")
+
+	if !result.Success {
+		t.Fatalf("Expected success, got error: %v", result.Error)
+	}
+	if result.Timing.DNSLookup <= 0 {
+		t.Errorf("Expected positive DNS lookup time, got %v", result.Timing.DNSLookup)
+	}
+	if result.Timing.TCPConnect <= 0 {
+		t.Errorf("Expected positive TCP connect time, got %v", result.Timing.TCPConnect)
+	}
+	if result.Timing.TLSHandshake <= 0 {
+		t.Errorf("Expected positive TLS handshake time, got %v", result.Timing.TLSHandshake)
+	}
+	if result.Timing.TTFB < 20*time.Millisecond {
+		t.Errorf("Expected TTFB to include server processing time, got %v", result.Timing.TTFB)
+	}
+	if result.Timing.Transfer < 0 {
+		t.Errorf("Expected non-negative transfer time, got %v", result.Timing.Transfer)
+	}
+}
+
+func TestHTTPChecker_CheckURL_Connection(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	checker := NewHTTPChecker()
+	transport := server.Client().Transport.(*http.Transport).Clone()
+	transport.TLSClientConfig.ServerName = "example.com"
+	checker.client.Transport = transport
+
+	result := checker.CheckURL(context.Background(), server.URL, 5*time.Second, "")
+
+	if result.Success {
+		t.Fatal("Expected a 502 response to fail the check")
+	}
+	if result.RemoteIP != "127.0.0.1" {
+		t.Errorf("Expected remote IP 127.0.0.1, got %q", result.RemoteIP)
+	}
+	if result.TLS == nil {
+		t.Fatal("Expected the TLS connection to be described")
+	}
+	if result.TLS.Version == "" || result.TLS.CipherSuite == "" || result.TLS.ServerName != "example.com" {
+		t.Errorf("Unexpected TLS details: %+v", result.TLS)
+	}
+	if len(result.TLS.Chain) == 0 || !result.TLS.HostnameMatch {
+		t.Errorf("Expected the presented certificate to match example.com, got %+v", result.TLS)
+	}
+
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer plain.Close()
+
+	result = NewHTTPChecker().CheckURL(context.Background(), plain.URL, 5*time.Second, "")
+	if result.TLS != nil {
+		t.Errorf("Expected no TLS details for plain HTTP, got %+v", result.TLS)
+	}
+	if result.RemoteIP != "127.0.0.1" {
+		t.Errorf("Expected remote IP 127.0.0.1, got %q", result.RemoteIP)
+	}
+}
+
+func TestHTTPChecker_CheckRequest_Assertions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status":"degraded"}`))
+	}))
+	defer server.Close()
+
+	checker := NewHTTPChecker()
+	ctx := context.Background()
+
+	result := checker.CheckRequest(ctx, server.URL, 5*time.Second, "", nil, &entities.HTTPConfig{
+		Assertions: []entities.HTTPAssertion{
+			{Source: "header", Property: "Content-Type", Operator: "equals", Value: "application/json"},
+			{Source: "json", Property: "status", Operator: "equals", Value: "ok"},
+		},
+	})
+
+	if result.Success {
+		t.Errorf("Expected failure for degraded status, got success")
+	}
+	if result.StatusCode != http.StatusOK {
+		t.Errorf("Expected status code 200, got %d", result.StatusCode)
+	}
+	expected := `assertion failed: json "status" equals "ok" (got "degraded")`
+	if result.Error == nil || result.Error.Error() != expected {
+		t.Errorf("Expected error %q, got: %v", expected, result.Error)
+	}
+}
+
+func TestHTTPChecker_WithConnection(t *testing.T) {
+	var serverName, host string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{
+		MaxVersion: tls.VersionTLS12,
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			serverName = hello.ServerName
+			return nil, nil
+		},
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	port := server.Listener.Addr().(*net.TCPAddr).Port
+	pinnedURL := fmt.Sprintf("https://monitor.invalid:%d/", port)
+	ctx := context.Background()
+
+	check := func(t *testing.T, rawURL string, cfg *entities.ConnectionConfig) HTTPCheckResult {
+		t.Helper()
+		parsedURL, _ := url.Parse(rawURL)
+		checker, err := NewHTTPChecker().WithConnection(ConnectionOptions{Config: cfg, PinnedHost: parsedURL.Hostname()})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return checker.CheckURL(ctx, rawURL, 5*time.Second, "")
+	}
+
+	t.Run("untrusted certificate", func(t *testing.T) {
+		result := check(t, server.URL, &entities.ConnectionConfig{})
+		if result.Success || result.Error == nil {
+			t.Errorf("Expected the self-signed certificate to be rejected")
+		}
+	})
+
+	t.Run("insecure skip verify", func(t *testing.T) {
+		result := check(t, server.URL, &entities.ConnectionConfig{InsecureSkipVerify: true})
+		if !result.Success {
+			t.Errorf("Expected success, got error: %v", result.Error)
+		}
+	})
+
+	t.Run("resolve to", func(t *testing.T) {
+		result := check(t, pinnedURL, &entities.ConnectionConfig{InsecureSkipVerify: true, ResolveTo: "127.0.0.1"})
+		if !result.Success {
+			t.Fatalf("Expected success, got error: %v", result.Error)
+		}
+		if serverName != "monitor.invalid" || host != fmt.Sprintf("monitor.invalid:%d", port) {
+			t.Errorf("Expected the URL host as SNI and Host, got %q and %q", serverName, host)
+		}
+	})
+
+	t.Run("server name", func(t *testing.T) {
+		result := check(t, pinnedURL, &entities.ConnectionConfig{InsecureSkipVerify: true, ResolveTo: "127.0.0.1", ServerName: "example.com"})
+		if !result.Success {
+			t.Fatalf("Expected success, got error: %v", result.Error)
+		}
+		if serverName != "example.com" || host != fmt.Sprintf("example.com:%d", port) {
+			t.Errorf("Expected example.com as SNI and Host, got %q and %q", serverName, host)
+		}
+		if result.Timing.TLSHandshake <= 0 {
+			t.Errorf("Expected positive TLS handshake time, got %v", result.Timing.TLSHandshake)
+		}
+	})
+
+	t.Run("minimum TLS version", func(t *testing.T) {
+		result := check(t, server.URL, &entities.ConnectionConfig{InsecureSkipVerify: true, MinTLSVersion: "1.3"})
+		if result.Success || result.Error == nil {
+			t.Errorf("Expected a TLS 1.2 server to be rejected")
+		}
+	})
+
+	t.Run("IP family", func(t *testing.T) {
+		if result := check(t, server.URL, &entities.ConnectionConfig{InsecureSkipVerify: true, IPFamily: "ipv4"}); !result.Success {
+			t.Errorf("Expected success over IPv4, got error: %v", result.Error)
+		}
+		if result := check(t, server.URL, &entities.ConnectionConfig{InsecureSkipVerify: true, IPFamily: "ipv6"}); result.Success {
+			t.Errorf("Expected an IPv4 address to be unreachable over IPv6")
+		}
+	})
+
+	t.Run("invalid settings", func(t *testing.T) {
+		for _, cfg := range []*entities.ConnectionConfig{
+			{MinTLSVersion: "1.4"},
+			{IPFamily: "ipv5"},
+			{ResolveTo: "not-an-ip"},
+		} {
+			if _, err := NewHTTPChecker().WithConnection(ConnectionOptions{Config: cfg, PinnedHost: "example.com"}); err == nil {
+				t.Errorf("Expected an error for %+v", cfg)
+			}
+		}
+	})
+}
+
+func TestHTTPChecker_WithConnection_ClientCertificate(t *testing.T) {
+	clientCertificate, clientCAs := newTestClientCertificate(t)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	connection := &entities.ConnectionConfig{InsecureSkipVerify: true}
+
+	checker, err := NewHTTPChecker().WithConnection(ConnectionOptions{Config: connection, PinnedHost: "127.0.0.1"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result := checker.CheckURL(context.Background(), server.URL, 5*time.Second, ""); result.Success {
+		t.Errorf("Expected the server to reject a request without client certificate")
+	}
+
+	checker, err = NewHTTPChecker().WithConnection(ConnectionOptions{Config: connection, PinnedHost: "127.0.0.1", ClientCertificate: clientCertificate})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result := checker.CheckURL(context.Background(), server.URL, 5*time.Second, ""); !result.Success {
+		t.Errorf("Expected success with client certificate, got error: %v", result.Error)
+	}
+}
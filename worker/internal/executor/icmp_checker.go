@@ -2,18 +2,40 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os/exec"
-	"regexp"
-	"strconv"
+	"math"
+	"math/rand/v2"
+	"net"
 	"time"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+const (
+	// DefaultICMPProbeCount is the number of echo requests sent per check when not configured
+	DefaultICMPProbeCount = 3
+	// MaxICMPProbeCount limits the number of echo requests sent per check
+	MaxICMPProbeCount = 20
+
+	// icmpProbeInterval is the minimum delay between two consecutive echo requests
+	icmpProbeInterval = 100 * time.Millisecond
 )
 
 // ICMPCheckResult represents the result of an ICMP health check
 type ICMPCheckResult struct {
-	ResponseTime time.Duration
-	Error        error
-	Success      bool
+	ResponseTime    time.Duration // average round-trip time of the received replies
+	PacketsSent     int
+	PacketsReceived int
+	PacketLoss      float64 // percentage of probes without a reply (0-100)
+	MinRTT          time.Duration
+	AvgRTT          time.Duration
+	MaxRTT          time.Duration
+	Jitter          time.Duration // mean deviation between consecutive round-trip times
+	Error           error
+	Success         bool
 }
 
 // ICMPChecker performs ICMP health checks (ping)
@@ -24,53 +46,251 @@ func NewICMPChecker() *ICMPChecker {
 	return &ICMPChecker{}
 }
 
-// Check performs a ping check on the given host
-func (c *ICMPChecker) Check(ctx context.Context, host string, timeout time.Duration) ICMPCheckResult {
-	// Calculate timeout in seconds for ping command
-	timeoutSec := fmt.Sprintf("%.0f", timeout.Seconds())
-	if timeoutSec == "0" {
-		timeoutSec = "1"
+// Check sends count echo requests to the given host and reports round-trip statistics
+// It prefers an unprivileged datagram ICMP socket and falls back to a raw socket when
+// datagram sockets are not allowed (see net.ipv4.ping_group_range)
+// The check succeeds when at least one reply is received; partial loss is reported in PacketLoss
+func (c *ICMPChecker) Check(ctx context.Context, host string, count int, timeout time.Duration) ICMPCheckResult {
+	if count <= 0 {
+		count = DefaultICMPProbeCount
+	}
+	if count > MaxICMPProbeCount {
+		count = MaxICMPProbeCount
 	}
 
-	// Prepare ping command (Linux syntax)
-	// -c 1: send 1 packet
-	// -W: timeout in seconds
-	cmd := exec.CommandContext(ctx, "ping", "-c", "1", "-W", timeoutSec, host)
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	startTime := time.Now()
-	output, err := cmd.CombinedOutput()
-	execDuration := time.Since(startTime)
+	ip, err := resolveICMPTarget(checkCtx, host)
+	if err != nil {
+		return ICMPCheckResult{Error: err, Success: false}
+	}
 
+	conn, privileged, err := listenICMP(ip)
 	if err != nil {
-		return ICMPCheckResult{
-			ResponseTime: 0,
-			Error:        fmt.Errorf("ping failed: %w, output: %s", err, string(output)),
-			Success:      false,
+		return ICMPCheckResult{Error: err, Success: false}
+	}
+	defer conn.Close()
+
+	// Give every probe an equal share of the timeout
+	probeTimeout := timeout / time.Duration(count)
+
+	var dst net.Addr = &net.UDPAddr{IP: ip}
+	if privileged {
+		dst = &net.IPAddr{IP: ip}
+	}
+
+	// Raw sockets receive every echo reply of the host, a random ID per check keeps concurrent
+	// checks of the same host from taking each other's replies
+	id := rand.IntN(0x10000)
+	var rtts []time.Duration
+	var lastErr error
+	sent := 0
+
+	for seq := 1; seq <= count; seq++ {
+		if checkCtx.Err() != nil {
+			break
 		}
+
+		probeStart := time.Now()
+		sent++
+		rtt, err := sendICMPProbe(checkCtx, conn, privileged, dst, ip, id, seq, probeTimeout)
+		if err != nil {
+			lastErr = err
+		} else {
+			rtts = append(rtts, rtt)
+		}
+
+		// Space probes out so a fast reply does not turn the check into a flood
+		if seq < count {
+			if wait := icmpProbeInterval - time.Since(probeStart); wait > 0 {
+				select {
+				case <-checkCtx.Done():
+				case <-time.After(wait):
+				}
+			}
+		}
+	}
+
+	result := summarizeICMPProbes(sent, rtts)
+	if result.PacketsReceived == 0 {
+		if lastErr == nil {
+			lastErr = checkCtx.Err()
+		}
+		result.Error = fmt.Errorf("ping failed: 100%% packet loss (%d sent): %v", sent, lastErr)
+		return result
+	}
+
+	result.Success = true
+	return result
+}
+
+// resolveICMPTarget resolves host to a single IP address, preferring IPv4
+func resolveICMPTarget(ctx context.Context, host string) (net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip, nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve host: %w", err)
+	}
+	for _, addr := range addrs {
+		if addr.IP.To4() != nil {
+			return addr.IP, nil
+		}
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("failed to resolve host: no addresses found for %s", host)
+	}
+
+	return addrs[0].IP, nil
+}
+
+// listenICMP opens an ICMP socket for the address family of ip
+// It returns whether the socket is a privileged raw socket
+func listenICMP(ip net.IP) (*icmp.PacketConn, bool, error) {
+	network, rawNetwork, address := "udp4", "ip4:icmp", "0.0.0.0"
+	if ip.To4() == nil {
+		network, rawNetwork, address = "udp6", "ip6:ipv6-icmp", "::"
+	}
+
+	conn, err := icmp.ListenPacket(network, address)
+	if err == nil {
+		return conn, false, nil
 	}
 
-	// Parse RTT from output
-	// Output format example: "... time=4.96 ms"
-	re := regexp.MustCompile(`time=([\d\.]+) ms`)
-	matches := re.FindStringSubmatch(string(output))
+	rawConn, rawErr := icmp.ListenPacket(rawNetwork, address)
+	if rawErr == nil {
+		return rawConn, true, nil
+	}
+
+	return nil, false, fmt.Errorf("failed to open ICMP socket: %w (raw socket: %v); allow unprivileged ping via net.ipv4.ping_group_range", err, rawErr)
+}
+
+// sendICMPProbe sends a single echo request and waits for the matching reply
+func sendICMPProbe(ctx context.Context, conn *icmp.PacketConn, privileged bool, dst net.Addr, ip net.IP, id, seq int, timeout time.Duration) (time.Duration, error) {
+	var requestType icmp.Type = ipv4.ICMPTypeEcho
+	var replyType icmp.Type = ipv4.ICMPTypeEchoReply
+	protocol := 1 // ICMP for IPv4
+	if ip.To4() == nil {
+		requestType = ipv6.ICMPTypeEchoRequest
+		replyType = ipv6.ICMPTypeEchoReply
+		protocol = 58 // ICMPv6
+	}
+
+	msg := icmp.Message{
+		Type: requestType,
+		Code: 0,
+		Body: &icmp.Echo{ID: id, Seq: seq, Data: []byte("V-Insight-Monitor")},
+	}
+	packet, err := msg.Marshal(nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to build echo request: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return 0, err
+	}
+
+	startTime := time.Now()
+	if _, err := conn.WriteTo(packet, dst); err != nil {
+		return 0, fmt.Errorf("failed to send echo request: %w", err)
+	}
+
+	buf := make([]byte, 1500)
+	for {
+		n, peer, err := conn.ReadFrom(buf)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return 0, errors.New("request timed out")
+			}
+			return 0, fmt.Errorf("failed to read echo reply: %w", err)
+		}
+		rtt := time.Since(startTime)
+
+		if !sameICMPPeer(peer, ip) {
+			continue
+		}
 
-	var responseTime time.Duration
-	if len(matches) > 1 {
-		ms, err := strconv.ParseFloat(matches[1], 64)
-		if err == nil {
-			responseTime = time.Duration(ms * float64(time.Millisecond))
+		reply, err := icmp.ParseMessage(protocol, buf[:n])
+		if err != nil || reply.Type != replyType {
+			continue
 		}
+
+		echo, ok := reply.Body.(*icmp.Echo)
+		if !ok || !matchesEchoRequest(echo, privileged, id, seq) {
+			continue
+		}
+
+		return rtt, nil
+	}
+}
+
+// matchesEchoRequest reports whether echo answers the request with id and seq
+// Datagram sockets rewrite the echo ID to the local port and only receive their own replies, so
+// only the sequence is matched; raw sockets receive the replies of every check on the host
+func matchesEchoRequest(echo *icmp.Echo, privileged bool, id, seq int) bool {
+	if privileged && echo.ID != id {
+		return false
+	}
+	return echo.Seq == seq
+}
+
+// sameICMPPeer reports whether the reply came from the probed address
+func sameICMPPeer(peer net.Addr, ip net.IP) bool {
+	switch addr := peer.(type) {
+	case *net.UDPAddr:
+		return addr.IP.Equal(ip)
+	case *net.IPAddr:
+		return addr.IP.Equal(ip)
 	}
+	return false
+}
 
-	// Fallback to execution duration if parsing fails or RTT is 0
-	// (though execution duration includes process startup overhead)
-	if responseTime == 0 {
-		responseTime = execDuration
+// summarizeICMPProbes computes loss and round-trip statistics for a set of probes
+func summarizeICMPProbes(sent int, rtts []time.Duration) ICMPCheckResult {
+	result := ICMPCheckResult{
+		PacketsSent:     sent,
+		PacketsReceived: len(rtts),
+	}
+	if sent > 0 {
+		result.PacketLoss = math.Round(float64(sent-len(rtts))/float64(sent)*10000) / 100
+	}
+	if len(rtts) == 0 {
+		return result
 	}
 
-	return ICMPCheckResult{
-		ResponseTime: responseTime,
-		Error:        nil,
-		Success:      true,
+	var total, totalDiff time.Duration
+	result.MinRTT = rtts[0]
+	result.MaxRTT = rtts[0]
+	for i, rtt := range rtts {
+		total += rtt
+		if rtt < result.MinRTT {
+			result.MinRTT = rtt
+		}
+		if rtt > result.MaxRTT {
+			result.MaxRTT = rtt
+		}
+		if i > 0 {
+			diff := rtt - rtts[i-1]
+			if diff < 0 {
+				diff = -diff
+			}
+			totalDiff += diff
+		}
+	}
+
+	result.AvgRTT = total / time.Duration(len(rtts))
+	if len(rtts) > 1 {
+		result.Jitter = totalDiff / time.Duration(len(rtts)-1)
 	}
+	result.ResponseTime = result.AvgRTT
+
+	return result
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 	"time"
+
+	"golang.org/x/net/icmp"
 )
 
 func TestICMPChecker_Check_Success(t *testing.T) {
@@ -11,7 +13,7 @@ func TestICMPChecker_Check_Success(t *testing.T) {
 	ctx := context.Background()
 
 	// Ping localhost
-	result := checker.Check(ctx, "127.0.0.1", 2*time.Second)
+	result := checker.Check(ctx, "127.0.0.1", 3, 2*time.Second)
 
 	if !result.Success {
 		t.Errorf("Expected success, got failure: %v", result.Error)
@@ -22,6 +24,15 @@ func TestICMPChecker_Check_Success(t *testing.T) {
 	if result.ResponseTime <= 0 {
 		t.Errorf("Expected positive response time, got %v", result.ResponseTime)
 	}
+	if result.PacketsSent != 3 || result.PacketsReceived != 3 {
+		t.Errorf("Expected 3/3 packets, got %d/%d", result.PacketsReceived, result.PacketsSent)
+	}
+	if result.PacketLoss != 0 {
+		t.Errorf("Expected 0%% packet loss, got %v", result.PacketLoss)
+	}
+	if result.MinRTT > result.AvgRTT || result.AvgRTT > result.MaxRTT {
+		t.Errorf("Expected min <= avg <= max, got %v/%v/%v", result.MinRTT, result.AvgRTT, result.MaxRTT)
+	}
 }
 
 func TestICMPChecker_Check_Failure(t *testing.T) {
@@ -30,7 +41,7 @@ func TestICMPChecker_Check_Failure(t *testing.T) {
 
 	// Ping non-existent host (should fail or timeout)
 	// Using a reserved IP that is unlikely to respond or route
-	result := checker.Check(ctx, "203.0.113.1", 1*time.Second)
+	result := checker.Check(ctx, "203.0.113.1", 2, 1*time.Second)
 
 	if result.Success {
 		t.Errorf("Expected failure for unreachable host, got success")
@@ -38,4 +49,61 @@ func TestICMPChecker_Check_Failure(t *testing.T) {
 	if result.Error == nil {
 		t.Errorf("Expected error for unreachable host, got nil")
 	}
+	if result.PacketLoss != 100 && result.PacketsSent > 0 {
+		t.Errorf("Expected 100%% packet loss, got %v", result.PacketLoss)
+	}
+}
+
+func TestSummarizeICMPProbes(t *testing.T) {
+	rtts := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 15 * time.Millisecond}
+	result := summarizeICMPProbes(4, rtts)
+
+	if result.PacketsSent != 4 || result.PacketsReceived != 3 {
+		t.Errorf("Expected 3/4 packets, got %d/%d", result.PacketsReceived, result.PacketsSent)
+	}
+	if result.PacketLoss != 25 {
+		t.Errorf("Expected 25%% packet loss, got %v", result.PacketLoss)
+	}
+	if result.MinRTT != 10*time.Millisecond || result.MaxRTT != 20*time.Millisecond {
+		t.Errorf("Expected min 10ms and max 20ms, got %v and %v", result.MinRTT, result.MaxRTT)
+	}
+	if result.AvgRTT != 15*time.Millisecond {
+		t.Errorf("Expected avg 15ms, got %v", result.AvgRTT)
+	}
+	// |20-10| and |15-20| averaged
+	if result.Jitter != 7500*time.Microsecond {
+		t.Errorf("Expected jitter 7.5ms, got %v", result.Jitter)
+	}
+}
+
+func TestSummarizeICMPProbes_AllLost(t *testing.T) {
+	result := summarizeICMPProbes(3, nil)
+
+	if result.PacketLoss != 100 {
+		t.Errorf("Expected 100%% packet loss, got %v", result.PacketLoss)
+	}
+	if result.AvgRTT != 0 || result.ResponseTime != 0 {
+		t.Errorf("Expected zero RTT without replies, got %v", result.AvgRTT)
+	}
+}
+
+func TestMatchesEchoRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		echo       icmp.Echo
+		privileged bool
+		want       bool
+	}{
+		{"raw socket, own reply", icmp.Echo{ID: 4242, Seq: 2}, true, true},
+		{"raw socket, reply of another check", icmp.Echo{ID: 1717, Seq: 2}, true, false},
+		{"raw socket, other sequence", icmp.Echo{ID: 4242, Seq: 1}, true, false},
+		{"datagram socket, ID rewritten by the kernel", icmp.Echo{ID: 51234, Seq: 2}, false, true},
+		{"datagram socket, other sequence", icmp.Echo{ID: 4242, Seq: 3}, false, false},
+	}
+
+	for _, tt := range tests {
+		if got := matchesEchoRequest(&tt.echo, tt.privileged, 4242, 2); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
 }
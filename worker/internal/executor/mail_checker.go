@@ -0,0 +1,376 @@
+package executor
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+)
+
+// maxMailBannerSize limits the greeting stored in check details
+const maxMailBannerSize = 512
+
+// MailCheckResult represents the result of a mail server check
+type MailCheckResult struct {
+	ResponseTime  time.Duration
+	Banner        string               // greeting of the server
+	TLS           *tls.ConnectionState // encrypted session, nil when it stayed in plaintext
+	Authenticated bool
+	Error         error
+	Success       bool
+}
+
+// MailChecker checks SMTP, IMAP and POP3 servers
+type MailChecker struct {
+	rootCAs *x509.CertPool // trusted roots for TLS sessions, system roots when nil
+}
+
+// NewMailChecker creates a new mail server checker
+func NewMailChecker() *MailChecker {
+	return &MailChecker{}
+}
+
+// mailConn is a mail protocol connection, replaced by a TLS connection after STARTTLS
+type mailConn struct {
+	conn         net.Conn
+	reader       *bufio.Reader
+	capabilities []string // SMTP extensions or IMAP capabilities announced by the server
+	tag          int      // last IMAP command tag
+}
+
+// mailProtocol is the exchange of one mail protocol
+type mailProtocol struct {
+	greeting func(c *mailConn) (string, error)
+	hello    func(c *mailConn) error // reads the capabilities, again after STARTTLS; nil when the protocol has none
+	startTLS func(c *mailConn) error
+	login    func(c *mailConn, username, password string) error
+	quit     func(c *mailConn) error
+}
+
+// mailProtocols holds the exchange of each mail monitor type
+var mailProtocols = map[string]mailProtocol{
+	"smtp": {
+		greeting: smtpGreeting,
+		hello:    smtpHello,
+		startTLS: func(c *mailConn) error { return smtpCommand(c, "STARTTLS", "220") },
+		login:    smtpLogin,
+		quit:     func(c *mailConn) error { return smtpCommand(c, "QUIT", "221") },
+	},
+	"imap": {
+		greeting: imapGreeting,
+		hello:    imapHello,
+		startTLS: func(c *mailConn) error { return imapCommand(c, "STARTTLS") },
+		login: func(c *mailConn, username, password string) error {
+			return imapCommand(c, "LOGIN "+imapQuote(username)+" "+imapQuote(password))
+		},
+		quit: func(c *mailConn) error { return imapCommand(c, "LOGOUT") },
+	},
+	"pop3": {
+		greeting: pop3Greeting,
+		startTLS: func(c *mailConn) error { return pop3Command(c, "STLS") },
+		login: func(c *mailConn, username, password string) error {
+			if err := pop3Command(c, "USER "+username); err != nil {
+				return err
+			}
+			return pop3Command(c, "PASS "+password)
+		},
+		quit: func(c *mailConn) error { return pop3Command(c, "QUIT") },
+	},
+}
+
+// Check runs a session with the SMTP, IMAP or POP3 server at address (host:port): it reads the
+// greeting, switches to TLS when cfg asks for it, logs in when cfg has credentials and ends the
+// session. Every reply must have the expected status, so that a server that accepts connections
+// but no longer handles mail is reported down. The certificate must verify for the host unless
+// cfg.InsecureSkipVerify is set; the session is returned either way so that it can be recorded.
+func (c *MailChecker) Check(ctx context.Context, protocol, address string, cfg entities.MailConfig, timeout time.Duration) MailCheckResult {
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	proto, ok := mailProtocols[protocol]
+	if !ok {
+		return MailCheckResult{Error: fmt.Errorf("unsupported mail protocol %q", protocol), Success: false}
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return MailCheckResult{Error: fmt.Errorf("invalid address: %w", err), Success: false}
+	}
+
+	startTime := time.Now()
+
+	conn, err := (&net.Dialer{}).DialContext(checkCtx, "tcp", address)
+	if err != nil {
+		return MailCheckResult{
+			ResponseTime: time.Since(startTime),
+			Error:        fmt.Errorf("TCP connection failed: %w", err),
+			Success:      false,
+		}
+	}
+	defer conn.Close()
+
+	// Unblock reads and writes as soon as the check is cancelled or times out, also after STARTTLS
+	stop := context.AfterFunc(checkCtx, func() {
+		conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	result := MailCheckResult{}
+	session := &mailConn{conn: conn, reader: bufio.NewReader(conn)}
+	fail := func(err error) MailCheckResult {
+		if checkCtx.Err() != nil {
+			err = fmt.Errorf("timed out: %w", err)
+		}
+		result.ResponseTime = time.Since(startTime)
+		result.Error = err
+		return result
+	}
+
+	// The certificate is verified after the handshake so that an invalid one is still recorded
+	tlsConfig := &tls.Config{ServerName: host, InsecureSkipVerify: true}
+	startTLS := func() error {
+		tlsConn := tls.Client(session.conn, tlsConfig)
+		if err := tlsConn.HandshakeContext(checkCtx); err != nil {
+			return fmt.Errorf("TLS handshake failed: %w", err)
+		}
+		state := tlsConn.ConnectionState()
+		result.TLS = &state
+		session.conn = tlsConn
+		session.reader = bufio.NewReader(tlsConn)
+
+		if !cfg.InsecureSkipVerify {
+			if err := verifyPeerCertificates(state, host, c.rootCAs); err != nil {
+				return fmt.Errorf("certificate verification failed: %w", err)
+			}
+		}
+		return nil
+	}
+
+	if cfg.TLS == "implicit" {
+		if err := startTLS(); err != nil {
+			return fail(err)
+		}
+	}
+
+	banner, err := proto.greeting(session)
+	if err != nil {
+		return fail(fmt.Errorf("invalid greeting: %w", err))
+	}
+	if len(banner) > maxMailBannerSize {
+		banner = banner[:maxMailBannerSize]
+	}
+	result.Banner = banner
+	if cfg.ExpectBanner != "" && !strings.Contains(banner, cfg.ExpectBanner) {
+		return fail(fmt.Errorf("banner %q does not contain %q", truncateForError(banner), cfg.ExpectBanner))
+	}
+
+	if proto.hello != nil {
+		if err := proto.hello(session); err != nil {
+			return fail(err)
+		}
+	}
+
+	if cfg.TLS == "starttls" {
+		if err := proto.startTLS(session); err != nil {
+			return fail(err)
+		}
+		if err := startTLS(); err != nil {
+			return fail(err)
+		}
+		// Capabilities announced before TLS must be discarded (RFC 3207, RFC 3501)
+		if proto.hello != nil {
+			if err := proto.hello(session); err != nil {
+				return fail(err)
+			}
+		}
+	}
+
+	if cfg.Username != "" {
+		if err := proto.login(session, cfg.Username, cfg.Password); err != nil {
+			return fail(fmt.Errorf("authentication failed: %w", err))
+		}
+		result.Authenticated = true
+	}
+
+	if err := proto.quit(session); err != nil {
+		return fail(err)
+	}
+
+	result.ResponseTime = time.Since(startTime)
+	result.Success = true
+	return result
+}
+
+// smtpGreeting reads the 220 greeting of an SMTP server and returns its first line
+func smtpGreeting(c *mailConn) (string, error) {
+	lines, err := readSMTPLines(c.reader, "220")
+	if err != nil {
+		return "", err
+	}
+	return lines[0], nil
+}
+
+// smtpHello sends EHLO and keeps the announced extensions
+func smtpHello(c *mailConn) error {
+	if _, err := fmt.Fprintf(c.conn, "EHLO %s\r\n", smtpClientName()); err != nil {
+		return err
+	}
+	lines, err := readSMTPLines(c.reader, "250")
+	if err != nil {
+		return fmt.Errorf("EHLO: %w", err)
+	}
+	// The first line is the server name
+	c.capabilities = lines[1:]
+	return nil
+}
+
+// smtpCommand sends an SMTP command and checks the status code of the reply
+func smtpCommand(c *mailConn, command, code string) error {
+	if _, err := io.WriteString(c.conn, command+"\r\n"); err != nil {
+		return err
+	}
+	if err := readSMTPReply(c.reader, code); err != nil {
+		return fmt.Errorf("%s: %w", command, err)
+	}
+	return nil
+}
+
+// smtpLogin authenticates with AUTH PLAIN, or AUTH LOGIN when the server only offers that (RFC 4954)
+func smtpLogin(c *mailConn, username, password string) error {
+	var mechanisms []string
+	for _, capability := range c.capabilities {
+		if fields := strings.Fields(strings.ToUpper(capability)); len(fields) > 0 && fields[0] == "AUTH" {
+			mechanisms = fields[1:]
+		}
+	}
+	if mechanisms == nil {
+		return errors.New("server does not offer AUTH")
+	}
+
+	encode := base64.StdEncoding.EncodeToString
+	if !slices.Contains(mechanisms, "PLAIN") && slices.Contains(mechanisms, "LOGIN") {
+		if err := smtpCommand(c, "AUTH LOGIN", "334"); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(c.conn, encode([]byte(username))+"\r\n"); err != nil {
+			return err
+		}
+		if err := readSMTPReply(c.reader, "334"); err != nil {
+			return fmt.Errorf("AUTH LOGIN: %w", err)
+		}
+		if _, err := io.WriteString(c.conn, encode([]byte(password))+"\r\n"); err != nil {
+			return err
+		}
+	} else {
+		if _, err := io.WriteString(c.conn, "AUTH PLAIN "+encode([]byte("\x00"+username+"\x00"+password))+"\r\n"); err != nil {
+			return err
+		}
+	}
+	if err := readSMTPReply(c.reader, "235"); err != nil {
+		return fmt.Errorf("AUTH: %w", err)
+	}
+	return nil
+}
+
+// readMailLine reads a CRLF-terminated line of a mail protocol
+func readMailLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// imapGreeting reads the untagged greeting of an IMAP server (RFC 3501), which must not be BYE
+func imapGreeting(c *mailConn) (string, error) {
+	line, err := readMailLine(c.reader)
+	if err != nil {
+		return "", err
+	}
+	for _, status := range []string{"* OK", "* PREAUTH"} {
+		if strings.HasPrefix(line, status) {
+			return strings.TrimSpace(strings.TrimPrefix(line, status)), nil
+		}
+	}
+	return "", fmt.Errorf("unexpected reply %q", truncateForError(line))
+}
+
+// imapHello asks for the capabilities of an IMAP server and keeps them
+func imapHello(c *mailConn) error {
+	c.capabilities = nil
+	return imapCommand(c, "CAPABILITY")
+}
+
+// imapCommand sends a tagged IMAP command and reads the responses until its tagged OK
+// Untagged CAPABILITY responses are kept
+func imapCommand(c *mailConn, command string) error {
+	c.tag++
+	tag := fmt.Sprintf("a%d", c.tag)
+	if _, err := fmt.Fprintf(c.conn, "%s %s\r\n", tag, command); err != nil {
+		return err
+	}
+
+	// Only the verb, the arguments may be credentials
+	verb, _, _ := strings.Cut(command, " ")
+	for {
+		line, err := readMailLine(c.reader)
+		if err != nil {
+			return fmt.Errorf("%s: %w", verb, err)
+		}
+		if capabilities, ok := strings.CutPrefix(line, "* CAPABILITY "); ok {
+			c.capabilities = strings.Fields(capabilities)
+			continue
+		}
+		status, found := strings.CutPrefix(line, tag+" ")
+		if !found {
+			continue
+		}
+		if !strings.HasPrefix(status, "OK") {
+			return fmt.Errorf("%s: unexpected reply %q", verb, truncateForError(status))
+		}
+		return nil
+	}
+}
+
+// imapQuote returns s as an IMAP quoted string
+func imapQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// pop3Greeting reads the +OK greeting of a POP3 server (RFC 1939)
+func pop3Greeting(c *mailConn) (string, error) {
+	line, err := readMailLine(c.reader)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(line, "+OK") {
+		return "", fmt.Errorf("unexpected reply %q", truncateForError(line))
+	}
+	return strings.TrimSpace(strings.TrimPrefix(line, "+OK")), nil
+}
+
+// pop3Command sends a POP3 command and checks that the server answers +OK
+func pop3Command(c *mailConn, command string) error {
+	if _, err := io.WriteString(c.conn, command+"\r\n"); err != nil {
+		return err
+	}
+
+	verb, _, _ := strings.Cut(command, " ")
+	line, err := readMailLine(c.reader)
+	if err != nil {
+		return fmt.Errorf("%s: %w", verb, err)
+	}
+	if !strings.HasPrefix(line, "+OK") {
+		return fmt.Errorf("%s: unexpected reply %q", verb, truncateForError(line))
+	}
+	return nil
+}
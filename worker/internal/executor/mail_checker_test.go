@@ -0,0 +1,304 @@
+package executor
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+)
+
+// fakeMailServer configures startFakeMailServer
+type fakeMailServer struct {
+	protocol    string
+	greeting    string           // replaces the protocol's greeting when set
+	certificate *testCertificate // offers STARTTLS when set
+	implicitTLS bool             // serves TLS from the first byte
+	username    string
+	password    string
+	smtpAuth    string // mechanisms announced by the SMTP server, "PLAIN LOGIN" when empty
+	hang        bool   // greets but never answers commands, like a relay whose queue died
+}
+
+// startFakeMailServer serves a minimal SMTP, IMAP or POP3 session and returns its address
+func startFakeMailServer(t *testing.T, server fakeMailServer) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	var tlsConfig *tls.Config
+	if server.certificate != nil {
+		tlsConfig = &tls.Config{Certificates: []tls.Certificate{{
+			Certificate: [][]byte{server.certificate.cert.Raw},
+			PrivateKey:  server.certificate.key,
+		}}}
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				if server.implicitTLS {
+					conn = tls.Server(conn, tlsConfig)
+				}
+				serveFakeMail(conn, server, tlsConfig)
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+// serveFakeMail runs one session of the fake server on conn
+func serveFakeMail(conn net.Conn, server fakeMailServer, tlsConfig *tls.Config) {
+	reader := bufio.NewReader(conn)
+	write := func(format string, args ...any) { fmt.Fprintf(conn, format, args...) }
+	readLine := func() (string, bool) {
+		line, err := reader.ReadString('\n')
+		return strings.TrimRight(line, "\r\n"), err == nil
+	}
+	upgrade := func() {
+		tlsConn := tls.Server(conn, tlsConfig)
+		conn, reader = tlsConn, bufio.NewReader(tlsConn)
+	}
+	tlsActive := server.implicitTLS
+
+	greetings := map[string]string{"smtp": "220 mail.test ESMTP ready", "imap": "* OK IMAP4rev1 mail.test ready", "pop3": "+OK POP3 mail.test ready"}
+	greeting := server.greeting
+	if greeting == "" {
+		greeting = greetings[server.protocol]
+	}
+	write("%s\r\n", greeting)
+	if server.hang {
+		readLine()
+		time.Sleep(time.Second)
+		return
+	}
+
+	for {
+		line, ok := readLine()
+		if !ok {
+			return
+		}
+
+		switch server.protocol {
+		case "smtp":
+			verb := strings.ToUpper(strings.Fields(line + " ")[0])
+			switch verb {
+			case "EHLO":
+				write("250-mail.test\r\n")
+				if tlsConfig != nil && !tlsActive {
+					write("250-STARTTLS\r\n")
+				}
+				auth := server.smtpAuth
+				if auth == "" {
+					auth = "PLAIN LOGIN"
+				}
+				if tlsActive {
+					write("250-AUTH %s\r\n", auth)
+				}
+				write("250 8BITMIME\r\n")
+			case "STARTTLS":
+				if tlsConfig == nil {
+					write("454 4.7.0 TLS not available\r\n")
+					continue
+				}
+				write("220 Go ahead\r\n")
+				upgrade()
+				tlsActive = true
+			case "AUTH":
+				args := strings.Fields(line)
+				var username, password string
+				if len(args) == 3 && args[1] == "PLAIN" {
+					decoded, _ := base64.StdEncoding.DecodeString(args[2])
+					parts := strings.Split(string(decoded), "\x00")
+					if len(parts) == 3 {
+						username, password = parts[1], parts[2]
+					}
+				} else if len(args) == 2 && args[1] == "LOGIN" {
+					write("334 VXNlcm5hbWU6\r\n")
+					encoded, _ := readLine()
+					decoded, _ := base64.StdEncoding.DecodeString(encoded)
+					username = string(decoded)
+					write("334 UGFzc3dvcmQ6\r\n")
+					encoded, _ = readLine()
+					decoded, _ = base64.StdEncoding.DecodeString(encoded)
+					password = string(decoded)
+				}
+				if username == server.username && password == server.password {
+					write("235 2.7.0 Authentication successful\r\n")
+				} else {
+					write("535 5.7.8 Authentication credentials invalid\r\n")
+				}
+			case "QUIT":
+				write("221 Bye\r\n")
+				return
+			default:
+				write("502 Command not implemented\r\n")
+			}
+
+		case "imap":
+			tag, command, _ := strings.Cut(line, " ")
+			verb, args, _ := strings.Cut(command, " ")
+			switch strings.ToUpper(verb) {
+			case "CAPABILITY":
+				capabilities := "IMAP4rev1"
+				if tlsConfig != nil && !tlsActive {
+					capabilities += " STARTTLS LOGINDISABLED"
+				}
+				write("* CAPABILITY %s\r\n%s OK CAPABILITY completed\r\n", capabilities, tag)
+			case "STARTTLS":
+				write("%s OK Begin TLS negotiation now\r\n", tag)
+				upgrade()
+				tlsActive = true
+			case "LOGIN":
+				if args == imapQuote(server.username)+" "+imapQuote(server.password) {
+					write("%s OK LOGIN completed\r\n", tag)
+				} else {
+					write("%s NO [AUTHENTICATIONFAILED] Invalid credentials\r\n", tag)
+				}
+			case "LOGOUT":
+				write("* BYE Logging out\r\n%s OK LOGOUT completed\r\n", tag)
+				return
+			default:
+				write("%s BAD Unknown command\r\n", tag)
+			}
+
+		case "pop3":
+			verb, args, _ := strings.Cut(line, " ")
+			switch strings.ToUpper(verb) {
+			case "STLS":
+				write("+OK Begin TLS negotiation\r\n")
+				upgrade()
+				tlsActive = true
+			case "USER":
+				if args == server.username {
+					write("+OK\r\n")
+				} else {
+					write("-ERR unknown user\r\n")
+				}
+			case "PASS":
+				if args == server.password {
+					write("+OK maildrop ready\r\n")
+				} else {
+					write("-ERR [AUTH] invalid password\r\n")
+				}
+			case "QUIT":
+				write("+OK Bye\r\n")
+				return
+			default:
+				write("-ERR unknown command\r\n")
+			}
+		}
+	}
+}
+
+func TestMailChecker_Check(t *testing.T) {
+	root := newTestCertificate(t, "Test Root", time.Now().Add(365*24*time.Hour), true, generateECDSAKey(t), nil)
+	leaf := newTestCertificate(t, "127.0.0.1", time.Now().Add(90*24*time.Hour), false, generateECDSAKey(t), root)
+	roots := x509.NewCertPool()
+	roots.AddCert(root.cert)
+	checker := &MailChecker{rootCAs: roots}
+
+	login := entities.MailConfig{TLS: "starttls", Username: "monitor@mail.test", Password: `s3cret "quoted"`}
+
+	tests := []struct {
+		name          string
+		server        fakeMailServer
+		cfg           entities.MailConfig
+		success       bool
+		errText       string
+		tls           bool
+		authenticated bool
+	}{
+		{"smtp banner", fakeMailServer{protocol: "smtp"}, entities.MailConfig{ExpectBanner: "ESMTP"}, true, "", false, false},
+		{"smtp unexpected banner", fakeMailServer{protocol: "smtp"}, entities.MailConfig{ExpectBanner: "Postfix"}, false, "does not contain", false, false},
+		{"smtp service unavailable", fakeMailServer{protocol: "smtp", greeting: "554 No SMTP service here"}, entities.MailConfig{}, false, "invalid greeting", false, false},
+		{"smtp starttls and auth plain", fakeMailServer{protocol: "smtp", certificate: leaf, username: login.Username, password: login.Password}, login, true, "", true, true},
+		{"smtp auth login", fakeMailServer{protocol: "smtp", certificate: leaf, username: login.Username, password: login.Password, smtpAuth: "LOGIN"}, login, true, "", true, true},
+		{"smtp wrong password", fakeMailServer{protocol: "smtp", certificate: leaf, username: login.Username, password: "other"}, login, false, "authentication failed", true, false},
+		{"smtp without starttls", fakeMailServer{protocol: "smtp"}, entities.MailConfig{TLS: "starttls"}, false, "STARTTLS", false, false},
+		{"smtp implicit tls", fakeMailServer{protocol: "smtp", certificate: leaf, implicitTLS: true}, entities.MailConfig{TLS: "implicit"}, true, "", true, false},
+		{"smtp hung relay", fakeMailServer{protocol: "smtp", hang: true}, entities.MailConfig{}, false, "timed out", false, false},
+		{"imap starttls and login", fakeMailServer{protocol: "imap", certificate: leaf, username: login.Username, password: login.Password}, login, true, "", true, true},
+		{"imap wrong password", fakeMailServer{protocol: "imap", certificate: leaf, username: login.Username, password: "other"}, login, false, "AUTHENTICATIONFAILED", true, false},
+		{"imap bye", fakeMailServer{protocol: "imap", greeting: "* BYE Too many connections"}, entities.MailConfig{}, false, "invalid greeting", false, false},
+		{"pop3 starttls and login", fakeMailServer{protocol: "pop3", certificate: leaf, username: login.Username, password: login.Password}, login, true, "", true, true},
+		{"pop3 implicit tls", fakeMailServer{protocol: "pop3", certificate: leaf, implicitTLS: true}, entities.MailConfig{TLS: "implicit"}, true, "", true, false},
+		{"pop3 wrong password", fakeMailServer{protocol: "pop3", certificate: leaf, username: login.Username, password: "other"}, login, false, "PASS", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := startFakeMailServer(t, tt.server)
+
+			result := checker.Check(context.Background(), tt.server.protocol, address, tt.cfg, 500*time.Millisecond)
+			if result.Success != tt.success {
+				t.Fatalf("Expected success=%v, got %v (error: %v)", tt.success, result.Success, result.Error)
+			}
+			if tt.errText != "" && (result.Error == nil || !strings.Contains(result.Error.Error(), tt.errText)) {
+				t.Errorf("Expected error containing %q, got %v", tt.errText, result.Error)
+			}
+			if (result.TLS != nil) != tt.tls {
+				t.Errorf("Expected TLS=%v, got %v", tt.tls, result.TLS != nil)
+			}
+			if result.Authenticated != tt.authenticated {
+				t.Errorf("Expected authenticated=%v, got %v", tt.authenticated, result.Authenticated)
+			}
+			if result.Error != nil && strings.Contains(result.Error.Error(), "s3cret") {
+				t.Errorf("Expected the password not to appear in the error: %v", result.Error)
+			}
+		})
+	}
+}
+
+func TestMailChecker_Check_UntrustedCertificate(t *testing.T) {
+	selfSigned := newTestCertificate(t, "127.0.0.1", time.Now().Add(90*24*time.Hour), false, generateECDSAKey(t), nil)
+	address := startFakeMailServer(t, fakeMailServer{protocol: "smtp", certificate: selfSigned, username: "monitor", password: "secret"})
+	checker := NewMailChecker()
+
+	// The credentials are not sent to a server whose certificate does not verify
+	cfg := entities.MailConfig{TLS: "starttls", Username: "monitor", Password: "secret"}
+	result := checker.Check(context.Background(), "smtp", address, cfg, time.Second)
+	if result.Success || result.Error == nil || !strings.Contains(result.Error.Error(), "certificate verification failed") {
+		t.Fatalf("Expected a verification failure, got success=%v error=%v", result.Success, result.Error)
+	}
+	if result.TLS == nil || result.Authenticated {
+		t.Errorf("Expected the TLS session to be returned without logging in")
+	}
+
+	cfg.InsecureSkipVerify = true
+	result = checker.Check(context.Background(), "smtp", address, cfg, time.Second)
+	if !result.Success || !result.Authenticated {
+		t.Errorf("Expected success with insecure_skip_verify, got: %v", result.Error)
+	}
+}
+
+func TestMailChecker_Check_ConnectionRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to reserve a port: %v", err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	result := NewMailChecker().Check(context.Background(), "imap", address, entities.MailConfig{}, time.Second)
+	if result.Success || result.Error == nil || !strings.Contains(result.Error.Error(), "TCP connection failed") {
+		t.Errorf("Expected a connection failure, got success=%v error=%v", result.Success, result.Error)
+	}
+}
@@ -0,0 +1,660 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+	"github.com/eovipmak/v-insight/shared/monitortype"
+)
+
+// defaultPushGracePeriod is tolerated after the check interval before a push monitor is marked down
+const defaultPushGracePeriod = 60 * time.Second
+
+// httpMonitorChecker checks 'http' monitors
+type httpMonitorChecker struct {
+	checker *HTTPChecker
+}
+
+// NewHTTPMonitorChecker creates the checker of 'http' monitors
+func NewHTTPMonitorChecker(checker *HTTPChecker) Checker {
+	return &httpMonitorChecker{checker: checker}
+}
+
+func (c *httpMonitorChecker) Type() *monitortype.Type {
+	return builtinType("http")
+}
+
+func (c *httpMonitorChecker) Check(ctx context.Context, req *CheckRequest) *CheckResult {
+	monitor := req.Monitor
+
+	var httpResult HTTPCheckResult
+	if httpChecker, err := monitorHTTPChecker(c.checker, req); err != nil {
+		httpResult = HTTPCheckResult{Error: err}
+	} else {
+		httpChecker = httpChecker.WithRedirectPolicy(monitor.Config.Redirect)
+		httpResult = httpChecker.CheckRequest(ctx, monitor.URL, req.Timeout(), monitor.Keyword, monitor.ExpectedStatusCodes, req.HTTPConfig())
+	}
+
+	result := &CheckResult{
+		Success:      httpResult.Success,
+		ResponseTime: httpResult.ResponseTime,
+		StatusCode:   httpResult.StatusCode,
+		Error:        httpResult.Error,
+		HTTP:         &httpResult,
+	}
+	if len(httpResult.Redirects) > 0 {
+		result.Details = &entities.CheckDetails{HTTP: newHTTPCheckDetails(httpResult.Redirects)}
+	}
+	return result
+}
+
+// newHTTPCheckDetails converts the redirect chain of an HTTP check into check details
+func newHTTPCheckDetails(hops []RedirectHop) *entities.HTTPCheckDetails {
+	details := &entities.HTTPCheckDetails{FinalURL: hops[len(hops)-1].URL}
+	for _, hop := range hops {
+		details.Redirects = append(details.Redirects, entities.HTTPRedirect{
+			URL:        hop.URL,
+			StatusCode: hop.StatusCode,
+			Location:   hop.Location,
+		})
+	}
+	return details
+}
+
+// transactionMonitorChecker checks 'transaction' monitors
+type transactionMonitorChecker struct {
+	checker *HTTPChecker
+}
+
+// NewTransactionMonitorChecker creates the checker of 'transaction' monitors, running the steps with checker
+func NewTransactionMonitorChecker(checker *HTTPChecker) Checker {
+	return &transactionMonitorChecker{checker: checker}
+}
+
+func (c *transactionMonitorChecker) Type() *monitortype.Type {
+	return builtinType("transaction")
+}
+
+func (c *transactionMonitorChecker) Check(ctx context.Context, req *CheckRequest) *CheckResult {
+	// Monitor URL is the base for relative step URLs
+	var transactionConfig entities.TransactionConfig
+	if req.Monitor.Config.Transaction != nil {
+		transactionConfig = *req.Monitor.Config.Transaction
+	}
+
+	var transactionResult TransactionCheckResult
+	if httpChecker, err := monitorHTTPChecker(c.checker, req); err != nil {
+		transactionResult = TransactionCheckResult{Error: err}
+	} else {
+		transactionResult = NewTransactionChecker(httpChecker).Check(ctx, req.Monitor.URL, transactionConfig, req.Timeout())
+	}
+
+	transactionDetails := &entities.TransactionCheckDetails{FailedStep: transactionResult.FailedStep}
+	for _, step := range transactionResult.Steps {
+		stepDetails := entities.TransactionStepDetails{
+			Name:           step.Name,
+			URL:            step.URL,
+			StatusCode:     step.StatusCode,
+			ResponseTimeMs: step.ResponseTime.Milliseconds(),
+			Success:        step.Success,
+		}
+		if step.Error != nil {
+			stepDetails.Error = step.Error.Error()
+		}
+		transactionDetails.Steps = append(transactionDetails.Steps, stepDetails)
+	}
+
+	return &CheckResult{
+		Success:      transactionResult.Success,
+		ResponseTime: transactionResult.ResponseTime,
+		StatusCode:   transactionResult.StatusCode,
+		Error:        transactionResult.Error,
+		Details:      &entities.CheckDetails{Transaction: transactionDetails},
+	}
+}
+
+// monitorHTTPChecker returns the HTTP checker for a monitor, with its own transport when the
+// monitor overrides TLS or addressing settings, presents a client certificate or uses a proxy
+func monitorHTTPChecker(checker *HTTPChecker, req *CheckRequest) (*HTTPChecker, error) {
+	monitor := req.Monitor
+	if monitor.Config.Connection == nil && req.ClientCertificate == nil && monitor.Config.Proxy == nil {
+		return checker, nil
+	}
+
+	parsedURL, err := url.Parse(monitor.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	checker, err = checker.WithConnection(ConnectionOptions{
+		Config:            monitor.Config.Connection,
+		PinnedHost:        parsedURL.Hostname(),
+		ClientCertificate: req.ClientCertificate,
+		Proxy:             req.Proxy(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid connection settings: %w", err)
+	}
+	return checker, nil
+}
+
+// tcpMonitorChecker checks 'tcp' monitors
+type tcpMonitorChecker struct {
+	checker *TCPChecker
+}
+
+// NewTCPMonitorChecker creates the checker of 'tcp' monitors
+func NewTCPMonitorChecker(checker *TCPChecker) Checker {
+	return &tcpMonitorChecker{checker: checker}
+}
+
+func (c *tcpMonitorChecker) Type() *monitortype.Type {
+	return builtinType("tcp")
+}
+
+func (c *tcpMonitorChecker) Check(ctx context.Context, req *CheckRequest) *CheckResult {
+	monitor := req.Monitor
+
+	// Parse TCP URL (expected format: host:port, tcp://host:port or tls://host:port)
+	host, port, useTLS, err := parseTCPAddress(monitor.URL)
+	if err != nil {
+		return &CheckResult{Error: fmt.Errorf("invalid TCP address: %w", err)}
+	}
+
+	tcpResult := c.checker.WithClientCertificate(req.ClientCertificate).WithProxy(req.Proxy()).Check(ctx, host, port, useTLS, req.Timeout(), monitor.Config.TCP)
+	result := &CheckResult{
+		Success:      tcpResult.Success,
+		ResponseTime: tcpResult.ResponseTime,
+		Error:        tcpResult.Error,
+	}
+	if useTLS || tcpResult.Response != nil {
+		result.Details = &entities.CheckDetails{
+			TCP: &entities.TCPCheckDetails{
+				TLS:      useTLS,
+				Response: PreviewTCPResponse(tcpResult.Response),
+			},
+		}
+	}
+	return result
+}
+
+// parseTCPAddress parses a TCP address string and returns host, port and whether to use TLS
+// Supports formats: "host:port", "tcp://host:port" or "tls://host:port"
+func parseTCPAddress(address string) (string, int, bool, error) {
+	// Remove tcp:// or tls:// prefix if present
+	useTLS := false
+	if len(address) > 6 && address[:6] == "tcp://" {
+		address = address[6:]
+	} else if len(address) > 6 && address[:6] == "tls://" {
+		address = address[6:]
+		useTLS = true
+	}
+
+	// Parse host:port
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", 0, false, fmt.Errorf("invalid address format: %w", err)
+	}
+
+	// Parse port number
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return "", 0, false, fmt.Errorf("invalid port number: %w", err)
+	}
+
+	if port < 1 || port > 65535 {
+		return "", 0, false, fmt.Errorf("port number out of range: %d", port)
+	}
+
+	return host, port, useTLS, nil
+}
+
+// udpMonitorChecker checks 'udp' monitors
+type udpMonitorChecker struct {
+	checker *UDPChecker
+}
+
+// NewUDPMonitorChecker creates the checker of 'udp' monitors
+func NewUDPMonitorChecker(checker *UDPChecker) Checker {
+	return &udpMonitorChecker{checker: checker}
+}
+
+func (c *udpMonitorChecker) Type() *monitortype.Type {
+	return builtinType("udp")
+}
+
+func (c *udpMonitorChecker) Check(ctx context.Context, req *CheckRequest) *CheckResult {
+	// Monitor URL holds host:port of the UDP service
+	var udpConfig entities.UDPConfig
+	if req.Monitor.Config.UDP != nil {
+		udpConfig = *req.Monitor.Config.UDP
+	}
+	// The community is stored encrypted, the config only holds MaskedSecret
+	udpConfig.Community = ""
+	if req.Secrets != nil {
+		udpConfig.Community = req.Secrets.SNMPCommunity
+	}
+
+	udpResult := c.checker.Check(ctx, req.Monitor.URL, udpConfig, req.Timeout())
+	result := &CheckResult{
+		Success:      udpResult.Success,
+		ResponseTime: udpResult.ResponseTime,
+		Error:        udpResult.Error,
+	}
+	if udpResult.Response != nil {
+		response, encoding := previewUDPResponse(udpResult.Response)
+		result.Details = &entities.CheckDetails{
+			UDP: &entities.UDPCheckDetails{
+				Preset:   udpConfig.Preset,
+				Response: response,
+				Encoding: encoding,
+				Size:     len(udpResult.Response),
+			},
+		}
+	}
+	return result
+}
+
+// icmpMonitorChecker checks 'ping' and 'icmp' monitors
+type icmpMonitorChecker struct {
+	checker  *ICMPChecker
+	typeName string
+}
+
+// NewICMPMonitorChecker creates the checker of 'ping' or 'icmp' monitors, the two names of the same check
+func NewICMPMonitorChecker(checker *ICMPChecker, typeName string) Checker {
+	return &icmpMonitorChecker{checker: checker, typeName: typeName}
+}
+
+func (c *icmpMonitorChecker) Type() *monitortype.Type {
+	return builtinType(c.typeName)
+}
+
+func (c *icmpMonitorChecker) Check(ctx context.Context, req *CheckRequest) *CheckResult {
+	// Prepare host for ping (strip protocol)
+	host := req.Monitor.URL
+	if strings.HasPrefix(host, "http://") {
+		host = host[7:]
+	} else if strings.HasPrefix(host, "https://") {
+		host = host[8:]
+	}
+	// Strip path/query/fragment if present, simplistic approach
+	// Ideally we rely on validation, but let's be safe against basic mistakes
+	// e.g. google.com/foo -> google.com
+	if idx := strings.Index(host, "/"); idx != -1 {
+		host = host[:idx]
+	}
+
+	count := DefaultICMPProbeCount
+	if req.Monitor.Config.ICMP != nil && req.Monitor.Config.ICMP.Count > 0 {
+		count = req.Monitor.Config.ICMP.Count
+	}
+
+	icmpResult := c.checker.Check(ctx, host, count, req.Timeout())
+	return &CheckResult{
+		Success:      icmpResult.Success,
+		ResponseTime: icmpResult.ResponseTime,
+		Error:        icmpResult.Error,
+		Details: &entities.CheckDetails{
+			ICMP: &entities.ICMPCheckDetails{
+				PacketsSent:     icmpResult.PacketsSent,
+				PacketsReceived: icmpResult.PacketsReceived,
+				PacketLoss:      icmpResult.PacketLoss,
+				MinRTTMs:        DurationToMs(icmpResult.MinRTT),
+				AvgRTTMs:        DurationToMs(icmpResult.AvgRTT),
+				MaxRTTMs:        DurationToMs(icmpResult.MaxRTT),
+				JitterMs:        DurationToMs(icmpResult.Jitter),
+			},
+		},
+	}
+}
+
+// dnsMonitorChecker checks 'dns' monitors
+type dnsMonitorChecker struct {
+	checker *DNSChecker
+}
+
+// NewDNSMonitorChecker creates the checker of 'dns' monitors
+func NewDNSMonitorChecker(checker *DNSChecker) Checker {
+	return &dnsMonitorChecker{checker: checker}
+}
+
+func (c *dnsMonitorChecker) Type() *monitortype.Type {
+	return builtinType("dns")
+}
+
+func (c *dnsMonitorChecker) Check(ctx context.Context, req *CheckRequest) *CheckResult {
+	// Monitor URL holds the domain name to resolve
+	dnsConfig := entities.DNSConfig{RecordType: "A"}
+	if req.Monitor.Config.DNS != nil {
+		dnsConfig = *req.Monitor.Config.DNS
+	}
+
+	dnsResult := c.checker.Check(ctx, req.Monitor.URL, dnsConfig, req.Timeout())
+	return &CheckResult{
+		Success:      dnsResult.Success,
+		ResponseTime: dnsResult.ResponseTime,
+		Error:        dnsResult.Error,
+		Details: &entities.CheckDetails{
+			DNS: &entities.DNSCheckDetails{
+				RecordType: dnsConfig.RecordType,
+				Resolver:   dnsResult.Resolver,
+				Answers:    dnsResult.Answers,
+			},
+		},
+	}
+}
+
+// grpcMonitorChecker checks 'grpc' monitors
+type grpcMonitorChecker struct {
+	checker *GRPCChecker
+}
+
+// NewGRPCMonitorChecker creates the checker of 'grpc' monitors
+func NewGRPCMonitorChecker(checker *GRPCChecker) Checker {
+	return &grpcMonitorChecker{checker: checker}
+}
+
+func (c *grpcMonitorChecker) Type() *monitortype.Type {
+	return builtinType("grpc")
+}
+
+func (c *grpcMonitorChecker) Check(ctx context.Context, req *CheckRequest) *CheckResult {
+	// Monitor URL holds host:port of the gRPC server
+	var grpcConfig entities.GRPCConfig
+	if req.Monitor.Config.GRPC != nil {
+		grpcConfig = *req.Monitor.Config.GRPC
+	}
+
+	grpcResult := c.checker.WithClientCertificate(req.ClientCertificate).Check(ctx, req.Monitor.URL, grpcConfig, req.Timeout())
+	return &CheckResult{
+		Success:      grpcResult.Success,
+		ResponseTime: grpcResult.ResponseTime,
+		Error:        grpcResult.Error,
+		Details: &entities.CheckDetails{
+			GRPC: &entities.GRPCCheckDetails{
+				Status: grpcResult.Status,
+				Code:   grpcResult.Code,
+			},
+		},
+	}
+}
+
+// webSocketMonitorChecker checks 'websocket' monitors
+type webSocketMonitorChecker struct {
+	checker *WebSocketChecker
+}
+
+// NewWebSocketMonitorChecker creates the checker of 'websocket' monitors
+func NewWebSocketMonitorChecker(checker *WebSocketChecker) Checker {
+	return &webSocketMonitorChecker{checker: checker}
+}
+
+func (c *webSocketMonitorChecker) Type() *monitortype.Type {
+	return builtinType("websocket")
+}
+
+func (c *webSocketMonitorChecker) Check(ctx context.Context, req *CheckRequest) *CheckResult {
+	var webSocketConfig entities.WebSocketConfig
+	if req.Monitor.Config.WebSocket != nil {
+		webSocketConfig = *req.Monitor.Config.WebSocket
+	}
+
+	wsResult := c.checker.Check(ctx, req.Monitor.URL, webSocketConfig, req.Timeout())
+	result := &CheckResult{
+		Success:      wsResult.Success,
+		ResponseTime: wsResult.ResponseTime,
+		StatusCode:   wsResult.StatusCode,
+		Error:        wsResult.Error,
+	}
+	if wsResult.StatusCode == http.StatusSwitchingProtocols {
+		result.Details = &entities.CheckDetails{
+			WebSocket: &entities.WebSocketCheckDetails{
+				HandshakeMs:    DurationToMs(wsResult.HandshakeTime),
+				FirstMessageMs: DurationToMs(wsResult.FirstMessageTime),
+				Subprotocol:    wsResult.Subprotocol,
+				Message:        previewWebSocketMessage(wsResult.Message),
+			},
+		}
+	}
+	return result
+}
+
+// mailMonitorChecker checks 'smtp', 'imap' and 'pop3' monitors
+type mailMonitorChecker struct {
+	checker    *MailChecker
+	sslChecker *SSLChecker
+	protocol   string
+}
+
+// NewMailMonitorChecker creates the checker of 'smtp', 'imap' or 'pop3' monitors
+// sslChecker inspects the certificate of the TLS session for monitors with SSL checking enabled
+func NewMailMonitorChecker(checker *MailChecker, sslChecker *SSLChecker, protocol string) Checker {
+	return &mailMonitorChecker{checker: checker, sslChecker: sslChecker, protocol: protocol}
+}
+
+func (c *mailMonitorChecker) Type() *monitortype.Type {
+	return builtinType(c.protocol)
+}
+
+func (c *mailMonitorChecker) Check(ctx context.Context, req *CheckRequest) *CheckResult {
+	// Monitor URL holds host:port of the mail server
+	var mailConfig entities.MailConfig
+	if req.Monitor.Config.Mail != nil {
+		mailConfig = *req.Monitor.Config.Mail
+	}
+	// The password is stored encrypted, the config only holds MaskedSecret
+	mailConfig.Password = ""
+	if req.Secrets != nil {
+		mailConfig.Password = req.Secrets.MailPassword
+	}
+
+	mailResult := c.checker.Check(ctx, c.protocol, req.Monitor.URL, mailConfig, req.Timeout())
+	result := &CheckResult{
+		Success:      mailResult.Success,
+		ResponseTime: mailResult.ResponseTime,
+		Error:        mailResult.Error,
+		Details: &entities.CheckDetails{
+			Mail: &entities.MailCheckDetails{
+				Protocol:      c.protocol,
+				Banner:        mailResult.Banner,
+				TLS:           mailResult.TLS != nil,
+				Authenticated: mailResult.Authenticated,
+			},
+		},
+	}
+
+	if mailResult.TLS != nil && req.Monitor.CheckSSL {
+		host, _, _ := net.SplitHostPort(req.Monitor.URL)
+		sslResult := c.sslChecker.InspectConnection(ctx, *mailResult.TLS, host)
+		result.SSL = &sslResult
+	}
+	return result
+}
+
+// databaseMonitorChecker checks 'postgres', 'mysql' and 'redis' monitors
+type databaseMonitorChecker struct {
+	checker *DatabaseChecker
+	engine  string
+}
+
+// NewDatabaseMonitorChecker creates the checker of the database monitors of engine
+func NewDatabaseMonitorChecker(checker *DatabaseChecker, engine string) Checker {
+	return &databaseMonitorChecker{checker: checker, engine: engine}
+}
+
+func (c *databaseMonitorChecker) Type() *monitortype.Type {
+	return builtinType(c.engine)
+}
+
+func (c *databaseMonitorChecker) Check(ctx context.Context, req *CheckRequest) *CheckResult {
+	// Monitor URL is the credential-free address; the full DSN is stored encrypted
+	if req.Secrets == nil || req.Secrets.DSN == "" {
+		return &CheckResult{Error: fmt.Errorf("no connection string configured")}
+	}
+
+	var databaseConfig entities.DatabaseConfig
+	if req.Monitor.Config.Database != nil {
+		databaseConfig = *req.Monitor.Config.Database
+	}
+
+	databaseResult := c.checker.Check(ctx, c.engine, req.Secrets.DSN, databaseConfig, req.Timeout())
+	return &CheckResult{
+		Success:      databaseResult.Success,
+		ResponseTime: databaseResult.ResponseTime,
+		Error:        databaseResult.Error,
+		Details: &entities.CheckDetails{
+			Database: &entities.DatabaseCheckDetails{
+				ConnectMs: DurationToMs(databaseResult.ConnectTime),
+				QueryMs:   DurationToMs(databaseResult.QueryTime),
+				Result:    databaseResult.Result,
+			},
+		},
+	}
+}
+
+// LatestCheckFunc returns the latest recorded check of a monitor, nil when it has none
+type LatestCheckFunc func(monitorID string) (*entities.MonitorCheck, error)
+
+// dockerMonitorChecker checks 'docker' monitors
+type dockerMonitorChecker struct {
+	checker     *DockerChecker
+	latestCheck LatestCheckFunc
+}
+
+// NewDockerMonitorChecker creates the checker of 'docker' monitors, comparing the restart count
+// with the one recorded by the check returned by latestCheck
+func NewDockerMonitorChecker(checker *DockerChecker, latestCheck LatestCheckFunc) Checker {
+	return &dockerMonitorChecker{checker: checker, latestCheck: latestCheck}
+}
+
+func (c *dockerMonitorChecker) Type() *monitortype.Type {
+	return builtinType("docker")
+}
+
+func (c *dockerMonitorChecker) Check(ctx context.Context, req *CheckRequest) *CheckResult {
+	// Monitor URL holds the container name or ID
+	var dockerConfig entities.DockerConfig
+	if req.Monitor.Config.Docker != nil {
+		dockerConfig = *req.Monitor.Config.Docker
+	}
+
+	var previous *entities.DockerCheckDetails
+	latest, err := c.latestCheck(req.Monitor.ID)
+	if err != nil {
+		return &CheckResult{Error: fmt.Errorf("failed to load the previous check: %w", err)}
+	}
+	if latest != nil && latest.Details != nil {
+		previous = latest.Details.Docker
+	}
+
+	dockerResult := c.checker.WithClientCertificate(req.ClientCertificate).Check(ctx, req.Monitor.URL, dockerConfig, previous, req.Timeout())
+	result := &CheckResult{
+		Success:      dockerResult.Success,
+		ResponseTime: dockerResult.ResponseTime,
+		Error:        dockerResult.Error,
+	}
+	if container := dockerResult.Container; container != nil {
+		details := &entities.DockerCheckDetails{
+			ContainerID:  container.ID,
+			Image:        container.Config.Image,
+			Status:       container.State.Status,
+			RestartCount: container.RestartCount,
+			ExitCode:     container.State.ExitCode,
+		}
+		if container.State.Health != nil {
+			details.Health = container.State.Health.Status
+		}
+		if !container.State.StartedAt.IsZero() {
+			startedAt := container.State.StartedAt
+			details.StartedAt = &startedAt
+		}
+		result.Details = &entities.CheckDetails{Docker: details}
+	}
+	return result
+}
+
+// domainMonitorChecker checks 'domain' monitors
+type domainMonitorChecker struct {
+	checker *DomainChecker
+}
+
+// NewDomainMonitorChecker creates the checker of 'domain' monitors
+func NewDomainMonitorChecker(checker *DomainChecker) Checker {
+	return &domainMonitorChecker{checker: checker}
+}
+
+func (c *domainMonitorChecker) Type() *monitortype.Type {
+	return builtinType("domain")
+}
+
+func (c *domainMonitorChecker) Check(ctx context.Context, req *CheckRequest) *CheckResult {
+	// Monitor URL holds the domain name, reduced to the registrable domain by the checker
+	domainResult := c.checker.Check(ctx, req.Monitor.URL, req.Timeout())
+	result := &CheckResult{
+		Success:      domainResult.Success,
+		ResponseTime: domainResult.ResponseTime,
+		Error:        domainResult.Error,
+	}
+	if domainResult.Domain != "" {
+		result.Details = &entities.CheckDetails{
+			Domain: &entities.DomainCheckDetails{
+				Domain:    domainResult.Domain,
+				Registrar: domainResult.Registrar,
+				Source:    domainResult.Source,
+			},
+		}
+		if !domainResult.ExpiresAt.IsZero() {
+			expiresAt := domainResult.ExpiresAt
+			result.Details.Domain.ExpiresAt = &expiresAt
+		}
+	}
+	return result
+}
+
+// pushMonitorChecker checks 'push' monitors
+type pushMonitorChecker struct{}
+
+// NewPushMonitorChecker creates the checker of 'push' monitors
+func NewPushMonitorChecker() Checker {
+	return pushMonitorChecker{}
+}
+
+func (pushMonitorChecker) Type() *monitortype.Type {
+	return builtinType("push")
+}
+
+// Check never probes: heartbeats are recorded by the backend and bump last_checked_at, so the
+// monitor is due here once the interval has passed and is down when the grace period is over too
+func (pushMonitorChecker) Check(ctx context.Context, req *CheckRequest) *CheckResult {
+	lastSeen, deadline := pushHeartbeatDeadline(req.Monitor)
+	if req.CheckedAt.Before(deadline) {
+		return &CheckResult{Skipped: true} // still within the grace period
+	}
+	return &CheckResult{
+		Error: fmt.Errorf("no heartbeat received in the last %s", req.CheckedAt.Sub(lastSeen).Round(time.Second)),
+	}
+}
+
+// pushHeartbeatDeadline returns when a push monitor was last seen (last heartbeat or missed-heartbeat
+// check, creation time before that) and the time after which a missing heartbeat marks it down
+func pushHeartbeatDeadline(monitor *entities.Monitor) (time.Time, time.Time) {
+	lastSeen := monitor.CreatedAt
+	if monitor.LastCheckedAt != nil {
+		lastSeen = *monitor.LastCheckedAt
+	}
+
+	gracePeriod := defaultPushGracePeriod
+	if monitor.Config.Push != nil && monitor.Config.Push.GracePeriod > 0 {
+		gracePeriod = time.Duration(monitor.Config.Push.GracePeriod) * time.Second
+	}
+
+	return lastSeen, lastSeen.Add(time.Duration(monitor.CheckInterval)*time.Second + gracePeriod)
+}
@@ -0,0 +1,287 @@
+package executor
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+)
+
+func TestHTTPMonitorChecker_Check(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	monitor := &entities.Monitor{Type: "http", URL: server.URL + "/old", Timeout: 5, ExpectedStatusCodes: []int64{200}}
+	result := NewHTTPMonitorChecker(NewHTTPChecker()).Check(context.Background(), &CheckRequest{Monitor: monitor, CheckedAt: time.Now()})
+
+	if !result.Success || result.StatusCode != http.StatusOK {
+		t.Fatalf("Expected a successful check with status 200, got %+v", result)
+	}
+	if result.HTTP == nil || string(result.HTTP.Body) != "ok" {
+		t.Errorf("Expected the response to be kept for content checks and evidence")
+	}
+	if result.Details == nil || result.Details.HTTP == nil || result.Details.HTTP.FinalURL != server.URL+"/new" {
+		t.Errorf("Expected the redirect chain in the details, got %+v", result.Details)
+	}
+}
+
+func TestHTTPMonitorChecker_Check_Credentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "admin" || password != "s3cret" || r.Header.Get("X-Api-Key") != "key-123" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	monitor := &entities.Monitor{
+		Type:                "http",
+		URL:                 server.URL,
+		Timeout:             5,
+		ExpectedStatusCodes: []int64{200},
+		Config: entities.MonitorConfig{HTTP: &entities.HTTPConfig{
+			Headers: map[string]string{"X-Api-Key": entities.MaskedSecret}, // the stored config only marks credentials as set
+			Auth:    &entities.HTTPAuth{Type: "basic", Username: "admin", Password: entities.MaskedSecret},
+		}},
+	}
+	secrets := &entities.MonitorSecrets{HTTPPassword: "s3cret", HTTPHeaders: map[string]string{"X-Api-Key": "key-123"}}
+
+	checker := NewHTTPMonitorChecker(NewHTTPChecker())
+	if result := checker.Check(context.Background(), &CheckRequest{Monitor: monitor, Secrets: secrets}); !result.Success {
+		t.Fatalf("Expected to authenticate with the stored credentials, got %+v", result)
+	}
+	if result := checker.Check(context.Background(), &CheckRequest{Monitor: monitor}); result.Success {
+		t.Errorf("Expected the check to fail without the stored credentials")
+	}
+	if monitor.Config.HTTP.Auth.Password != entities.MaskedSecret {
+		t.Errorf("Expected the monitor config not to be modified")
+	}
+}
+
+func TestTCPMonitorChecker_Check_InvalidAddress(t *testing.T) {
+	monitor := &entities.Monitor{Type: "tcp", URL: "example.com", Timeout: 5}
+	result := NewTCPMonitorChecker(NewTCPChecker()).Check(context.Background(), &CheckRequest{Monitor: monitor})
+
+	if result.Success || result.Error == nil || !strings.Contains(result.Error.Error(), "invalid TCP address") {
+		t.Errorf("Expected an invalid address error, got %+v", result)
+	}
+}
+
+func TestParseTCPAddress(t *testing.T) {
+	tests := []struct {
+		address  string
+		wantHost string
+		wantPort int
+		wantTLS  bool
+		wantErr  bool
+	}{
+		{"db.example.com:5432", "db.example.com", 5432, false, false},
+		{"tcp://db.example.com:5432", "db.example.com", 5432, false, false},
+		{"tls://mail.example.com:465", "mail.example.com", 465, true, false},
+		{"db.example.com", "", 0, false, true},
+		{"db.example.com:70000", "", 0, false, true},
+	}
+
+	for _, tt := range tests {
+		host, port, useTLS, err := parseTCPAddress(tt.address)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.address, tt.wantErr, err)
+			continue
+		}
+		if host != tt.wantHost || port != tt.wantPort || useTLS != tt.wantTLS {
+			t.Errorf("%s: expected %s %d %v, got %s %d %v", tt.address, tt.wantHost, tt.wantPort, tt.wantTLS, host, port, useTLS)
+		}
+	}
+}
+
+func TestMailMonitorChecker_Check(t *testing.T) {
+	root := newTestCertificate(t, "Test Root", time.Now().Add(365*24*time.Hour), true, generateECDSAKey(t), nil)
+	leaf := newTestCertificate(t, "127.0.0.1", time.Now().Add(30*24*time.Hour), false, generateECDSAKey(t), root)
+	roots := x509.NewCertPool()
+	roots.AddCert(root.cert)
+	checker := NewMailMonitorChecker(&MailChecker{rootCAs: roots}, &SSLChecker{timeout: 5 * time.Second, rootCAs: roots}, "imap")
+
+	address := startFakeMailServer(t, fakeMailServer{protocol: "imap", certificate: leaf})
+	monitor := &entities.Monitor{
+		Type:     "imap",
+		URL:      address,
+		Timeout:  5,
+		CheckSSL: true,
+		Config:   entities.MonitorConfig{Mail: &entities.MailConfig{TLS: "starttls"}},
+	}
+
+	result := checker.Check(context.Background(), &CheckRequest{Monitor: monitor})
+	if !result.Success {
+		t.Fatalf("Expected success, got: %v", result.Error)
+	}
+	if result.Details == nil || result.Details.Mail == nil || !result.Details.Mail.TLS || result.Details.Mail.Banner != "IMAP4rev1 mail.test ready" {
+		t.Errorf("Expected the session in the details, got %+v", result.Details)
+	}
+	if result.SSL == nil || !result.SSL.Valid || !result.SSL.ExpiresAt.Equal(leaf.cert.NotAfter) {
+		t.Fatalf("Expected the STARTTLS certificate to be recorded, got %+v", result.SSL)
+	}
+
+	// The certificate is only inspected for monitors with SSL checking enabled
+	monitor.CheckSSL = false
+	if result := checker.Check(context.Background(), &CheckRequest{Monitor: monitor}); result.SSL != nil {
+		t.Errorf("Expected no certificate without SSL checking")
+	}
+}
+
+func TestMailMonitorChecker_Check_Login(t *testing.T) {
+	root := newTestCertificate(t, "Test Root", time.Now().Add(365*24*time.Hour), true, generateECDSAKey(t), nil)
+	leaf := newTestCertificate(t, "127.0.0.1", time.Now().Add(30*24*time.Hour), false, generateECDSAKey(t), root)
+	roots := x509.NewCertPool()
+	roots.AddCert(root.cert)
+	checker := NewMailMonitorChecker(&MailChecker{rootCAs: roots}, &SSLChecker{timeout: 5 * time.Second, rootCAs: roots}, "imap")
+
+	address := startFakeMailServer(t, fakeMailServer{protocol: "imap", certificate: leaf, username: "monitor", password: "s3cret"})
+	monitor := &entities.Monitor{
+		Type:    "imap",
+		URL:     address,
+		Timeout: 5,
+		Config: entities.MonitorConfig{Mail: &entities.MailConfig{
+			TLS:      "starttls",
+			Username: "monitor",
+			Password: entities.MaskedSecret, // the stored config only marks the password as set
+		}},
+	}
+
+	result := checker.Check(context.Background(), &CheckRequest{Monitor: monitor, Secrets: &entities.MonitorSecrets{MailPassword: "s3cret"}})
+	if !result.Success || !result.Details.Mail.Authenticated {
+		t.Fatalf("Expected to log in with the stored password, got: %v", result.Error)
+	}
+
+	if result := checker.Check(context.Background(), &CheckRequest{Monitor: monitor}); result.Success {
+		t.Errorf("Expected the login to fail without the stored password")
+	}
+}
+
+func TestDockerMonitorChecker_Check(t *testing.T) {
+	socket := startDockerSocket(t)
+	monitor := &entities.Monitor{
+		ID:      "monitor-1",
+		Type:    "docker",
+		URL:     "api",
+		Timeout: 5,
+		Config:  entities.MonitorConfig{Docker: &entities.DockerConfig{Endpoint: "unix://" + socket}},
+	}
+
+	var latest *entities.MonitorCheck
+	checker := NewDockerMonitorChecker(NewDockerChecker([]string{socket}), func(monitorID string) (*entities.MonitorCheck, error) {
+		if monitorID != monitor.ID {
+			t.Errorf("Expected the latest check of %s, got %s", monitor.ID, monitorID)
+		}
+		return latest, nil
+	})
+
+	// The first check has nothing to compare the restart count with
+	result := checker.Check(context.Background(), &CheckRequest{Monitor: monitor})
+	if !result.Success {
+		t.Fatalf("Expected success, got: %v", result.Error)
+	}
+	docker := result.Details.Docker
+	if docker == nil || docker.ContainerID != "bbb222" || docker.Image != "api:2.1" || docker.Health != "healthy" || docker.RestartCount != 5 || docker.StartedAt == nil {
+		t.Fatalf("Expected the container state in the details, got %+v", docker)
+	}
+
+	latest = &entities.MonitorCheck{Details: &entities.CheckDetails{Docker: &entities.DockerCheckDetails{ContainerID: "bbb222", RestartCount: 4}}}
+	if result := checker.Check(context.Background(), &CheckRequest{Monitor: monitor}); result.Success || result.Details == nil {
+		t.Errorf("Expected a restart since the previous check to fail with details, got %+v", result)
+	}
+
+	checker = NewDockerMonitorChecker(NewDockerChecker([]string{socket}), func(string) (*entities.MonitorCheck, error) {
+		return nil, errors.New("connection refused")
+	})
+	if result := checker.Check(context.Background(), &CheckRequest{Monitor: monitor}); result.Success || result.Error == nil || !strings.Contains(result.Error.Error(), "failed to load the previous check") {
+		t.Errorf("Expected the lookup error, got %+v", result)
+	}
+}
+
+func TestDatabaseMonitorChecker_Check_NoSecrets(t *testing.T) {
+	monitor := &entities.Monitor{Type: "postgres", URL: "postgres://db.example.com:5432/app", Timeout: 5}
+	result := NewDatabaseMonitorChecker(NewDatabaseChecker(), "postgres").Check(context.Background(), &CheckRequest{Monitor: monitor})
+
+	if result.Success || result.Error == nil || result.Error.Error() != "no connection string configured" {
+		t.Errorf("Expected a missing connection string error, got %+v", result)
+	}
+}
+
+func TestPushMonitorChecker_Check(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	monitor := &entities.Monitor{Type: "push", CheckInterval: 300, CreatedAt: createdAt}
+	checker := NewPushMonitorChecker()
+
+	result := checker.Check(context.Background(), &CheckRequest{Monitor: monitor, CheckedAt: createdAt.Add(5 * time.Minute)})
+	if !result.Skipped {
+		t.Errorf("Expected the check to be skipped within the grace period, got %+v", result)
+	}
+
+	result = checker.Check(context.Background(), &CheckRequest{Monitor: monitor, CheckedAt: createdAt.Add(10 * time.Minute)})
+	if result.Skipped || result.Success || result.Error == nil {
+		t.Fatalf("Expected a missed heartbeat, got %+v", result)
+	}
+	if result.Error.Error() != "no heartbeat received in the last 10m0s" {
+		t.Errorf("Unexpected error: %v", result.Error)
+	}
+}
+
+// TestPushHeartbeatDeadline tests when a push monitor without heartbeats is marked down
+func TestPushHeartbeatDeadline(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	lastHeartbeat := createdAt.Add(time.Hour)
+
+	tests := []struct {
+		name          string
+		lastCheckedAt *time.Time
+		config        entities.MonitorConfig
+		wantLastSeen  time.Time
+		wantDeadline  time.Time
+	}{
+		{
+			name:         "never received, default grace period",
+			wantLastSeen: createdAt,
+			wantDeadline: createdAt.Add(5*time.Minute + 60*time.Second),
+		},
+		{
+			name:          "last heartbeat, custom grace period",
+			lastCheckedAt: &lastHeartbeat,
+			config:        entities.MonitorConfig{Push: &entities.PushConfig{GracePeriod: 600}},
+			wantLastSeen:  lastHeartbeat,
+			wantDeadline:  lastHeartbeat.Add(5*time.Minute + 10*time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			monitor := &entities.Monitor{
+				Type:          "push",
+				CheckInterval: 300,
+				Config:        tt.config,
+				LastCheckedAt: tt.lastCheckedAt,
+				CreatedAt:     createdAt,
+			}
+
+			lastSeen, deadline := pushHeartbeatDeadline(monitor)
+			if !lastSeen.Equal(tt.wantLastSeen) {
+				t.Errorf("Expected last seen %v, got %v", tt.wantLastSeen, lastSeen)
+			}
+			if !deadline.Equal(tt.wantDeadline) {
+				t.Errorf("Expected deadline %v, got %v", tt.wantDeadline, deadline)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package executor
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+	"golang.org/x/net/proxy"
+)
+
+// MonitorProxy returns the proxy of a monitor with the password from its decrypted secrets, nil when
+// the monitor connects directly. The stored config only holds MaskedSecret in place of the password.
+func MonitorProxy(monitor *entities.Monitor, monitorSecrets *entities.MonitorSecrets) *entities.ProxyConfig {
+	if monitor.Config.Proxy == nil {
+		return nil
+	}
+	proxyConfig := *monitor.Config.Proxy
+	proxyConfig.Password = ""
+	if monitorSecrets != nil {
+		proxyConfig.Password = monitorSecrets.ProxyPassword
+	}
+	return &proxyConfig
+}
+
+// proxyDialer opens connections through the HTTP CONNECT or SOCKS5 proxy of a monitor
+type proxyDialer struct {
+	cfg     *entities.ProxyConfig
+	forward *net.Dialer // dials the proxy itself
+}
+
+// newProxyDialer creates a dialer that tunnels through the proxy in cfg
+func newProxyDialer(cfg *entities.ProxyConfig, forward *net.Dialer) *proxyDialer {
+	return &proxyDialer{cfg: cfg, forward: forward}
+}
+
+// DialContext connects to address through the proxy
+func (d *proxyDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	switch d.cfg.Type {
+	case "http":
+		return d.dialConnect(ctx, address)
+	case "socks5":
+		var auth *proxy.Auth
+		if d.cfg.Username != "" {
+			auth = &proxy.Auth{User: d.cfg.Username, Password: d.cfg.Password}
+		}
+		dialer, err := proxy.SOCKS5("tcp", d.cfg.Address, auth, d.forward)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SOCKS5 proxy: %w", err)
+		}
+		conn, err := dialer.(proxy.ContextDialer).DialContext(ctx, network, address)
+		if err != nil {
+			return nil, fmt.Errorf("SOCKS5 proxy %s: %w", d.cfg.Address, err)
+		}
+		return conn, nil
+	default:
+		return nil, fmt.Errorf("unsupported proxy type %q", d.cfg.Type)
+	}
+}
+
+// dialConnect opens a tunnel to address with an HTTP CONNECT request
+func (d *proxyDialer) dialConnect(ctx context.Context, address string) (net.Conn, error) {
+	conn, err := d.forward.DialContext(ctx, "tcp", d.cfg.Address)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP proxy %s: %w", d.cfg.Address, err)
+	}
+
+	// Unblock the exchange when the check is cancelled or times out
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Now())
+	})
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: make(http.Header),
+	}
+	if d.cfg.Username != "" {
+		credentials := base64.StdEncoding.EncodeToString([]byte(d.cfg.Username + ":" + d.cfg.Password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	reader := bufio.NewReader(conn)
+	err = req.Write(conn)
+	var resp *http.Response
+	if err == nil {
+		resp, err = http.ReadResponse(reader, req)
+	}
+	if !stop() {
+		err = ctx.Err()
+	}
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("HTTP proxy %s: CONNECT failed: %w", d.cfg.Address, err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("HTTP proxy %s: CONNECT %s refused: %s", d.cfg.Address, address, resp.Status)
+	}
+
+	// Servers that speak first (e.g. SMTP banners) may already have sent data
+	if reader.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, reader: reader}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn is a connection whose first bytes were already read into reader
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+// newProxyURL returns the proxy in cfg as a URL for http.Transport
+func newProxyURL(cfg *entities.ProxyConfig) (*url.URL, error) {
+	if cfg.Type != "http" && cfg.Type != "socks5" {
+		return nil, fmt.Errorf("unsupported proxy type %q", cfg.Type)
+	}
+
+	proxyURL := &url.URL{Scheme: cfg.Type, Host: cfg.Address}
+	if cfg.Username != "" {
+		proxyURL.User = url.UserPassword(cfg.Username, cfg.Password)
+	}
+	return proxyURL, nil
+}
@@ -0,0 +1,303 @@
+package executor
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+)
+
+// startTestProxy runs handle for every connection and returns the listener address and
+// a channel receiving the targets the proxy connected to
+func startTestProxy(t *testing.T, handle func(conn net.Conn, targets chan<- string)) (string, <-chan string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to create proxy: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	targets := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go handle(conn, targets)
+		}
+	}()
+
+	return listener.Addr().String(), targets
+}
+
+// pipe copies data both ways between the client and target until either side closes
+func pipe(client, target net.Conn) {
+	defer client.Close()
+	defer target.Close()
+	go io.Copy(target, client)
+	io.Copy(client, target)
+}
+
+// startConnectProxy starts an HTTP CONNECT proxy that requires the given Basic credentials
+func startConnectProxy(t *testing.T, username, password string) (string, <-chan string) {
+	expectedAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+
+	return startTestProxy(t, func(conn net.Conn, targets chan<- string) {
+		req, err := http.ReadRequest(bufio.NewReader(conn))
+		if err != nil {
+			conn.Close()
+			return
+		}
+		if req.Method != http.MethodConnect || req.Header.Get("Proxy-Authorization") != expectedAuth {
+			fmt.Fprint(conn, "HTTP/1.1 407 Proxy Authentication Required\r\n\r\n")
+			conn.Close()
+			return
+		}
+
+		target, err := net.Dial("tcp", req.Host)
+		if err != nil {
+			fmt.Fprint(conn, "HTTP/1.1 502 Bad Gateway\r\n\r\n")
+			conn.Close()
+			return
+		}
+		targets <- req.Host
+		fmt.Fprint(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+		pipe(conn, target)
+	})
+}
+
+// startSOCKS5Proxy starts a SOCKS5 proxy (RFC 1928) that requires the given username/password (RFC 1929)
+func startSOCKS5Proxy(t *testing.T, username, password string) (string, <-chan string) {
+	return startTestProxy(t, func(conn net.Conn, targets chan<- string) {
+		reader := bufio.NewReader(conn)
+		fail := func() { conn.Close() }
+
+		// Greeting: version, methods; only username/password is accepted
+		header := make([]byte, 2)
+		if _, err := io.ReadFull(reader, header); err != nil || header[0] != 5 {
+			fail()
+			return
+		}
+		if _, err := io.ReadFull(reader, make([]byte, header[1])); err != nil {
+			fail()
+			return
+		}
+		conn.Write([]byte{5, 2})
+
+		// Username/password sub-negotiation
+		version, _ := reader.ReadByte()
+		userLen, _ := reader.ReadByte()
+		user := make([]byte, userLen)
+		io.ReadFull(reader, user)
+		passLen, _ := reader.ReadByte()
+		pass := make([]byte, passLen)
+		io.ReadFull(reader, pass)
+		if version != 1 || string(user) != username || string(pass) != password {
+			conn.Write([]byte{1, 1})
+			fail()
+			return
+		}
+		conn.Write([]byte{1, 0})
+
+		// Request: version, CONNECT, reserved, address type, address, port
+		request := make([]byte, 4)
+		if _, err := io.ReadFull(reader, request); err != nil || request[1] != 1 {
+			fail()
+			return
+		}
+		var host string
+		switch request[3] {
+		case 1:
+			ip := make([]byte, 4)
+			io.ReadFull(reader, ip)
+			host = net.IP(ip).String()
+		case 3:
+			length, _ := reader.ReadByte()
+			name := make([]byte, length)
+			io.ReadFull(reader, name)
+			host = string(name)
+		default:
+			fail()
+			return
+		}
+		portBytes := make([]byte, 2)
+		io.ReadFull(reader, portBytes)
+		address := net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(portBytes))))
+
+		target, err := net.Dial("tcp", address)
+		if err != nil {
+			conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+			fail()
+			return
+		}
+		targets <- address
+		conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+		pipe(conn, target)
+	})
+}
+
+// startBannerServer accepts connections and greets them with banner
+func startBannerServer(t *testing.T, banner string) (string, int) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				conn.Write([]byte(banner))
+				time.Sleep(100 * time.Millisecond)
+			}()
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+func TestTCPChecker_Check_Proxy(t *testing.T) {
+	host, port := startBannerServer(t, "220 ready\r\n")
+	target := net.JoinHostPort(host, strconv.Itoa(port))
+	expect := &entities.TCPConfig{Expect: "220 ready"}
+
+	connectAddr, connectTargets := startConnectProxy(t, "monitor", "s3cret")
+	socksAddr, socksTargets := startSOCKS5Proxy(t, "monitor", "s3cret")
+
+	tests := []struct {
+		name    string
+		proxy   *entities.ProxyConfig
+		targets <-chan string
+	}{
+		{"http", &entities.ProxyConfig{Type: "http", Address: connectAddr, Username: "monitor", Password: "s3cret"}, connectTargets},
+		{"socks5", &entities.ProxyConfig{Type: "socks5", Address: socksAddr, Username: "monitor", Password: "s3cret"}, socksTargets},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewTCPChecker().WithProxy(tt.proxy).Check(context.Background(), host, port, false, 2*time.Second, expect)
+			if !result.Success {
+				t.Fatalf("Expected success through the proxy, got: %v", result.Error)
+			}
+			if got := <-tt.targets; got != target {
+				t.Errorf("Expected the proxy to connect to %s, got %s", target, got)
+			}
+
+			wrongPassword := *tt.proxy
+			wrongPassword.Password = "wrong"
+			result = NewTCPChecker().WithProxy(&wrongPassword).Check(context.Background(), host, port, false, 2*time.Second, expect)
+			if result.Success {
+				t.Errorf("Expected the proxy to reject wrong credentials")
+			}
+		})
+	}
+}
+
+func TestHTTPChecker_WithConnection_Proxy(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	connectAddr, connectTargets := startConnectProxy(t, "monitor", "s3cret")
+	socksAddr, socksTargets := startSOCKS5Proxy(t, "monitor", "s3cret")
+	target := server.Listener.Addr().String()
+
+	tests := []struct {
+		name    string
+		proxy   *entities.ProxyConfig
+		targets <-chan string
+	}{
+		{"http", &entities.ProxyConfig{Type: "http", Address: connectAddr, Username: "monitor", Password: "s3cret"}, connectTargets},
+		{"socks5", &entities.ProxyConfig{Type: "socks5", Address: socksAddr, Username: "monitor", Password: "s3cret"}, socksTargets},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker, err := NewHTTPChecker().WithConnection(ConnectionOptions{
+				Config: &entities.ConnectionConfig{InsecureSkipVerify: true},
+				Proxy:  tt.proxy,
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			result := checker.CheckURL(context.Background(), server.URL, 5*time.Second, "")
+			if !result.Success {
+				t.Fatalf("Expected success through the proxy, got: %v", result.Error)
+			}
+			if got := <-tt.targets; got != target {
+				t.Errorf("Expected the proxy to connect to %s, got %s", target, got)
+			}
+		})
+	}
+
+	if _, err := NewHTTPChecker().WithConnection(ConnectionOptions{Proxy: &entities.ProxyConfig{Type: "ftp", Address: connectAddr}}); err == nil {
+		t.Errorf("Expected an error for an unsupported proxy type")
+	}
+}
+
+func TestSSLChecker_CheckEndpoint_Proxy(t *testing.T) {
+	root := newTestCertificate(t, "Test Root", time.Now().Add(24*time.Hour), true, generateECDSAKey(t), nil)
+	leaf := newTestCertificate(t, "proxied.example.test", time.Now().Add(24*time.Hour), false, generateECDSAKey(t), root)
+	serverURL := startTLSChainServer(t, leaf, root, tls.VersionTLS12)
+
+	host, port, _ := net.SplitHostPort(serverURL[len("https://"):])
+	connectAddr, targets := startConnectProxy(t, "monitor", "s3cret")
+
+	checker := NewSSLChecker(5 * time.Second).WithProxy(&entities.ProxyConfig{Type: "http", Address: connectAddr, Username: "monitor", Password: "s3cret"})
+	result := checker.CheckEndpoint(context.Background(), host, port, "proxied.example.test", "")
+
+	if len(result.Chain) == 0 || result.Chain[0].Subject != "proxied.example.test" {
+		t.Fatalf("Expected the certificate to be read through the proxy, got %+v (error: %v)", result.Chain, result.Error)
+	}
+	if got := <-targets; got != net.JoinHostPort(host, port) {
+		t.Errorf("Expected the proxy to connect to %s:%s, got %s", host, port, got)
+	}
+}
+
+func TestTCPMonitorChecker_Check_ProxyPassword(t *testing.T) {
+	host, port := startBannerServer(t, "220 ready\r\n")
+	socksAddr, socksTargets := startSOCKS5Proxy(t, "monitor", "s3cret")
+	monitor := &entities.Monitor{
+		Type:    "tcp",
+		URL:     net.JoinHostPort(host, strconv.Itoa(port)),
+		Timeout: 2,
+		Config: entities.MonitorConfig{
+			TCP:   &entities.TCPConfig{Expect: "220 ready"},
+			Proxy: &entities.ProxyConfig{Type: "socks5", Address: socksAddr, Username: "monitor", Password: entities.MaskedSecret},
+		},
+	}
+	checker := NewTCPMonitorChecker(NewTCPChecker())
+
+	result := checker.Check(context.Background(), &CheckRequest{Monitor: monitor, Secrets: &entities.MonitorSecrets{ProxyPassword: "s3cret"}})
+	if !result.Success {
+		t.Fatalf("Expected success with the stored proxy password, got: %v", result.Error)
+	}
+	<-socksTargets
+
+	if result := checker.Check(context.Background(), &CheckRequest{Monitor: monitor}); result.Success {
+		t.Errorf("Expected the proxy to reject the check without the stored password")
+	}
+}
@@ -0,0 +1,346 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// Revocation statuses reported for the leaf certificate
+const (
+	RevocationGood    = "good"
+	RevocationRevoked = "revoked"
+	RevocationUnknown = "unknown"
+)
+
+// Sources of a revocation status
+const (
+	RevocationSourceStaple = "ocsp_staple"
+	RevocationSourceOCSP   = "ocsp"
+	RevocationSourceCRL    = "crl"
+)
+
+const (
+	// maxOCSPResponseSize limits the OCSP response read from a responder
+	maxOCSPResponseSize = 1 << 20
+	// maxCRLSize limits the CRL downloaded from a distribution point
+	maxCRLSize = 20 << 20
+
+	// maxRevocationCacheAge bounds how long a status is reused when its OCSP response or CRL is
+	// valid for longer, or does not say when it is updated
+	maxRevocationCacheAge = 6 * time.Hour
+	// revocationRetryInterval is how long an unknown status is reused before the responders and
+	// distribution points are queried again
+	revocationRetryInterval = 15 * time.Minute
+	// revocationClockSkew is tolerated between the validity of a response or CRL and the local clock
+	revocationClockSkew = 5 * time.Minute
+)
+
+// RevocationResult represents the revocation status of the leaf certificate
+type RevocationResult struct {
+	Status       string        // good, revoked or unknown
+	Source       string        // ocsp_staple, ocsp or crl; empty when no source answered
+	ResponseTime time.Duration // time spent querying the responder or downloading the CRL, 0 for a staple or a cached status
+	RevokedAt    time.Time
+	Reason       string // revocation reason, e.g. keyCompromise
+	Error        error  // why the status is unknown
+}
+
+// revocationCache keeps the status of certificates, by issuer and serial number, until the OCSP
+// response or CRL it was read from is due to be updated. It is shared by the copies of an SSLChecker.
+type revocationCache struct {
+	mu      sync.Mutex
+	entries map[string]revocationCacheEntry
+}
+
+type revocationCacheEntry struct {
+	result    RevocationResult
+	expiresAt time.Time
+}
+
+func newRevocationCache() *revocationCache {
+	return &revocationCache{entries: make(map[string]revocationCacheEntry)}
+}
+
+// get returns the cached status of a certificate, a nil cache never has one
+func (c *revocationCache) get(key string, now time.Time) (RevocationResult, bool) {
+	if c == nil {
+		return RevocationResult{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !now.Before(entry.expiresAt) {
+		return RevocationResult{}, false
+	}
+	result := entry.result
+	result.ResponseTime = 0
+	return result, true
+}
+
+// put stores the status of a certificate until expiresAt, dropping the expired entries
+func (c *revocationCache) put(key string, result RevocationResult, expiresAt time.Time) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for k, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = revocationCacheEntry{result: result, expiresAt: expiresAt}
+}
+
+// revocationCacheKey identifies leaf by its issuer and serial number
+func revocationCacheKey(leaf, issuer *x509.Certificate) string {
+	sum := sha256.Sum256(issuer.Raw)
+	return hex.EncodeToString(sum[:]) + ":" + leaf.SerialNumber.Text(16)
+}
+
+// revocationCacheExpiry returns until when a status read from a response or CRL valid until
+// nextUpdate is reused, at most maxRevocationCacheAge
+func revocationCacheExpiry(nextUpdate, now time.Time) time.Time {
+	expiresAt := now.Add(maxRevocationCacheAge)
+	if !nextUpdate.IsZero() && nextUpdate.Before(expiresAt) {
+		expiresAt = nextUpdate
+	}
+	return expiresAt
+}
+
+// checkRevocationFreshness rejects an OCSP response or CRL that is not valid yet or was due to be
+// replaced by a newer one
+func checkRevocationFreshness(thisUpdate, nextUpdate, now time.Time) error {
+	if thisUpdate.After(now.Add(revocationClockSkew)) {
+		return fmt.Errorf("not valid before %s", thisUpdate.UTC().Format(time.RFC3339))
+	}
+	if !nextUpdate.IsZero() && nextUpdate.Add(revocationClockSkew).Before(now) {
+		return fmt.Errorf("stale, next update was due at %s", nextUpdate.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// checkRevocation determines whether leaf was revoked by its issuer. The OCSP response stapled
+// to the handshake is used first, then a cached status, then the OCSP responders and CRL
+// distribution points of the certificate, all within the deadline of ctx
+func (c *SSLChecker) checkRevocation(ctx context.Context, state tls.ConnectionState, leaf, issuer *x509.Certificate) RevocationResult {
+	if issuer == nil {
+		return RevocationResult{Status: RevocationUnknown, Error: errors.New("issuer certificate not available")}
+	}
+
+	var errs []error
+
+	if len(state.OCSPResponse) > 0 {
+		response, err := ocsp.ParseResponseForCert(state.OCSPResponse, leaf, issuer)
+		if err == nil {
+			err = checkRevocationFreshness(response.ThisUpdate, response.NextUpdate, time.Now())
+		}
+		if err == nil {
+			return ocspRevocationResult(response, RevocationSourceStaple, 0)
+		}
+		errs = append(errs, fmt.Errorf("stapled OCSP response: %w", err))
+	}
+
+	key := revocationCacheKey(leaf, issuer)
+	if result, ok := c.revocations.get(key, time.Now()); ok {
+		return result
+	}
+
+	client := &http.Client{}
+
+	for _, server := range leaf.OCSPServer {
+		startTime := time.Now()
+		response, err := queryOCSP(ctx, client, server, leaf, issuer)
+		if err == nil {
+			result := ocspRevocationResult(response, RevocationSourceOCSP, time.Since(startTime))
+			c.revocations.put(key, result, revocationCacheExpiry(response.NextUpdate, time.Now()))
+			return result
+		}
+		errs = append(errs, fmt.Errorf("OCSP responder %s: %w", server, err))
+	}
+
+	for _, distributionPoint := range leaf.CRLDistributionPoints {
+		startTime := time.Now()
+		result, nextUpdate, err := queryCRL(ctx, client, distributionPoint, leaf, issuer)
+		if err == nil {
+			result.ResponseTime = time.Since(startTime)
+			c.revocations.put(key, result, revocationCacheExpiry(nextUpdate, time.Now()))
+			return result
+		}
+		errs = append(errs, fmt.Errorf("CRL %s: %w", distributionPoint, err))
+	}
+
+	if len(errs) == 0 {
+		return RevocationResult{Status: RevocationUnknown, Error: errors.New("no OCSP responder or CRL distribution point")}
+	}
+	result := RevocationResult{Status: RevocationUnknown, Error: errors.Join(errs...)}
+	if ctx.Err() == nil {
+		// The sources did answer, or failed on their own: do not query them on every check
+		c.revocations.put(key, result, time.Now().Add(revocationRetryInterval))
+	}
+	return result
+}
+
+// queryOCSP asks an OCSP responder for the status of leaf
+func queryOCSP(ctx context.Context, client *http.Client, server string, leaf, issuer *x509.Certificate) (*ocsp.Response, error) {
+	request, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server, bytes.NewReader(request))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/ocsp-request")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxOCSPResponseSize))
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := ocsp.ParseResponseForCert(body, leaf, issuer)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRevocationFreshness(response.ThisUpdate, response.NextUpdate, time.Now()); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// queryCRL downloads a CRL signed by issuer and looks up the serial number of leaf
+// It also returns when the CRL is due to be updated, zero when it does not say
+func queryCRL(ctx context.Context, client *http.Client, distributionPoint string, leaf, issuer *x509.Certificate) (RevocationResult, time.Time, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, distributionPoint, nil)
+	if err != nil {
+		return RevocationResult{}, time.Time{}, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return RevocationResult{}, time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return RevocationResult{}, time.Time{}, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCRLSize))
+	if err != nil {
+		return RevocationResult{}, time.Time{}, err
+	}
+
+	crl, err := x509.ParseRevocationList(body)
+	if err != nil {
+		return RevocationResult{}, time.Time{}, fmt.Errorf("failed to parse CRL: %w", err)
+	}
+	if err := crl.CheckSignatureFrom(issuer); err != nil {
+		return RevocationResult{}, time.Time{}, fmt.Errorf("CRL not signed by the issuer: %w", err)
+	}
+	if err := checkRevocationFreshness(crl.ThisUpdate, crl.NextUpdate, time.Now()); err != nil {
+		return RevocationResult{}, time.Time{}, err
+	}
+
+	for _, entry := range crl.RevokedCertificateEntries {
+		if entry.SerialNumber.Cmp(leaf.SerialNumber) == 0 {
+			return RevocationResult{
+				Status:    RevocationRevoked,
+				Source:    RevocationSourceCRL,
+				RevokedAt: entry.RevocationTime,
+				Reason:    revocationReasonName(entry.ReasonCode),
+			}, crl.NextUpdate, nil
+		}
+	}
+
+	return RevocationResult{Status: RevocationGood, Source: RevocationSourceCRL}, crl.NextUpdate, nil
+}
+
+// ocspRevocationResult converts an OCSP response into a revocation result
+func ocspRevocationResult(response *ocsp.Response, source string, responseTime time.Duration) RevocationResult {
+	result := RevocationResult{Source: source, ResponseTime: responseTime}
+	switch response.Status {
+	case ocsp.Good:
+		result.Status = RevocationGood
+	case ocsp.Revoked:
+		result.Status = RevocationRevoked
+		result.RevokedAt = response.RevokedAt
+		result.Reason = revocationReasonName(response.RevocationReason)
+	default:
+		result.Status = RevocationUnknown
+		result.Error = errors.New("responder does not know the certificate")
+	}
+	return result
+}
+
+// revocationReasonName returns the RFC 5280 name of a CRL reason code
+func revocationReasonName(code int) string {
+	switch code {
+	case ocsp.Unspecified:
+		return "unspecified"
+	case ocsp.KeyCompromise:
+		return "keyCompromise"
+	case ocsp.CACompromise:
+		return "cACompromise"
+	case ocsp.AffiliationChanged:
+		return "affiliationChanged"
+	case ocsp.Superseded:
+		return "superseded"
+	case ocsp.CessationOfOperation:
+		return "cessationOfOperation"
+	case ocsp.CertificateHold:
+		return "certificateHold"
+	case ocsp.RemoveFromCRL:
+		return "removeFromCRL"
+	case ocsp.PrivilegeWithdrawn:
+		return "privilegeWithdrawn"
+	case ocsp.AACompromise:
+		return "aACompromise"
+	default:
+		return fmt.Sprintf("reason %d", code)
+	}
+}
+
+// certificateIssuer returns the certificate that issued leaf: the next certificate presented by the
+// server, or the one found while verifying the chain
+func certificateIssuer(state tls.ConnectionState) *x509.Certificate {
+	if len(state.PeerCertificates) == 0 {
+		return nil
+	}
+	leaf := state.PeerCertificates[0]
+	if len(state.PeerCertificates) > 1 && bytes.Equal(state.PeerCertificates[1].RawSubject, leaf.RawIssuer) {
+		return state.PeerCertificates[1]
+	}
+	for _, chain := range state.VerifiedChains {
+		if len(chain) > 1 {
+			return chain[1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package executor
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// newRevocableCertificate issues a leaf for 127.0.0.1 that points to the given OCSP responder and CRL
+func newRevocableCertificate(t *testing.T, issuer *testCertificate, ocspURL, crlURL string) *testCertificate {
+	t.Helper()
+
+	key := generateECDSAKey(t)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(90 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	if ocspURL != "" {
+		template.OCSPServer = []string{ocspURL}
+	}
+	if crlURL != "" {
+		template.CRLDistributionPoints = []string{crlURL}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer.cert, key.Public(), issuer.key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+	return &testCertificate{cert: cert, key: key}
+}
+
+// newOCSPResponse signs an OCSP response for leaf with the issuer key
+func newOCSPResponse(t *testing.T, issuer *testCertificate, serial *big.Int, status int, revokedAt time.Time) []byte {
+	t.Helper()
+	return newOCSPResponseValidUntil(t, issuer, serial, status, revokedAt, time.Now().Add(24*time.Hour))
+}
+
+// newOCSPResponseValidUntil signs an OCSP response for leaf whose next update is due at nextUpdate
+func newOCSPResponseValidUntil(t *testing.T, issuer *testCertificate, serial *big.Int, status int, revokedAt, nextUpdate time.Time) []byte {
+	t.Helper()
+
+	response, err := ocsp.CreateResponse(issuer.cert, issuer.cert, ocsp.Response{
+		Status:           status,
+		SerialNumber:     serial,
+		ThisUpdate:       nextUpdate.Add(-25 * time.Hour),
+		NextUpdate:       nextUpdate,
+		RevokedAt:        revokedAt,
+		RevocationReason: ocsp.KeyCompromise,
+	}, issuer.key)
+	if err != nil {
+		t.Fatalf("Failed to create OCSP response: %v", err)
+	}
+	return response
+}
+
+// startOCSPResponder answers every OCSP request with the given status
+func startOCSPResponder(t *testing.T, issuer *testCertificate, status int, revokedAt time.Time) string {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		request, err := ocsp.ParseRequest(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/ocsp-response")
+		w.Write(newOCSPResponse(t, issuer, request.SerialNumber, status, revokedAt))
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL
+}
+
+func TestSSLChecker_CheckSSL_OCSPResponder(t *testing.T) {
+	root := newTestCertificate(t, "Test Root", time.Now().Add(365*24*time.Hour), true, generateECDSAKey(t), nil)
+	roots := x509.NewCertPool()
+	roots.AddCert(root.cert)
+	checker := &SSLChecker{timeout: 5 * time.Second, rootCAs: roots}
+
+	t.Run("good", func(t *testing.T) {
+		leaf := newRevocableCertificate(t, root, startOCSPResponder(t, root, ocsp.Good, time.Time{}), "")
+		result := checker.CheckSSL(context.Background(), startTLSChainServer(t, leaf, root, 0))
+
+		if !result.Valid || result.Error != nil {
+			t.Fatalf("Expected valid certificate, got valid=%v error=%v", result.Valid, result.Error)
+		}
+		if result.Revocation.Status != RevocationGood || result.Revocation.Source != RevocationSourceOCSP {
+			t.Errorf("Expected good status from OCSP, got %q from %q (error: %v)", result.Revocation.Status, result.Revocation.Source, result.Revocation.Error)
+		}
+		if result.Revocation.ResponseTime <= 0 {
+			t.Errorf("Expected responder response time to be recorded")
+		}
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		revokedAt := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+		leaf := newRevocableCertificate(t, root, startOCSPResponder(t, root, ocsp.Revoked, revokedAt), "")
+		result := checker.CheckSSL(context.Background(), startTLSChainServer(t, leaf, root, 0))
+
+		if result.Valid || result.Error == nil {
+			t.Errorf("Expected revoked certificate to be invalid, got valid=%v", result.Valid)
+		}
+		if result.Revocation.Status != RevocationRevoked {
+			t.Fatalf("Expected revoked status, got %q (error: %v)", result.Revocation.Status, result.Revocation.Error)
+		}
+		if !result.Revocation.RevokedAt.Equal(revokedAt) || result.Revocation.Reason != "keyCompromise" {
+			t.Errorf("Expected revocation at %v for keyCompromise, got %v for %s", revokedAt, result.Revocation.RevokedAt, result.Revocation.Reason)
+		}
+	})
+}
+
+func TestSSLChecker_CheckRevocation(t *testing.T) {
+	root := newTestCertificate(t, "Test Root", time.Now().Add(365*24*time.Hour), true, generateECDSAKey(t), nil)
+	checker := &SSLChecker{timeout: 5 * time.Second}
+
+	t.Run("stapled response", func(t *testing.T) {
+		leaf := newRevocableCertificate(t, root, "http://127.0.0.1:1/unreachable", "")
+		state := tls.ConnectionState{OCSPResponse: newOCSPResponse(t, root, leaf.cert.SerialNumber, ocsp.Good, time.Time{})}
+
+		result := checker.checkRevocation(context.Background(), state, leaf.cert, root.cert)
+		if result.Status != RevocationGood || result.Source != RevocationSourceStaple {
+			t.Errorf("Expected good status from the staple, got %q from %q (error: %v)", result.Status, result.Source, result.Error)
+		}
+	})
+
+	t.Run("CRL fallback", func(t *testing.T) {
+		var crl []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(crl)
+		}))
+		defer server.Close()
+
+		leaf := newRevocableCertificate(t, root, "", server.URL)
+		other := newRevocableCertificate(t, root, "", server.URL)
+		var err error
+		crl, err = x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+			Number:     big.NewInt(1),
+			ThisUpdate: time.Now().Add(-time.Hour),
+			NextUpdate: time.Now().Add(24 * time.Hour),
+			RevokedCertificateEntries: []x509.RevocationListEntry{
+				{SerialNumber: leaf.cert.SerialNumber, RevocationTime: time.Now().Add(-time.Hour), ReasonCode: ocsp.Superseded},
+			},
+		}, root.cert, root.key)
+		if err != nil {
+			t.Fatalf("Failed to create CRL: %v", err)
+		}
+
+		result := checker.checkRevocation(context.Background(), tls.ConnectionState{}, leaf.cert, root.cert)
+		if result.Status != RevocationRevoked || result.Source != RevocationSourceCRL || result.Reason != "superseded" {
+			t.Errorf("Expected revoked (superseded) from CRL, got %q from %q, reason %q (error: %v)", result.Status, result.Source, result.Reason, result.Error)
+		}
+
+		result = checker.checkRevocation(context.Background(), tls.ConnectionState{}, other.cert, root.cert)
+		if result.Status != RevocationGood {
+			t.Errorf("Expected good status for a certificate not in the CRL, got %q (error: %v)", result.Status, result.Error)
+		}
+	})
+
+	t.Run("no revocation source", func(t *testing.T) {
+		leaf := newRevocableCertificate(t, root, "", "")
+
+		result := checker.checkRevocation(context.Background(), tls.ConnectionState{}, leaf.cert, root.cert)
+		if result.Status != RevocationUnknown || result.Error == nil {
+			t.Errorf("Expected unknown status with an error, got %q", result.Status)
+		}
+	})
+}
+
+func TestSSLChecker_CheckRevocation_Cache(t *testing.T) {
+	root := newTestCertificate(t, "Test Root", time.Now().Add(365*24*time.Hour), true, generateECDSAKey(t), nil)
+	checker := NewSSLChecker(5 * time.Second)
+
+	var queries atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		request, err := ocsp.ParseRequest(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Write(newOCSPResponse(t, root, request.SerialNumber, ocsp.Good, time.Time{}))
+	}))
+	defer server.Close()
+
+	leaf := newRevocableCertificate(t, root, server.URL, "")
+	for i := 0; i < 3; i++ {
+		result := checker.WithClientCertificate(nil).checkRevocation(context.Background(), tls.ConnectionState{}, leaf.cert, root.cert)
+		if result.Status != RevocationGood || result.Source != RevocationSourceOCSP {
+			t.Fatalf("Expected good status from OCSP, got %q from %q (error: %v)", result.Status, result.Source, result.Error)
+		}
+	}
+	if queries.Load() != 1 {
+		t.Errorf("Expected the responder to be queried once, got %d queries", queries.Load())
+	}
+
+	// Another certificate of the same issuer is looked up on its own
+	other := newRevocableCertificate(t, root, server.URL, "")
+	checker.checkRevocation(context.Background(), tls.ConnectionState{}, other.cert, root.cert)
+	if queries.Load() != 2 {
+		t.Errorf("Expected a query for the other certificate, got %d queries", queries.Load())
+	}
+}
+
+func TestSSLChecker_CheckRevocation_Stale(t *testing.T) {
+	root := newTestCertificate(t, "Test Root", time.Now().Add(365*24*time.Hour), true, generateECDSAKey(t), nil)
+	checker := &SSLChecker{timeout: 5 * time.Second}
+	expired := time.Now().Add(-time.Hour)
+
+	t.Run("stapled response", func(t *testing.T) {
+		leaf := newRevocableCertificate(t, root, "", "")
+		state := tls.ConnectionState{OCSPResponse: newOCSPResponseValidUntil(t, root, leaf.cert.SerialNumber, ocsp.Good, time.Time{}, expired)}
+
+		result := checker.checkRevocation(context.Background(), state, leaf.cert, root.cert)
+		if result.Status != RevocationUnknown || result.Error == nil || !strings.Contains(result.Error.Error(), "stale") {
+			t.Errorf("Expected a stale staple to be rejected, got %q (error: %v)", result.Status, result.Error)
+		}
+	})
+
+	t.Run("responder", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			request, _ := ocsp.ParseRequest(body)
+			w.Write(newOCSPResponseValidUntil(t, root, request.SerialNumber, ocsp.Good, time.Time{}, expired))
+		}))
+		defer server.Close()
+		leaf := newRevocableCertificate(t, root, server.URL, "")
+
+		result := checker.checkRevocation(context.Background(), tls.ConnectionState{}, leaf.cert, root.cert)
+		if result.Status != RevocationUnknown || result.Error == nil || !strings.Contains(result.Error.Error(), "stale") {
+			t.Errorf("Expected a stale OCSP response to be rejected, got %q (error: %v)", result.Status, result.Error)
+		}
+	})
+
+	t.Run("CRL", func(t *testing.T) {
+		crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+			Number:     big.NewInt(1),
+			ThisUpdate: expired.Add(-24 * time.Hour),
+			NextUpdate: expired,
+		}, root.cert, root.key)
+		if err != nil {
+			t.Fatalf("Failed to create CRL: %v", err)
+		}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(crl)
+		}))
+		defer server.Close()
+		leaf := newRevocableCertificate(t, root, "", server.URL)
+
+		result := checker.checkRevocation(context.Background(), tls.ConnectionState{}, leaf.cert, root.cert)
+		if result.Status != RevocationUnknown || result.Error == nil || !strings.Contains(result.Error.Error(), "stale") {
+			t.Errorf("Expected a stale CRL to be rejected, got %q (error: %v)", result.Status, result.Error)
+		}
+	})
+}
+
+func TestSSLChecker_CheckRevocation_Deadline(t *testing.T) {
+	root := newTestCertificate(t, "Test Root", time.Now().Add(365*24*time.Hour), true, generateECDSAKey(t), nil)
+	checker := NewSSLChecker(5 * time.Second)
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	leaf := newRevocableCertificate(t, root, server.URL, server.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	startTime := time.Now()
+	result := checker.checkRevocation(ctx, tls.ConnectionState{}, leaf.cert, root.cert)
+	if elapsed := time.Since(startTime); elapsed > 2*time.Second {
+		t.Errorf("Expected the lookup to stop at the deadline, took %v", elapsed)
+	}
+	if result.Status != RevocationUnknown {
+		t.Errorf("Expected unknown status, got %q", result.Status)
+	}
+
+	// A lookup cut short by the deadline is tried again on the next check
+	if _, ok := checker.revocations.get(revocationCacheKey(leaf.cert, root.cert), time.Now()); ok {
+		t.Errorf("Expected no cached status after the deadline")
+	}
+}
@@ -1,38 +1,103 @@
 package executor
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/url"
+	"os"
+	"strings"
 	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+)
+
+const (
+	// minRSAKeyBits is the smallest RSA key size not reported as weak
+	minRSAKeyBits = 2048
+	// minECDSAKeyBits is the smallest ECDSA key size not reported as weak
+	minECDSAKeyBits = 256
 )
 
 // SSLCheckResult represents the result of an SSL certificate check
 type SSLCheckResult struct {
 	Valid      bool
-	ExpiresAt  time.Time
+	ExpiresAt  time.Time // earliest expiry in the presented chain
 	DaysUntil  int
 	Error      error
 	Issuer     string
 	Subject    string
+
+	Chain         []CertificateInfo // certificates presented by the server, leaf first
+	Fingerprint   string            // SHA-256 fingerprint of the leaf certificate
+	HostnameMatch bool
+	TLSVersion    string
+	CipherSuite   string
+	Weaknesses    []string // reasons the TLS setup is considered weak, empty when it is not
+	Revocation    RevocationResult
+}
+
+// CertificateInfo describes a single certificate of the presented chain
+type CertificateInfo struct {
+	Subject            string
+	Issuer             string
+	SANs               []string
+	NotBefore          time.Time
+	NotAfter           time.Time
+	KeyType            string // RSA, ECDSA or Ed25519
+	KeyBits            int
+	SignatureAlgorithm string
+	Fingerprint        string // SHA-256, hex encoded
 }
 
 // SSLChecker performs SSL certificate checks
 type SSLChecker struct {
-	timeout time.Duration
+	timeout           time.Duration
+	rootCAs           *x509.CertPool   // trusted roots, system roots when nil
+	clientCertificate *tls.Certificate // presented to servers that require mutual TLS
+	proxy             *entities.ProxyConfig
+	revocations       *revocationCache // revocation statuses shared by the copies of the checker, none when nil
 }
 
 // NewSSLChecker creates a new SSL checker with the specified timeout
+// The timeout covers a whole check: connecting, the handshake and the revocation lookup
 func NewSSLChecker(timeout time.Duration) *SSLChecker {
 	return &SSLChecker{
-		timeout: timeout,
+		timeout:     timeout,
+		revocations: newRevocationCache(),
 	}
 }
 
+// WithClientCertificate returns a copy of the checker that presents certificate during the handshake
+func (c *SSLChecker) WithClientCertificate(certificate *tls.Certificate) *SSLChecker {
+	checker := *c
+	checker.clientCertificate = certificate
+	return &checker
+}
+
+// WithProxy returns a copy of the checker that connects through the proxy in cfg, directly when nil
+func (c *SSLChecker) WithProxy(cfg *entities.ProxyConfig) *SSLChecker {
+	checker := *c
+	checker.proxy = cfg
+	return &checker
+}
+
 // CheckSSL checks the SSL certificate for the given hostname
-// It returns certificate expiry information and validity status
-func (c *SSLChecker) CheckSSL(hostname string) SSLCheckResult {
+// It inspects the whole presented chain and the negotiated connection, and reports
+// expiry information, validity status and weaknesses of the TLS setup
+func (c *SSLChecker) CheckSSL(ctx context.Context, hostname string) SSLCheckResult {
 	// Parse URL to extract hostname and port
 	parsedURL, err := url.Parse(hostname)
 	if err != nil {
@@ -45,35 +110,52 @@ func (c *SSLChecker) CheckSSL(hostname string) SSLCheckResult {
 	// Extract host and port
 	host := parsedURL.Hostname()
 	port := parsedURL.Port()
-	
+
 	// Default to port 443 if not specified
 	if port == "" {
 		port = "443"
 	}
 
-	// Set up dialer with timeout
-	dialer := &net.Dialer{
-		Timeout: c.timeout,
+	return c.CheckEndpoint(ctx, host, port, "", "")
+}
+
+// CheckEndpoint checks the certificate served on host:port
+// serverName is sent as SNI and verified against the certificate, host when empty
+// startTLS upgrades a plaintext connection first: "smtp" or "postgres", direct TLS when empty
+func (c *SSLChecker) CheckEndpoint(ctx context.Context, host, port, serverName, startTLS string) SSLCheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	if serverName == "" {
+		serverName = host
 	}
 
 	// First, try to connect with proper certificate verification
+	// Legacy protocol versions and cipher suites are offered so that a server still
+	// accepting them is reported as weak instead of failing the handshake
 	conf := &tls.Config{
 		InsecureSkipVerify: false,
-		ServerName:         host,
+		ServerName:         serverName,
+		RootCAs:            c.rootCAs,
+		MinVersion:         tls.VersionTLS10,
+		CipherSuites:       allCipherSuites(),
+	}
+	if c.clientCertificate != nil {
+		conf.Certificates = []tls.Certificate{*c.clientCertificate}
 	}
 
 	// Track whether the verified handshake succeeded
 	verifiedHandshake := false
 	var verificationErr error
 
-	conn, err := tls.DialWithDialer(dialer, "tcp", net.JoinHostPort(host, port), conf)
+	conn, err := c.handshake(ctx, net.JoinHostPort(host, port), startTLS, conf)
 	if err != nil {
 		// Verified handshake failed - store the error
 		verificationErr = err
-		
+
 		// Try again with InsecureSkipVerify to get certificate details for diagnostics
 		conf.InsecureSkipVerify = true
-		conn, err = tls.DialWithDialer(dialer, "tcp", net.JoinHostPort(host, port), conf)
+		conn, err = c.handshake(ctx, net.JoinHostPort(host, port), startTLS, conf)
 		if err != nil {
 			return SSLCheckResult{
 				Valid: false,
@@ -86,9 +168,21 @@ func (c *SSLChecker) CheckSSL(hostname string) SSLCheckResult {
 	}
 	defer conn.Close()
 
-	// Get connection state
-	state := conn.ConnectionState()
-	
+	return c.connectionResult(ctx, conn.ConnectionState(), serverName, verifiedHandshake, verificationErr)
+}
+
+// InspectConnection checks the certificate of a TLS session opened by another checker, e.g. after
+// STARTTLS, verifying the presented chain against serverName as CheckEndpoint does
+func (c *SSLChecker) InspectConnection(ctx context.Context, state tls.ConnectionState, serverName string) SSLCheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	err := verifyPeerCertificates(state, serverName, c.rootCAs)
+	return c.connectionResult(ctx, state, serverName, err == nil, err)
+}
+
+// connectionResult describes the certificate chain and posture of a negotiated connection
+func (c *SSLChecker) connectionResult(ctx context.Context, state tls.ConnectionState, serverName string, verified bool, verificationErr error) SSLCheckResult {
 	// Check if we have any certificates
 	if len(state.PeerCertificates) == 0 {
 		return SSLCheckResult{
@@ -100,33 +194,269 @@ func (c *SSLChecker) CheckSSL(hostname string) SSLCheckResult {
 	// Get the leaf certificate (the server's certificate)
 	cert := state.PeerCertificates[0]
 
+	// Only mark as valid if the verified handshake succeeded
+	// This ensures we don't mark certificates as valid when they failed TLS verification
+	result := SSLCheckResult{
+		Valid:         verified,
+		Error:         verificationErr,
+		Issuer:        cert.Issuer.CommonName,
+		Subject:       cert.Subject.CommonName,
+		Fingerprint:   certificateFingerprint(cert),
+		HostnameMatch: cert.VerifyHostname(serverName) == nil,
+		TLSVersion:    tls.VersionName(state.Version),
+		CipherSuite:   tls.CipherSuiteName(state.CipherSuite),
+	}
+
+	// An intermediate that expires before the leaf breaks the chain first
+	result.ExpiresAt = cert.NotAfter
+	for _, chainCert := range state.PeerCertificates {
+		result.Chain = append(result.Chain, describeCertificate(chainCert))
+		if chainCert.NotAfter.Before(result.ExpiresAt) {
+			result.ExpiresAt = chainCert.NotAfter
+		}
+	}
+
 	// Calculate days until expiry
-	daysUntil := int(time.Until(cert.NotAfter).Hours() / 24)
+	result.DaysUntil = int(time.Until(result.ExpiresAt).Hours() / 24)
 
-	// Extract issuer and subject information
-	issuer := cert.Issuer.CommonName
-	subject := cert.Subject.CommonName
+	result.Weaknesses = tlsWeaknesses(state)
 
-	// Only mark as valid if the verified handshake succeeded
-	// This ensures we don't mark certificates as valid when they failed TLS verification
-	if !verifiedHandshake {
-		return SSLCheckResult{
-			Valid:     false,
-			ExpiresAt: cert.NotAfter,
-			DaysUntil: daysUntil,
-			Error:     verificationErr,
-			Issuer:    issuer,
-			Subject:   subject,
+	// A revoked certificate is invalid even if the chain verifies
+	result.Revocation = c.checkRevocation(ctx, state, cert, certificateIssuer(state))
+	if result.Revocation.Status == RevocationRevoked {
+		result.Valid = false
+		result.Error = fmt.Errorf("certificate revoked at %s (%s)", result.Revocation.RevokedAt.UTC().Format(time.RFC3339), result.Revocation.Reason)
+	}
+
+	return result
+}
+
+// verifyPeerCertificates verifies the chain presented on a connection established without
+// verification, as the TLS handshake would have: against roots (system roots when nil) and serverName
+func verifyPeerCertificates(state tls.ConnectionState, serverName string, roots *x509.CertPool) error {
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("no certificates found")
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+		DNSName:       serverName,
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	return err
+}
+
+// handshake connects to address, negotiates STARTTLS when requested and completes the TLS handshake
+// before the deadline of ctx
+func (c *SSLChecker) handshake(ctx context.Context, address, startTLS string, conf *tls.Config) (*tls.Conn, error) {
+	var rawConn net.Conn
+	var err error
+	dialer := &net.Dialer{Timeout: c.timeout}
+	if c.proxy != nil {
+		rawConn, err = newProxyDialer(c.proxy, dialer).DialContext(ctx, "tcp", address)
+	} else {
+		rawConn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The deadline covers the STARTTLS exchange and the handshake
+	deadline, _ := ctx.Deadline()
+	if err := rawConn.SetDeadline(deadline); err != nil {
+		rawConn.Close()
+		return nil, err
+	}
+
+	switch startTLS {
+	case "":
+	case "smtp":
+		err = startTLSSMTP(rawConn)
+	case "postgres":
+		err = startTLSPostgres(rawConn)
+	default:
+		err = fmt.Errorf("unsupported STARTTLS protocol %q", startTLS)
+	}
+	if err != nil {
+		rawConn.Close()
+		return nil, fmt.Errorf("STARTTLS failed: %w", err)
+	}
+
+	conn := tls.Client(rawConn, conf)
+	if err := conn.HandshakeContext(ctx); err != nil {
+		rawConn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+
+	return conn, nil
+}
+
+// startTLSSMTP reads the SMTP greeting and asks the server to switch to TLS (RFC 3207)
+func startTLSSMTP(conn net.Conn) error {
+	reader := bufio.NewReader(conn)
+	if err := readSMTPReply(reader, "220"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(conn, "EHLO %s\r\n", smtpClientName()); err != nil {
+		return err
+	}
+	if err := readSMTPReply(reader, "250"); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(conn, "STARTTLS\r\n"); err != nil {
+		return err
+	}
+	return readSMTPReply(reader, "220")
+}
+
+// readSMTPReply reads a possibly multi-line SMTP reply and checks its status code
+func readSMTPReply(reader *bufio.Reader, code string) error {
+	_, err := readSMTPLines(reader, code)
+	return err
+}
+
+// readSMTPLines reads a possibly multi-line SMTP reply, checks its status code and
+// returns the text of its lines
+func readSMTPLines(reader *bufio.Reader, code string) ([]string, error) {
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if len(line) < 3 || line[:3] != code {
+			return nil, fmt.Errorf("unexpected reply %q", truncateForError(line))
+		}
+		if len(line) > 4 {
+			lines = append(lines, line[4:])
+		} else {
+			lines = append(lines, "")
+		}
+		// "250-" continues the reply, "250 " ends it
+		if len(line) == 3 || line[3] != '-' {
+			return lines, nil
 		}
 	}
+}
+
+// smtpClientName returns the name announced in EHLO
+func smtpClientName() string {
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		return hostname
+	}
+	return "localhost"
+}
+
+// postgresSSLRequestCode is the protocol code of a PostgreSQL SSLRequest message
+const postgresSSLRequestCode = 80877103
+
+// startTLSPostgres sends a PostgreSQL SSLRequest and checks the server accepts it
+func startTLSPostgres(conn net.Conn) error {
+	request := make([]byte, 8)
+	binary.BigEndian.PutUint32(request[0:4], 8)
+	binary.BigEndian.PutUint32(request[4:8], postgresSSLRequestCode)
+	if _, err := conn.Write(request); err != nil {
+		return err
+	}
+
+	reply := make([]byte, 1)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return err
+	}
+	if reply[0] != 'S' {
+		return errors.New("server does not support SSL")
+	}
+	return nil
+}
 
-	// Verified handshake succeeded - certificate is valid
-	return SSLCheckResult{
-		Valid:     true,
-		ExpiresAt: cert.NotAfter,
-		DaysUntil: daysUntil,
-		Error:     nil,
-		Issuer:    issuer,
-		Subject:   subject,
+// describeCertificate extracts the details recorded for a certificate
+func describeCertificate(cert *x509.Certificate) CertificateInfo {
+	info := CertificateInfo{
+		Subject:            cert.Subject.CommonName,
+		Issuer:             cert.Issuer.CommonName,
+		NotBefore:          cert.NotBefore,
+		NotAfter:           cert.NotAfter,
+		SignatureAlgorithm: cert.SignatureAlgorithm.String(),
+		Fingerprint:        certificateFingerprint(cert),
+	}
+	if info.Subject == "" {
+		info.Subject = cert.Subject.String()
+	}
+
+	info.SANs = append(info.SANs, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		info.SANs = append(info.SANs, ip.String())
+	}
+
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		info.KeyType = "RSA"
+		info.KeyBits = key.N.BitLen()
+	case *ecdsa.PublicKey:
+		info.KeyType = "ECDSA"
+		info.KeyBits = key.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		info.KeyType = "Ed25519"
+		info.KeyBits = 256
+	default:
+		info.KeyType = cert.PublicKeyAlgorithm.String()
+	}
+
+	return info
+}
+
+// tlsWeaknesses lists the weak parts of a negotiated connection: legacy protocol
+// versions, insecure cipher suites, short keys and SHA-1/MD5 signatures
+func tlsWeaknesses(state tls.ConnectionState) []string {
+	var weaknesses []string
+
+	if state.Version < tls.VersionTLS12 {
+		weaknesses = append(weaknesses, fmt.Sprintf("legacy protocol %s", tls.VersionName(state.Version)))
+	}
+	for _, suite := range tls.InsecureCipherSuites() {
+		if suite.ID == state.CipherSuite {
+			weaknesses = append(weaknesses, fmt.Sprintf("insecure cipher suite %s", suite.Name))
+		}
+	}
+
+	for _, cert := range state.PeerCertificates {
+		info := describeCertificate(cert)
+		if (info.KeyType == "RSA" && info.KeyBits < minRSAKeyBits) || (info.KeyType == "ECDSA" && info.KeyBits < minECDSAKeyBits) {
+			weaknesses = append(weaknesses, fmt.Sprintf("%d-bit %s key in certificate %q", info.KeyBits, info.KeyType, info.Subject))
+		}
+
+		// Self-signed roots are trusted by their presence in the store, their signature does not matter
+		selfSigned := bytes.Equal(cert.RawSubject, cert.RawIssuer)
+		switch cert.SignatureAlgorithm {
+		case x509.MD2WithRSA, x509.MD5WithRSA, x509.SHA1WithRSA, x509.DSAWithSHA1, x509.ECDSAWithSHA1:
+			if !selfSigned {
+				weaknesses = append(weaknesses, fmt.Sprintf("%s signature on certificate %q", info.SignatureAlgorithm, info.Subject))
+			}
+		}
+	}
+
+	return weaknesses
+}
+
+// certificateFingerprint returns the SHA-256 fingerprint of a certificate
+func certificateFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// allCipherSuites returns the IDs of every cipher suite Go implements, including
+// insecure ones, so the cipher chosen by the server can be inspected
+func allCipherSuites() []uint16 {
+	var ids []uint16
+	for _, suite := range tls.CipherSuites() {
+		ids = append(ids, suite.ID)
+	}
+	for _, suite := range tls.InsecureCipherSuites() {
+		ids = append(ids, suite.ID)
 	}
+	return ids
 }
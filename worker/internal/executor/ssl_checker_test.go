@@ -1,6 +1,22 @@
 package executor
 
 import (
+	"bufio"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"strings"
 	"testing"
 	"time"
 )
@@ -32,7 +48,7 @@ func TestSSLChecker_CheckSSL(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := checker.CheckSSL(tt.hostname)
+			result := checker.CheckSSL(context.Background(), tt.hostname)
 			
 			if tt.wantErr && result.Error == nil {
 				t.Errorf("CheckSSL() expected error but got none")
@@ -59,3 +75,288 @@ func TestSSLChecker_CheckSSL(t *testing.T) {
 		})
 	}
 }
+
+// testCertificate is a certificate and key issued by newTestCertificate
+type testCertificate struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+// newTestCertificate issues a certificate signed by parent, or a self-signed one when parent is nil
+func newTestCertificate(t *testing.T, commonName string, notAfter time.Time, isCA bool, key crypto.Signer, parent *testCertificate) *testCertificate {
+	t.Helper()
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatalf("Failed to generate serial: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	if !isCA {
+		template.DNSNames = []string{commonName}
+		template.IPAddresses = []net.IP{net.ParseIP("127.0.0.1")}
+	}
+
+	issuer, signer := template, key
+	if parent != nil {
+		issuer, signer = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, key.Public(), signer)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+	return &testCertificate{cert: cert, key: key}
+}
+
+// newTestClientCertificate issues a client certificate and returns it with the pool
+// a server needs to verify it
+func newTestClientCertificate(t *testing.T) (*tls.Certificate, *x509.CertPool) {
+	t.Helper()
+
+	ca := newTestCertificate(t, "Test Client CA", time.Now().Add(24*time.Hour), true, generateECDSAKey(t), nil)
+	client := newTestCertificate(t, "monitor-client", time.Now().Add(24*time.Hour), false, generateECDSAKey(t), ca)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	return &tls.Certificate{Certificate: [][]byte{client.cert.Raw}, PrivateKey: client.key}, pool
+}
+
+// startTLSChainServer serves the leaf and intermediate over TLS and returns its https URL
+func startTLSChainServer(t *testing.T, leaf, intermediate *testCertificate, maxVersion uint16) string {
+	t.Helper()
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{
+			Certificate: [][]byte{leaf.cert.Raw, intermediate.cert.Raw},
+			PrivateKey:  leaf.key,
+		}},
+		MinVersion: tls.VersionTLS10,
+		MaxVersion: maxVersion,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				conn.(*tls.Conn).Handshake()
+			}()
+		}
+	}()
+
+	return "https://" + listener.Addr().String()
+}
+
+func generateECDSAKey(t *testing.T) crypto.Signer {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	return key
+}
+
+func TestSSLChecker_CheckSSL_Chain(t *testing.T) {
+	root := newTestCertificate(t, "Test Root", time.Now().Add(10*365*24*time.Hour), true, generateECDSAKey(t), nil)
+	// The intermediate expires long before the leaf
+	intermediate := newTestCertificate(t, "Test Intermediate", time.Now().Add(5*24*time.Hour), true, generateECDSAKey(t), root)
+	leaf := newTestCertificate(t, "localhost", time.Now().Add(90*24*time.Hour), false, generateECDSAKey(t), intermediate)
+	serverURL := startTLSChainServer(t, leaf, intermediate, 0)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root.cert)
+	checker := &SSLChecker{timeout: 5 * time.Second, rootCAs: roots}
+
+	result := checker.CheckSSL(context.Background(), serverURL)
+	if !result.Valid || result.Error != nil {
+		t.Fatalf("Expected valid certificate, got valid=%v error=%v", result.Valid, result.Error)
+	}
+	if !result.ExpiresAt.Equal(intermediate.cert.NotAfter) {
+		t.Errorf("Expected expiry of the intermediate %v, got %v", intermediate.cert.NotAfter, result.ExpiresAt)
+	}
+	if result.DaysUntil > 5 {
+		t.Errorf("Expected DaysUntil <= 5, got %d", result.DaysUntil)
+	}
+	if len(result.Chain) != 2 {
+		t.Fatalf("Expected 2 certificates in chain, got %d", len(result.Chain))
+	}
+	if result.Chain[0].Subject != "localhost" || result.Chain[1].Subject != "Test Intermediate" {
+		t.Errorf("Unexpected chain subjects: %q, %q", result.Chain[0].Subject, result.Chain[1].Subject)
+	}
+	if result.Chain[0].KeyType != "ECDSA" || result.Chain[0].KeyBits != 256 {
+		t.Errorf("Expected 256-bit ECDSA key, got %d-bit %s", result.Chain[0].KeyBits, result.Chain[0].KeyType)
+	}
+	if result.Chain[0].SignatureAlgorithm != "ECDSA-SHA256" {
+		t.Errorf("Expected ECDSA-SHA256 signature, got %s", result.Chain[0].SignatureAlgorithm)
+	}
+	if len(result.Chain[0].SANs) != 2 || result.Chain[0].SANs[1] != "127.0.0.1" {
+		t.Errorf("Expected SANs [localhost 127.0.0.1], got %v", result.Chain[0].SANs)
+	}
+	if result.Fingerprint != result.Chain[0].Fingerprint || len(result.Fingerprint) != 64 {
+		t.Errorf("Expected leaf SHA-256 fingerprint, got %q", result.Fingerprint)
+	}
+	if !result.HostnameMatch {
+		t.Errorf("Expected hostname to match")
+	}
+	if result.TLSVersion != "TLS 1.3" {
+		t.Errorf("Expected TLS 1.3, got %s", result.TLSVersion)
+	}
+	if len(result.Weaknesses) != 0 {
+		t.Errorf("Expected no weaknesses, got %v", result.Weaknesses)
+	}
+}
+
+func TestSSLChecker_CheckSSL_Untrusted(t *testing.T) {
+	root := newTestCertificate(t, "Test Root", time.Now().Add(365*24*time.Hour), true, generateECDSAKey(t), nil)
+	leaf := newTestCertificate(t, "localhost", time.Now().Add(90*24*time.Hour), false, generateECDSAKey(t), root)
+	serverURL := startTLSChainServer(t, leaf, root, 0)
+
+	// System roots do not trust the test root, details are still recorded
+	checker := NewSSLChecker(5 * time.Second)
+	result := checker.CheckSSL(context.Background(), serverURL)
+	if result.Valid {
+		t.Errorf("Expected invalid certificate")
+	}
+	if result.Error == nil {
+		t.Errorf("Expected verification error")
+	}
+	if len(result.Chain) != 2 || result.Fingerprint == "" {
+		t.Errorf("Expected chain details for an untrusted certificate, got %d certificates", len(result.Chain))
+	}
+}
+
+func TestSSLChecker_CheckSSL_Weak(t *testing.T) {
+	weakKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	root := newTestCertificate(t, "Test Root", time.Now().Add(365*24*time.Hour), true, generateECDSAKey(t), nil)
+	leaf := newTestCertificate(t, "localhost", time.Now().Add(90*24*time.Hour), false, weakKey, root)
+	serverURL := startTLSChainServer(t, leaf, root, tls.VersionTLS11)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root.cert)
+	checker := &SSLChecker{timeout: 5 * time.Second, rootCAs: roots}
+
+	result := checker.CheckSSL(context.Background(), serverURL)
+	if result.TLSVersion != "TLS 1.1" {
+		t.Errorf("Expected TLS 1.1, got %q (error: %v)", result.TLSVersion, result.Error)
+	}
+
+	want := []string{"legacy protocol TLS 1.1", `1024-bit RSA key in certificate "localhost"`}
+	for _, weakness := range want {
+		found := false
+		for _, got := range result.Weaknesses {
+			if got == weakness {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected weakness %q, got %v", weakness, result.Weaknesses)
+		}
+	}
+}
+
+// startSTARTTLSServer serves leaf after a plaintext SMTP or PostgreSQL STARTTLS exchange and returns its host and port
+func startSTARTTLSServer(t *testing.T, leaf, intermediate *testCertificate, protocol string) (string, string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	conf := &tls.Config{
+		Certificates: []tls.Certificate{{
+			Certificate: [][]byte{leaf.cert.Raw, intermediate.cert.Raw},
+			PrivateKey:  leaf.key,
+		}},
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				switch protocol {
+				case "smtp":
+					fmt.Fprint(conn, "220 mail.test ESMTP\r\n")
+					if line, _ := reader.ReadString('\n'); !strings.HasPrefix(line, "EHLO ") {
+						return
+					}
+					fmt.Fprint(conn, "250-mail.test\r\n250-PIPELINING\r\n250 STARTTLS\r\n")
+					if line, _ := reader.ReadString('\n'); line != "STARTTLS\r\n" {
+						return
+					}
+					fmt.Fprint(conn, "220 Ready to start TLS\r\n")
+				case "postgres":
+					request := make([]byte, 8)
+					if _, err := io.ReadFull(reader, request); err != nil || binary.BigEndian.Uint32(request[4:]) != 80877103 {
+						return
+					}
+					conn.Write([]byte{'S'})
+				}
+				tls.Server(conn, conf).Handshake()
+			}()
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	return host, port
+}
+
+func TestSSLChecker_CheckEndpoint_STARTTLS(t *testing.T) {
+	root := newTestCertificate(t, "Test Root", time.Now().Add(365*24*time.Hour), true, generateECDSAKey(t), nil)
+	leaf := newTestCertificate(t, "mail.test", time.Now().Add(90*24*time.Hour), false, generateECDSAKey(t), root)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root.cert)
+	checker := &SSLChecker{timeout: 5 * time.Second, rootCAs: roots}
+
+	for _, protocol := range []string{"smtp", "postgres"} {
+		t.Run(protocol, func(t *testing.T) {
+			host, port := startSTARTTLSServer(t, leaf, root, protocol)
+
+			result := checker.CheckEndpoint(context.Background(), host, port, "mail.test", protocol)
+			if !result.Valid || result.Error != nil {
+				t.Fatalf("Expected valid certificate, got valid=%v error=%v", result.Valid, result.Error)
+			}
+			if result.Subject != "mail.test" || !result.HostnameMatch {
+				t.Errorf("Expected matching certificate for mail.test, got %q (match=%v)", result.Subject, result.HostnameMatch)
+			}
+		})
+	}
+
+	t.Run("protocol mismatch", func(t *testing.T) {
+		host, port := startSTARTTLSServer(t, leaf, root, "smtp")
+
+		result := checker.CheckEndpoint(context.Background(), host, port, "mail.test", "postgres")
+		if result.Valid || result.Error == nil {
+			t.Errorf("Expected STARTTLS failure, got valid=%v", result.Valid)
+		}
+	})
+}
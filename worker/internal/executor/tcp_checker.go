@@ -1,52 +1,217 @@
 package executor
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"io"
 	"net"
+	"regexp"
+	"strconv"
 	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+)
+
+const (
+	// maxTCPResponseSize limits how much of the server response is read when matching
+	maxTCPResponseSize = 64 * 1024
+	// maxTCPResponsePreview limits the response stored in check details and error messages
+	maxTCPResponsePreview = 512
 )
 
 // TCPCheckResult represents the result of a TCP health check
 type TCPCheckResult struct {
 	ResponseTime time.Duration
+	Response     []byte // data read from the server, only when an expectation is configured
 	Error        error
 	Success      bool
 }
 
 // TCPChecker performs TCP health checks
-type TCPChecker struct{}
+type TCPChecker struct {
+	rootCAs           *x509.CertPool   // trusted roots for TLS connections, system roots when nil
+	clientCertificate *tls.Certificate // presented for mutual TLS when set
+	proxy             *entities.ProxyConfig
+}
 
 // NewTCPChecker creates a new TCP checker
 func NewTCPChecker() *TCPChecker {
 	return &TCPChecker{}
 }
 
+// WithClientCertificate returns a copy of the checker that presents certificate on TLS connections
+func (c *TCPChecker) WithClientCertificate(certificate *tls.Certificate) *TCPChecker {
+	checker := *c
+	checker.clientCertificate = certificate
+	return &checker
+}
+
+// WithProxy returns a copy of the checker that connects through the proxy in cfg, directly when nil
+func (c *TCPChecker) WithProxy(cfg *entities.ProxyConfig) *TCPChecker {
+	checker := *c
+	checker.proxy = cfg
+	return &checker
+}
+
 // Check performs a TCP health check on the given host and port
-// Returns response time and any error encountered
-func (c *TCPChecker) Check(host string, port int, timeout time.Duration) TCPCheckResult {
+// With useTLS the connection is wrapped in TLS and the handshake must succeed.
+// When cfg sets a payload it is sent after connecting; when it sets an expectation the
+// response must contain the expected string (or match the regex) before the timeout.
+// The response time covers the whole exchange.
+func (c *TCPChecker) Check(ctx context.Context, host string, port int, useTLS bool, timeout time.Duration, cfg *entities.TCPConfig) TCPCheckResult {
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var matcher func([]byte) bool
+	if cfg != nil && cfg.Expect != "" {
+		var err error
+		matcher, err = newResponseMatcher(cfg.Expect, cfg.MatchMode)
+		if err != nil {
+			return TCPCheckResult{Error: err, Success: false}
+		}
+	}
+
 	startTime := time.Now()
 
 	// Create address string
-	address := fmt.Sprintf("%s:%d", host, port)
-
-	// Attempt TCP connection with timeout
-	conn, err := net.DialTimeout("tcp", address, timeout)
-	responseTime := time.Since(startTime)
+	address := net.JoinHostPort(host, strconv.Itoa(port))
 
+	conn, err := c.dial(checkCtx, address, host, useTLS)
 	if err != nil {
 		return TCPCheckResult{
-			ResponseTime: responseTime,
-			Error:        fmt.Errorf("TCP connection failed: %w", err),
+			ResponseTime: time.Since(startTime),
+			Error:        err,
 			Success:      false,
 		}
 	}
+	defer conn.Close()
 
-	// Close connection immediately after successful connection
-	conn.Close()
+	// Unblock reads and writes as soon as the check is cancelled or times out
+	stop := context.AfterFunc(checkCtx, func() {
+		conn.SetDeadline(time.Now())
+	})
+	defer stop()
 
-	return TCPCheckResult{
-		ResponseTime: responseTime,
-		Error:        nil,
-		Success:      true,
+	if cfg != nil && cfg.Send != "" {
+		if _, err := io.WriteString(conn, cfg.Send); err != nil {
+			return TCPCheckResult{
+				ResponseTime: time.Since(startTime),
+				Error:        fmt.Errorf("failed to send payload: %w", err),
+				Success:      false,
+			}
+		}
+	}
+
+	if matcher == nil {
+		return TCPCheckResult{
+			ResponseTime: time.Since(startTime),
+			Error:        nil,
+			Success:      true,
+		}
+	}
+
+	response, err := readTCPResponse(conn, matcher)
+	result := TCPCheckResult{
+		ResponseTime: time.Since(startTime),
+		Response:     response,
+	}
+	if err != nil {
+		if checkCtx.Err() != nil {
+			err = errors.New("timed out waiting for expected response")
+		}
+		result.Error = fmt.Errorf("%w (received %q)", err, PreviewTCPResponse(response))
+		return result
 	}
-}
\ No newline at end of file
+
+	result.Success = true
+	return result
+}
+
+// dial opens a plain or TLS-wrapped connection to address, through the proxy when one is set
+func (c *TCPChecker) dial(ctx context.Context, address, host string, useTLS bool) (net.Conn, error) {
+	var conn net.Conn
+	var err error
+	if c.proxy != nil {
+		conn, err = newProxyDialer(c.proxy, &net.Dialer{}).DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("TCP connection failed: %w", err)
+	}
+	if !useTLS {
+		return conn, nil
+	}
+
+	tlsConn := tls.Client(conn, newClientTLSConfig(host, c.rootCAs, c.clientCertificate))
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("TLS connection failed: %w", err)
+	}
+	return tlsConn, nil
+}
+
+// newClientTLSConfig returns the TLS settings of a checker connection to serverName,
+// presenting clientCertificate when the server requests one
+func newClientTLSConfig(serverName string, rootCAs *x509.CertPool, clientCertificate *tls.Certificate) *tls.Config {
+	config := &tls.Config{ServerName: serverName, RootCAs: rootCAs}
+	if clientCertificate != nil {
+		config.Certificates = []tls.Certificate{*clientCertificate}
+	}
+	return config
+}
+
+// newResponseMatcher builds the matcher of an expected response: the response must contain
+// expect, or match it as a regex with matchMode 'regex'
+func newResponseMatcher(expect, matchMode string) (func([]byte) bool, error) {
+	switch matchMode {
+	case "", "contains":
+		expected := []byte(expect)
+		return func(response []byte) bool {
+			return bytes.Contains(response, expected)
+		}, nil
+	case "regex":
+		re, err := regexp.Compile(expect)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expect regex: %w", err)
+		}
+		return re.Match, nil
+	default:
+		return nil, fmt.Errorf("unsupported match mode %q", matchMode)
+	}
+}
+
+// readTCPResponse reads from conn until the response matches, the server closes
+// the connection, or the response limit is reached
+func readTCPResponse(conn net.Conn, matches func([]byte) bool) ([]byte, error) {
+	var response []byte
+	buf := make([]byte, 4096)
+	for {
+		n, err := conn.Read(buf)
+		response = append(response, buf[:n]...)
+		if matches(response) {
+			return response, nil
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return response, errors.New("connection closed before expected response")
+			}
+			return response, fmt.Errorf("failed to read response: %w", err)
+		}
+		if len(response) >= maxTCPResponseSize {
+			return response, errors.New("expected response not found")
+		}
+	}
+}
+
+// PreviewTCPResponse truncates a response for error messages and check details
+func PreviewTCPResponse(response []byte) string {
+	if len(response) > maxTCPResponsePreview {
+		response = response[:maxTCPResponsePreview]
+	}
+	return string(response)
+}
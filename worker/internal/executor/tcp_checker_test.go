@@ -1,9 +1,17 @@
 package executor
 
 import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"net"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
 )
 
 func TestTCPChecker_Check_Success(t *testing.T) {
@@ -29,7 +37,7 @@ func TestTCPChecker_Check_Success(t *testing.T) {
 	port := addr.Port
 
 	checker := NewTCPChecker()
-	result := checker.Check(host, port, 5*time.Second)
+	result := checker.Check(context.Background(), host, port, false, 5*time.Second, nil)
 
 	if !result.Success {
 		t.Errorf("Expected success=true, got false")
@@ -44,7 +52,7 @@ func TestTCPChecker_Check_Success(t *testing.T) {
 
 func TestTCPChecker_Check_ConnectionRefused(t *testing.T) {
 	checker := NewTCPChecker()
-	result := checker.Check("127.0.0.1", 12345, 1*time.Second) // Assuming this port is not open
+	result := checker.Check(context.Background(), "127.0.0.1", 12345, false, 1*time.Second, nil) // Assuming this port is not open
 
 	if result.Success {
 		t.Errorf("Expected success=false for connection refused, got true")
@@ -59,7 +67,7 @@ func TestTCPChecker_Check_ConnectionRefused(t *testing.T) {
 
 func TestTCPChecker_Check_InvalidHost(t *testing.T) {
 	checker := NewTCPChecker()
-	result := checker.Check("invalid.host.that.does.not.exist", 80, 1*time.Second)
+	result := checker.Check(context.Background(), "invalid.host.that.does.not.exist", 80, false, 1*time.Second, nil)
 
 	if result.Success {
 		t.Errorf("Expected success=false for invalid host, got true")
@@ -67,4 +75,200 @@ func TestTCPChecker_Check_InvalidHost(t *testing.T) {
 	if result.Error == nil {
 		t.Errorf("Expected error for invalid host, got nil")
 	}
-}
\ No newline at end of file
+}
+
+// startLineServer starts a TCP server that answers every received line using respond
+func startLineServer(t *testing.T, respond func(line string) string) (string, int) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if reply := respond(strings.TrimSpace(line)); reply != "" {
+						conn.Write([]byte(reply))
+					}
+				}
+			}(conn)
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+func TestTCPChecker_Check_SendExpect(t *testing.T) {
+	host, port := startLineServer(t, func(line string) string {
+		if line == "PING" {
+			return "+PONG\r\n"
+		}
+		return "-ERR unknown command\r\n"
+	})
+
+	checker := NewTCPChecker()
+
+	tests := []struct {
+		name        string
+		config      *entities.TCPConfig
+		wantSuccess bool
+	}{
+		{"contains match", &entities.TCPConfig{Send: "PING\r\n", Expect: "+PONG"}, true},
+		{"regex match", &entities.TCPConfig{Send: "PING\r\n", Expect: `^\+PO[N]G\r\n$`, MatchMode: "regex"}, true},
+		{"unexpected response", &entities.TCPConfig{Send: "HELLO\r\n", Expect: "+PONG"}, false},
+		{"invalid regex", &entities.TCPConfig{Send: "PING\r\n", Expect: "(", MatchMode: "regex"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := checker.Check(context.Background(), host, port, false, 500*time.Millisecond, tt.config)
+			if result.Success != tt.wantSuccess {
+				t.Errorf("Expected success=%v, got %v (error: %v)", tt.wantSuccess, result.Success, result.Error)
+			}
+			if !tt.wantSuccess && result.Error == nil {
+				t.Errorf("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestTCPChecker_Check_HungServer(t *testing.T) {
+	// The server accepts connections but never answers
+	host, port := startLineServer(t, func(line string) string { return "" })
+
+	checker := NewTCPChecker()
+	startTime := time.Now()
+	result := checker.Check(context.Background(), host, port, false, 300*time.Millisecond, &entities.TCPConfig{Send: "PING\r\n", Expect: "PONG"})
+
+	if result.Success {
+		t.Errorf("Expected failure for a server that does not respond, got success")
+	}
+	if result.Error == nil || !strings.Contains(result.Error.Error(), "timed out") {
+		t.Errorf("Expected timeout error, got: %v", result.Error)
+	}
+	if elapsed := time.Since(startTime); elapsed > 2*time.Second {
+		t.Errorf("Expected check to stop at the timeout, took %v", elapsed)
+	}
+}
+
+func TestTCPChecker_Check_ContextCancelled(t *testing.T) {
+	host, port := startLineServer(t, func(line string) string { return "" })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	checker := NewTCPChecker()
+	startTime := time.Now()
+	result := checker.Check(ctx, host, port, false, 5*time.Second, &entities.TCPConfig{Expect: "READY"})
+
+	if result.Success {
+		t.Errorf("Expected failure after cancellation, got success")
+	}
+	if elapsed := time.Since(startTime); elapsed > 2*time.Second {
+		t.Errorf("Expected check to stop when the context is cancelled, took %v", elapsed)
+	}
+}
+
+func TestTCPChecker_Check_TLS(t *testing.T) {
+	// Borrow the httptest certificate, which is valid for 127.0.0.1
+	httpServer := httptest.NewTLSServer(nil)
+	certificates := httpServer.TLS.Certificates
+	roots := x509.NewCertPool()
+	roots.AddCert(httpServer.Certificate())
+	httpServer.Close()
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: certificates})
+	if err != nil {
+		t.Fatalf("Failed to create TLS test server: %v", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				conn.Write([]byte("220 ready\r\n"))
+				time.Sleep(100 * time.Millisecond)
+			}(conn)
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+
+	checker := &TCPChecker{rootCAs: roots}
+	result := checker.Check(context.Background(), addr.IP.String(), addr.Port, true, 2*time.Second, &entities.TCPConfig{Expect: "220"})
+	if !result.Success {
+		t.Errorf("Expected TLS check to succeed, got: %v", result.Error)
+	}
+
+	// Without the test root the handshake must fail
+	result = NewTCPChecker().Check(context.Background(), addr.IP.String(), addr.Port, true, 2*time.Second, nil)
+	if result.Success {
+		t.Errorf("Expected TLS check with an untrusted certificate to fail")
+	}
+}
+
+func TestTCPChecker_Check_ClientCertificate(t *testing.T) {
+	clientCertificate, clientCAs := newTestClientCertificate(t)
+	httpServer := httptest.NewTLSServer(nil)
+	certificates := httpServer.TLS.Certificates
+	roots := x509.NewCertPool()
+	roots.AddCert(httpServer.Certificate())
+	httpServer.Close()
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: certificates,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create TLS test server: %v", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				// The write fails when the client did not present an accepted certificate
+				conn.Write([]byte("220 ready\r\n"))
+				time.Sleep(100 * time.Millisecond)
+			}(conn)
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	checker := &TCPChecker{rootCAs: roots}
+	expect := &entities.TCPConfig{Expect: "220"}
+
+	result := checker.Check(context.Background(), addr.IP.String(), addr.Port, true, 2*time.Second, expect)
+	if result.Success {
+		t.Errorf("Expected the server to reject a connection without client certificate")
+	}
+
+	result = checker.WithClientCertificate(clientCertificate).Check(context.Background(), addr.IP.String(), addr.Port, true, 2*time.Second, expect)
+	if !result.Success {
+		t.Errorf("Expected success with client certificate, got: %v", result.Error)
+	}
+}
@@ -0,0 +1,251 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+	"github.com/tidwall/gjson"
+)
+
+// transactionVariableRegex matches {{variable}} placeholders in step settings
+var transactionVariableRegex = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// TransactionStepResult represents the result of a single transaction step
+type TransactionStepResult struct {
+	Name         string
+	URL          string
+	StatusCode   int
+	ResponseTime time.Duration
+	Error        error
+	Success      bool
+}
+
+// TransactionCheckResult represents the result of a multi-step HTTP transaction check
+type TransactionCheckResult struct {
+	Steps        []TransactionStepResult
+	FailedStep   int           // 1-based index of the failing step, 0 if all passed
+	StatusCode   int           // status code of the last executed step
+	ResponseTime time.Duration // total time of all executed steps
+	Error        error
+	Success      bool
+}
+
+// TransactionChecker runs an ordered list of HTTP requests as one check
+type TransactionChecker struct {
+	httpChecker *HTTPChecker
+}
+
+// NewTransactionChecker creates a new transaction checker built on the given HTTP checker
+func NewTransactionChecker(httpChecker *HTTPChecker) *TransactionChecker {
+	return &TransactionChecker{httpChecker: httpChecker}
+}
+
+// Check runs the steps in order, sharing cookies and extracted variables between them
+// Execution stops at the first failing step; the timeout applies to the whole transaction
+func (c *TransactionChecker) Check(ctx context.Context, baseURL string, cfg entities.TransactionConfig, timeout time.Duration) TransactionCheckResult {
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if len(cfg.Steps) == 0 {
+		return TransactionCheckResult{Error: errors.New("transaction has no steps"), Success: false}
+	}
+
+	// Every run gets its own cookie jar so sessions never leak between checks
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return TransactionCheckResult{Error: fmt.Errorf("failed to create cookie jar: %w", err), Success: false}
+	}
+	variables := make(map[string]string, len(cfg.Secrets))
+	for name, value := range cfg.Secrets {
+		variables[name] = value
+	}
+
+	// Keywords and assertions are resolved when compared so that failures show the placeholders
+	checker := c.httpChecker.withCookieJar(jar).withPlaceholders(func(value string) string {
+		return substituteVariables(value, variables)
+	})
+
+	result := TransactionCheckResult{}
+	for i, step := range cfg.Steps {
+		stepResult := c.runStep(checkCtx, checker, jar, baseURL, step, variables)
+		result.Steps = append(result.Steps, stepResult)
+		result.ResponseTime += stepResult.ResponseTime
+		result.StatusCode = stepResult.StatusCode
+
+		if !stepResult.Success {
+			result.FailedStep = i + 1
+			result.Error = fmt.Errorf("%s failed: %w", describeStep(step, i), stepResult.Error)
+			return result
+		}
+	}
+
+	result.Success = true
+	return result
+}
+
+// runStep executes a single step and stores its extracted values in variables
+func (c *TransactionChecker) runStep(ctx context.Context, checker *HTTPChecker, jar http.CookieJar, baseURL string, step entities.TransactionStep, variables map[string]string) TransactionStepResult {
+	// The stored URL keeps the placeholders, it is part of the check details
+	stepResult := TransactionStepResult{Name: step.Name, URL: stepDisplayURL(baseURL, step.URL)}
+
+	stepURL, err := resolveStepURL(baseURL, substituteVariables(step.URL, variables))
+	if err != nil {
+		stepResult.Error = err
+		return stepResult
+	}
+
+	remaining := time.Until(deadlineOf(ctx))
+	if remaining <= 0 {
+		stepResult.Error = errors.New("transaction timed out")
+		return stepResult
+	}
+
+	httpConfig := substituteHTTPConfig(step.HTTPConfig, variables)
+	httpResult := checker.CheckRequest(ctx, stepURL, remaining, step.Keyword, step.ExpectedStatusCodes, &httpConfig)
+	stepResult.StatusCode = httpResult.StatusCode
+	stepResult.ResponseTime = httpResult.ResponseTime
+
+	if !httpResult.Success {
+		stepResult.Error = httpResult.Error
+		var urlErr *url.Error
+		if errors.As(stepResult.Error, &urlErr) {
+			urlErr.URL = stepResult.URL // transport errors quote the request URL
+		}
+		if stepResult.Error == nil {
+			stepResult.Error = fmt.Errorf("unexpected status code %d", httpResult.StatusCode)
+		}
+		return stepResult
+	}
+
+	for _, extract := range step.Extract {
+		value, err := extractStepValue(httpResult, jar, stepURL, extract)
+		if err != nil {
+			stepResult.Error = err
+			return stepResult
+		}
+		variables[extract.Variable] = value
+	}
+
+	stepResult.Success = true
+	return stepResult
+}
+
+// extractStepValue reads a value for a variable from a step response
+func extractStepValue(httpResult HTTPCheckResult, jar http.CookieJar, stepURL string, extract entities.TransactionExtract) (string, error) {
+	switch extract.Source {
+	case "json":
+		if !gjson.ValidBytes(httpResult.Body) {
+			return "", fmt.Errorf("cannot extract %s: response body is not valid JSON", extract.Variable)
+		}
+		value := gjson.GetBytes(httpResult.Body, extract.Property)
+		if !value.Exists() {
+			return "", fmt.Errorf("cannot extract %s: JSON path %q not found", extract.Variable, extract.Property)
+		}
+		return value.String(), nil
+	case "header":
+		value := httpResult.Headers.Get(extract.Property)
+		if value == "" {
+			return "", fmt.Errorf("cannot extract %s: header %q not found", extract.Variable, extract.Property)
+		}
+		return value, nil
+	case "cookie":
+		// Cookies set by the response (or earlier steps) are in the jar
+		parsed, err := url.Parse(stepURL)
+		if err == nil {
+			for _, cookie := range jar.Cookies(parsed) {
+				if cookie.Name == extract.Property {
+					return cookie.Value, nil
+				}
+			}
+		}
+		return "", fmt.Errorf("cannot extract %s: cookie %q not found", extract.Variable, extract.Property)
+	default:
+		return "", fmt.Errorf("cannot extract %s: unsupported source %q", extract.Variable, extract.Source)
+	}
+}
+
+// resolveStepURL resolves a step URL against the monitor URL
+func resolveStepURL(baseURL, stepURL string) (string, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid monitor URL: %w", err)
+	}
+	if stepURL == "" {
+		return base.String(), nil
+	}
+
+	ref, err := url.Parse(stepURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid step URL: %w", err)
+	}
+	return base.ResolveReference(ref).String(), nil
+}
+
+// stepDisplayURL resolves a step URL template against the monitor URL for the check details,
+// keeping {{variable}} placeholders so that secrets and extracted tokens are never stored
+func stepDisplayURL(baseURL, template string) string {
+	resolved, err := resolveStepURL(baseURL, template)
+	if err != nil {
+		return template
+	}
+	return strings.NewReplacer("%7B", "{", "%7D", "}").Replace(resolved)
+}
+
+// substituteHTTPConfig returns a copy of the request settings with {{variable}} placeholders replaced
+// Assertion values are resolved by the HTTP checker when they are compared
+func substituteHTTPConfig(cfg entities.HTTPConfig, variables map[string]string) entities.HTTPConfig {
+	if len(cfg.Headers) > 0 {
+		headers := make(map[string]string, len(cfg.Headers))
+		for name, value := range cfg.Headers {
+			headers[name] = substituteVariables(value, variables)
+		}
+		cfg.Headers = headers
+	}
+	cfg.Body = substituteVariables(cfg.Body, variables)
+
+	if cfg.Auth != nil {
+		auth := *cfg.Auth
+		auth.Username = substituteVariables(auth.Username, variables)
+		auth.Password = substituteVariables(auth.Password, variables)
+		auth.Token = substituteVariables(auth.Token, variables)
+		cfg.Auth = &auth
+	}
+
+	return cfg
+}
+
+// substituteVariables replaces {{variable}} placeholders; unknown variables are left untouched
+func substituteVariables(value string, variables map[string]string) string {
+	if value == "" {
+		return value
+	}
+	return transactionVariableRegex.ReplaceAllStringFunc(value, func(match string) string {
+		name := transactionVariableRegex.FindStringSubmatch(match)[1]
+		if replacement, ok := variables[name]; ok {
+			return replacement
+		}
+		return match
+	})
+}
+
+// describeStep identifies a step in error messages, e.g. step 2 (get token)
+func describeStep(step entities.TransactionStep, index int) string {
+	if step.Name != "" {
+		return fmt.Sprintf("step %d (%s)", index+1, step.Name)
+	}
+	return fmt.Sprintf("step %d", index+1)
+}
+
+// deadlineOf returns the deadline of ctx, which is always set by Check
+func deadlineOf(ctx context.Context) time.Time {
+	deadline, _ := ctx.Deadline()
+	return deadline
+}
@@ -0,0 +1,195 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+)
+
+// newTransactionTestServer serves a login flow: POST /login returns a token and a session cookie,
+// GET /profile requires both
+func newTransactionTestServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		var creds struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+		}
+		if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&creds) != nil || creds.Password != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "sess-1", Path: "/"})
+		w.Header().Set("X-Request-Id", "req-42")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"token":"tok-123"}}`))
+	})
+	mux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("session")
+		if err != nil || cookie.Value != "sess-1" || r.Header.Get("Authorization") != "Bearer tok-123" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"name":"monitor","request":"` + r.Header.Get("X-Previous-Request") + `"}`))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestTransactionChecker_Check_Success(t *testing.T) {
+	server := newTransactionTestServer(t)
+	checker := NewTransactionChecker(NewHTTPChecker())
+
+	result := checker.Check(context.Background(), server.URL, entities.TransactionConfig{
+		Secrets: map[string]string{"password": "s3cret"},
+		Steps: []entities.TransactionStep{
+			{
+				Name: "login",
+				URL:  "/login",
+				HTTPConfig: entities.HTTPConfig{
+					Method:      "POST",
+					Body:        `{"username":"monitor","password":"{{password}}"}`,
+					ContentType: "application/json",
+				},
+				Extract: []entities.TransactionExtract{
+					{Variable: "token", Source: "json", Property: "data.token"},
+					{Variable: "request_id", Source: "header", Property: "X-Request-Id"},
+					{Variable: "session", Source: "cookie", Property: "session"},
+				},
+			},
+			{
+				Name: "profile",
+				URL:  "/profile",
+				HTTPConfig: entities.HTTPConfig{
+					Headers: map[string]string{"X-Previous-Request": "{{request_id}}"},
+					Auth:    &entities.HTTPAuth{Type: "bearer", Token: "{{token}}"},
+					Assertions: []entities.HTTPAssertion{
+						{Source: "json", Property: "request", Operator: "equals", Value: "{{request_id}}"},
+					},
+				},
+			},
+		},
+	}, 5*time.Second)
+
+	if !result.Success {
+		t.Fatalf("Expected success, got failure: %v", result.Error)
+	}
+	if len(result.Steps) != 2 {
+		t.Fatalf("Expected 2 step results, got %d", len(result.Steps))
+	}
+	if result.Steps[1].URL != server.URL+"/profile" {
+		t.Errorf("Expected step URL to be resolved against the monitor URL, got %s", result.Steps[1].URL)
+	}
+	if result.FailedStep != 0 {
+		t.Errorf("Expected no failed step, got %d", result.FailedStep)
+	}
+	if result.ResponseTime < result.Steps[0].ResponseTime+result.Steps[1].ResponseTime {
+		t.Errorf("Expected total response time to include every step")
+	}
+}
+
+func TestTransactionChecker_Check_FailingStep(t *testing.T) {
+	server := newTransactionTestServer(t)
+	checker := NewTransactionChecker(NewHTTPChecker())
+
+	result := checker.Check(context.Background(), server.URL, entities.TransactionConfig{
+		Steps: []entities.TransactionStep{
+			{Name: "home", URL: "/profile", ExpectedStatusCodes: []int64{403}},
+			{Name: "login", URL: "/login", HTTPConfig: entities.HTTPConfig{Method: "POST", Body: `{"password":"wrong"}`}},
+			{Name: "profile", URL: "/profile"},
+		},
+	}, 5*time.Second)
+
+	if result.Success {
+		t.Fatalf("Expected failure, got success")
+	}
+	if result.FailedStep != 2 {
+		t.Errorf("Expected step 2 to fail, got %d", result.FailedStep)
+	}
+	if len(result.Steps) != 2 {
+		t.Errorf("Expected execution to stop after the failing step, got %d results", len(result.Steps))
+	}
+	if result.Error == nil || !strings.Contains(result.Error.Error(), "step 2 (login) failed: unexpected status code 401") {
+		t.Errorf("Expected error naming the failing step, got: %v", result.Error)
+	}
+}
+
+func TestTransactionChecker_Check_MissingExtract(t *testing.T) {
+	server := newTransactionTestServer(t)
+	checker := NewTransactionChecker(NewHTTPChecker())
+
+	result := checker.Check(context.Background(), server.URL, entities.TransactionConfig{
+		Secrets: map[string]string{"password": "s3cret"},
+		Steps: []entities.TransactionStep{
+			{
+				URL:        "/login",
+				HTTPConfig: entities.HTTPConfig{Method: "POST", Body: `{"password":"{{password}}"}`},
+				Extract:    []entities.TransactionExtract{{Variable: "token", Source: "json", Property: "access_token"}},
+			},
+		},
+	}, 5*time.Second)
+
+	if result.Success {
+		t.Fatalf("Expected failure, got success")
+	}
+	if result.Error == nil || !strings.Contains(result.Error.Error(), `step 1 failed: cannot extract token: JSON path "access_token" not found`) {
+		t.Errorf("Expected extraction error, got: %v", result.Error)
+	}
+}
+
+func TestTransactionChecker_Check_SecretsNotRecorded(t *testing.T) {
+	server := newTransactionTestServer(t)
+	checker := NewTransactionChecker(NewHTTPChecker())
+
+	result := checker.Check(context.Background(), server.URL, entities.TransactionConfig{
+		Secrets: map[string]string{"password": "s3cret"},
+		Steps: []entities.TransactionStep{
+			{
+				URL:        "/login?audit={{password}}",
+				HTTPConfig: entities.HTTPConfig{Method: "POST", Body: `{"password":"{{password}}"}`},
+				Extract:    []entities.TransactionExtract{{Variable: "token", Source: "json", Property: "data.token"}},
+			},
+			{
+				URL: "/login?token={{token}}",
+				HTTPConfig: entities.HTTPConfig{
+					Method: "POST",
+					Body:   `{"password":"{{password}}"}`,
+					Assertions: []entities.HTTPAssertion{
+						{Source: "json", Property: "data.token", Operator: "not_equals", Value: "{{token}}"},
+					},
+				},
+			},
+		},
+	}, 5*time.Second)
+
+	if result.Success {
+		t.Fatalf("Expected the assertion to fail, got success")
+	}
+	if result.Steps[0].URL != server.URL+"/login?audit={{password}}" || result.Steps[1].URL != server.URL+"/login?token={{token}}" {
+		t.Errorf("Expected the step URLs to keep their placeholders, got %s and %s", result.Steps[0].URL, result.Steps[1].URL)
+	}
+	if result.Error == nil || !strings.Contains(result.Error.Error(), `not equals "{{token}}"`) {
+		t.Errorf("Expected the assertion to be described with its placeholder, got: %v", result.Error)
+	}
+	if strings.Contains(result.Error.Error(), "s3cret") {
+		t.Errorf("Expected no secret in the error, got: %v", result.Error)
+	}
+}
+
+func TestSubstituteVariables(t *testing.T) {
+	variables := map[string]string{"token": "abc", "id": "42"}
+
+	got := substituteVariables("/items/{{id}}?t={{ token }}&x={{unknown}}", variables)
+	expected := "/items/42?t=abc&x={{unknown}}"
+	if got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
@@ -0,0 +1,387 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"regexp"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+)
+
+// maxUDPResponsePreview limits the reply stored in check details
+const maxUDPResponsePreview = 512
+
+// UDPCheckResult represents the result of a UDP health check
+type UDPCheckResult struct {
+	ResponseTime time.Duration
+	Response     []byte // reply that matched, or the last reply received when none did
+	Error        error
+	Success      bool
+}
+
+// UDPChecker performs UDP request/reply checks
+type UDPChecker struct{}
+
+// NewUDPChecker creates a new UDP checker
+func NewUDPChecker() *UDPChecker {
+	return &UDPChecker{}
+}
+
+// udpProbe is a datagram and the check of the replies to it
+type udpProbe struct {
+	request []byte
+	verify  func(reply []byte) error // nil accepts any reply
+}
+
+// Check sends the datagram described by cfg to address (host:port) and waits for a reply.
+// With a preset the reply must answer the preset's request; with an expectation it must also
+// contain the expected string or bytes, or match the regex. Replies that do not match are
+// ignored until one does or the timeout expires.
+func (c *UDPChecker) Check(ctx context.Context, address string, cfg entities.UDPConfig, timeout time.Duration) UDPCheckResult {
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	probe, err := newUDPProbe(cfg)
+	if err != nil {
+		return UDPCheckResult{Error: err, Success: false}
+	}
+
+	startTime := time.Now()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(checkCtx, "udp", address)
+	if err != nil {
+		return UDPCheckResult{
+			ResponseTime: time.Since(startTime),
+			Error:        err,
+			Success:      false,
+		}
+	}
+	defer conn.Close()
+
+	// Unblock reads as soon as the check is cancelled or times out
+	stop := context.AfterFunc(checkCtx, func() {
+		conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	if _, err := conn.Write(probe.request); err != nil {
+		return UDPCheckResult{
+			ResponseTime: time.Since(startTime),
+			Error:        fmt.Errorf("failed to send datagram: %w", err),
+			Success:      false,
+		}
+	}
+
+	buf := make([]byte, 65535)
+	var lastReply []byte
+	var mismatch error
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			result := UDPCheckResult{
+				ResponseTime: time.Since(startTime),
+				Response:     lastReply,
+			}
+			switch {
+			case checkCtx.Err() != nil && mismatch != nil:
+				result.Error = fmt.Errorf("timed out waiting for expected reply: %w", mismatch)
+			case checkCtx.Err() != nil:
+				result.Error = errors.New("no reply received before the timeout")
+			default:
+				// Usually "connection refused" from an ICMP port unreachable
+				result.Error = fmt.Errorf("failed to read reply: %w", err)
+			}
+			return result
+		}
+
+		lastReply = bytes.Clone(buf[:n])
+		if probe.verify != nil {
+			if err := probe.verify(lastReply); err != nil {
+				mismatch = err
+				continue
+			}
+		}
+
+		return UDPCheckResult{
+			ResponseTime: time.Since(startTime),
+			Response:     lastReply,
+			Success:      true,
+		}
+	}
+}
+
+// newUDPProbe builds the datagram of cfg and the check of its replies
+func newUDPProbe(cfg entities.UDPConfig) (*udpProbe, error) {
+	var probe *udpProbe
+	switch cfg.Preset {
+	case "dns":
+		probe = newDNSProbe()
+	case "ntp":
+		probe = newNTPProbe()
+	case "snmp":
+		community := cfg.Community
+		if community == "" {
+			community = "public"
+		}
+		probe = newSNMPProbe(community)
+	case "":
+		payload := []byte(cfg.Payload)
+		if cfg.Encoding == "hex" {
+			var err error
+			if payload, err = hex.DecodeString(cfg.Payload); err != nil {
+				return nil, fmt.Errorf("invalid hex payload: %w", err)
+			}
+		}
+		probe = &udpProbe{request: payload}
+	default:
+		return nil, fmt.Errorf("unsupported UDP preset %q", cfg.Preset)
+	}
+
+	if cfg.Expect == "" {
+		return probe, nil
+	}
+
+	var matches func(reply []byte) bool
+	switch cfg.MatchMode {
+	case "", "contains":
+		expect := []byte(cfg.Expect)
+		matches = func(reply []byte) bool { return bytes.Contains(reply, expect) }
+	case "regex":
+		re, err := regexp.Compile(cfg.Expect)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expect regex: %w", err)
+		}
+		matches = re.Match
+	case "hex":
+		expect, err := hex.DecodeString(cfg.Expect)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expect bytes: %w", err)
+		}
+		matches = func(reply []byte) bool { return bytes.Contains(reply, expect) }
+	default:
+		return nil, fmt.Errorf("unsupported match mode %q", cfg.MatchMode)
+	}
+
+	verifyPreset := probe.verify
+	probe.verify = func(reply []byte) error {
+		if verifyPreset != nil {
+			if err := verifyPreset(reply); err != nil {
+				return err
+			}
+		}
+		if !matches(reply) {
+			preview, _ := previewUDPResponse(reply)
+			return fmt.Errorf("reply %q does not match the expected response", preview)
+		}
+		return nil
+	}
+	return probe, nil
+}
+
+// newDNSProbe queries the name servers of the root zone, which any DNS server answers,
+// if only with a refusal. The reply must be a response with the query's ID.
+func newDNSProbe() *udpProbe {
+	id := uint16(rand.Uint32())
+
+	request := binary.BigEndian.AppendUint16(nil, id)
+	request = append(request,
+		0x01, 0x00, // flags: recursion desired
+		0x00, 0x01, // one question
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // no answer, authority or additional records
+		0x00,       // root name
+		0x00, 0x02, // type NS
+		0x00, 0x01, // class IN
+	)
+
+	return &udpProbe{
+		request: request,
+		verify: func(reply []byte) error {
+			if len(reply) < 12 || binary.BigEndian.Uint16(reply) != id || reply[2]&0x80 == 0 {
+				return errors.New("reply is not a DNS response to the query")
+			}
+			return nil
+		},
+	}
+}
+
+// newNTPProbe sends an NTPv4 client request (RFC 5905) with a random transmit timestamp, which a
+// server echoes as the originate timestamp of its reply
+func newNTPProbe() *udpProbe {
+	request := make([]byte, 48)
+	request[0] = 0x23 // leap indicator 0, version 4, mode 3 (client)
+	binary.BigEndian.PutUint64(request[40:], rand.Uint64())
+
+	return &udpProbe{
+		request: request,
+		verify: func(reply []byte) error {
+			if len(reply) < 48 || reply[0]&0x07 != 4 || !bytes.Equal(reply[24:32], request[40:48]) {
+				return errors.New("reply is not an NTP server response to the request")
+			}
+			stratum := reply[1]
+			if stratum == 0 {
+				return fmt.Errorf("NTP server sent kiss-o'-death %q", bytes.TrimRight(reply[12:16], "\x00"))
+			}
+			if reply[0]>>6 == 3 || stratum >= 16 {
+				return errors.New("NTP server is not synchronized")
+			}
+			return nil
+		},
+	}
+}
+
+// sysDescrOID is the BER encoding of 1.3.6.1.2.1.1.1.0 (SNMPv2-MIB::sysDescr.0)
+var sysDescrOID = []byte{0x2b, 0x06, 0x01, 0x02, 0x01, 0x01, 0x01, 0x00}
+
+// newSNMPProbe sends an SNMPv2c GetRequest for sysDescr.0 (RFC 3416)
+// Agents silently drop requests with a wrong community, which then times out
+func newSNMPProbe(community string) *udpProbe {
+	requestID := rand.Int32N(1<<31-1) + 1
+
+	pdu := berTLV(0xa0, concat( // GetRequest-PDU
+		berInteger(int64(requestID)),
+		berInteger(0), // error-status
+		berInteger(0), // error-index
+		berTLV(0x30, berTLV(0x30, concat( // variable-bindings
+			berTLV(0x06, sysDescrOID),
+			berTLV(0x05, nil), // NULL value
+		))),
+	))
+	request := berTLV(0x30, concat(
+		berInteger(1), // version: SNMPv2c
+		berTLV(0x04, []byte(community)),
+		pdu,
+	))
+
+	return &udpProbe{
+		request: request,
+		verify: func(reply []byte) error {
+			errorStatus, err := parseSNMPResponse(reply, int64(requestID))
+			if err != nil {
+				return err
+			}
+			if errorStatus != 0 {
+				return fmt.Errorf("SNMP agent returned error status %d", errorStatus)
+			}
+			return nil
+		},
+	}
+}
+
+// parseSNMPResponse returns the error-status of an SNMP GetResponse to the request with requestID
+func parseSNMPResponse(reply []byte, requestID int64) (int64, error) {
+	errNotResponse := errors.New("reply is not an SNMP response to the request")
+
+	tag, message, _, err := readBERTLV(reply)
+	if err != nil || tag != 0x30 {
+		return 0, errNotResponse
+	}
+	// version and community precede the PDU
+	for i := 0; i < 2; i++ {
+		if _, _, message, err = readBERTLV(message); err != nil {
+			return 0, errNotResponse
+		}
+	}
+	tag, pdu, _, err := readBERTLV(message)
+	if err != nil || tag != 0xa2 {
+		return 0, errNotResponse
+	}
+
+	var values [2]int64 // request-id, error-status
+	for i := range values {
+		var content []byte
+		if tag, content, pdu, err = readBERTLV(pdu); err != nil || tag != 0x02 || len(content) == 0 || len(content) > 8 {
+			return 0, errNotResponse
+		}
+		for j, b := range content {
+			if j == 0 {
+				values[i] = int64(int8(b))
+			} else {
+				values[i] = values[i]<<8 | int64(b)
+			}
+		}
+	}
+	if values[0] != requestID {
+		return 0, errNotResponse
+	}
+	return values[1], nil
+}
+
+// berTLV encodes a BER tag, length and content
+func berTLV(tag byte, content []byte) []byte {
+	out := []byte{tag}
+	if len(content) < 0x80 {
+		out = append(out, byte(len(content)))
+	} else {
+		var length []byte
+		for n := len(content); n > 0; n >>= 8 {
+			length = append([]byte{byte(n)}, length...)
+		}
+		out = append(out, 0x80|byte(len(length)))
+		out = append(out, length...)
+	}
+	return append(out, content...)
+}
+
+// berInteger encodes a non-negative BER INTEGER in the fewest bytes
+func berInteger(value int64) []byte {
+	content := []byte{byte(value)}
+	for value >>= 8; value > 0; value >>= 8 {
+		content = append([]byte{byte(value)}, content...)
+	}
+	if content[0]&0x80 != 0 {
+		content = append([]byte{0}, content...)
+	}
+	return berTLV(0x02, content)
+}
+
+// readBERTLV splits the first BER element off data
+func readBERTLV(data []byte) (tag byte, content, rest []byte, err error) {
+	if len(data) < 2 {
+		return 0, nil, nil, errors.New("truncated BER element")
+	}
+	tag, length, data := data[0], int(data[1]), data[2:]
+	if length&0x80 != 0 {
+		size := length & 0x7f
+		if size == 0 || size > 4 || len(data) < size {
+			return 0, nil, nil, errors.New("invalid BER length")
+		}
+		length = 0
+		for _, b := range data[:size] {
+			length = length<<8 | int(b)
+		}
+		data = data[size:]
+	}
+	if length > len(data) {
+		return 0, nil, nil, errors.New("truncated BER element")
+	}
+	return tag, data[:length], data[length:], nil
+}
+
+// concat joins byte slices
+func concat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+// previewUDPResponse truncates a reply for check details, as text when it is printable
+// and hex encoded otherwise; it returns the preview and its encoding
+func previewUDPResponse(reply []byte) (string, string) {
+	if len(reply) > maxUDPResponsePreview {
+		reply = reply[:maxUDPResponsePreview]
+	}
+	if utf8.Valid(reply) && bytes.IndexFunc(reply, func(r rune) bool {
+		return !unicode.IsPrint(r) && !unicode.IsSpace(r)
+	}) < 0 {
+		return string(reply), "text"
+	}
+	return hex.EncodeToString(reply), "hex"
+}
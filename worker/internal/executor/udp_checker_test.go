@@ -0,0 +1,265 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+)
+
+// startUDPServer answers every datagram with the replies returned by handle
+func startUDPServer(t *testing.T, handle func(request []byte) [][]byte) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 65535)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			for _, reply := range handle(bytes.Clone(buf[:n])) {
+				conn.WriteTo(reply, addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func TestUDPChecker_Check_Payload(t *testing.T) {
+	address := startUDPServer(t, func(request []byte) [][]byte {
+		if bytes.Equal(request, []byte{0xff, 0xff, 0xff, 0xff, 'T'}) {
+			return [][]byte{{0xff, 0xff, 0xff, 0xff, 'I', 0x11}}
+		}
+		return [][]byte{append([]byte("echo: "), request...)}
+	})
+
+	tests := []struct {
+		name    string
+		cfg     entities.UDPConfig
+		success bool
+		errText string
+	}{
+		{"any reply", entities.UDPConfig{Payload: "ping"}, true, ""},
+		{"contains", entities.UDPConfig{Payload: "ping", Expect: "echo: ping"}, true, ""},
+		{"regex", entities.UDPConfig{Payload: "ping", Expect: `^echo: \w+$`, MatchMode: "regex"}, true, ""},
+		{"hex", entities.UDPConfig{Payload: "ffffffff54", Encoding: "hex", Expect: "ffffffff49", MatchMode: "hex"}, true, ""},
+		{"mismatch", entities.UDPConfig{Payload: "ping", Expect: "pong"}, false, "does not match the expected response"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewUDPChecker().Check(context.Background(), address, tt.cfg, 500*time.Millisecond)
+			if result.Success != tt.success {
+				t.Fatalf("Expected success=%v, got %v (error: %v)", tt.success, result.Success, result.Error)
+			}
+			if tt.errText != "" && (result.Error == nil || !strings.Contains(result.Error.Error(), tt.errText)) {
+				t.Errorf("Expected error containing %q, got %v", tt.errText, result.Error)
+			}
+			if len(result.Response) == 0 {
+				t.Errorf("Expected the reply to be returned")
+			}
+		})
+	}
+}
+
+func TestUDPChecker_Check_NoReply(t *testing.T) {
+	address := startUDPServer(t, func(request []byte) [][]byte { return nil })
+
+	result := NewUDPChecker().Check(context.Background(), address, entities.UDPConfig{Payload: "ping"}, 200*time.Millisecond)
+	if result.Success || result.Error == nil || result.Error.Error() != "no reply received before the timeout" {
+		t.Errorf("Expected a timeout without reply, got %+v", result)
+	}
+}
+
+func TestUDPChecker_Check_PortUnreachable(t *testing.T) {
+	// Reserve a port and release it so that nothing listens on it
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to reserve a port: %v", err)
+	}
+	address := conn.LocalAddr().String()
+	conn.Close()
+
+	result := NewUDPChecker().Check(context.Background(), address, entities.UDPConfig{Payload: "ping"}, time.Second)
+	if result.Success || result.Error == nil {
+		t.Errorf("Expected the check to fail, got %+v", result)
+	}
+}
+
+func TestUDPChecker_Check_DNSPreset(t *testing.T) {
+	address := startUDPServer(t, func(request []byte) [][]byte {
+		stray := []byte{request[0] ^ 0xff, request[1], 0x81, 0x80, 0, 0, 0, 0, 0, 0, 0, 0}
+		response := append([]byte{request[0], request[1], 0x81, 0x85}, request[4:]...) // REFUSED still proves the server answers
+		return [][]byte{stray, response}
+	})
+
+	result := NewUDPChecker().Check(context.Background(), address, entities.UDPConfig{Preset: "dns"}, time.Second)
+	if !result.Success {
+		t.Fatalf("Expected success, got: %v", result.Error)
+	}
+	if result.Response[2]&0x80 == 0 {
+		t.Errorf("Expected the DNS response to be returned, not the stray datagram")
+	}
+}
+
+func TestUDPChecker_Check_NTPPreset(t *testing.T) {
+	tests := []struct {
+		name    string
+		stratum byte
+		refID   string
+		errText string
+	}{
+		{"synchronized", 2, "GPS\x00", ""},
+		{"kiss-o'-death", 0, "RATE", `kiss-o'-death "RATE"`},
+		{"unsynchronized", 16, "INIT", "not synchronized"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := startUDPServer(t, func(request []byte) [][]byte {
+				reply := make([]byte, 48)
+				reply[0] = 0x24 // version 4, mode 4 (server)
+				reply[1] = tt.stratum
+				copy(reply[12:16], tt.refID)
+				copy(reply[24:32], request[40:48])
+				binary.BigEndian.PutUint64(reply[40:], 1)
+				return [][]byte{reply}
+			})
+
+			result := NewUDPChecker().Check(context.Background(), address, entities.UDPConfig{Preset: "ntp"}, 300*time.Millisecond)
+			if tt.errText == "" {
+				if !result.Success {
+					t.Errorf("Expected success, got: %v", result.Error)
+				}
+				return
+			}
+			if result.Success || result.Error == nil || !strings.Contains(result.Error.Error(), tt.errText) {
+				t.Errorf("Expected error containing %q, got %v", tt.errText, result.Error)
+			}
+		})
+	}
+}
+
+// snmpAgent answers GetRequests with the given community with a GetResponse carrying errorStatus
+func snmpAgent(community string, errorStatus int64) func(request []byte) [][]byte {
+	return func(request []byte) [][]byte {
+		_, message, _, _ := readBERTLV(request)
+		_, _, message, _ = readBERTLV(message) // version
+		_, requestCommunity, message, _ := readBERTLV(message)
+		if string(requestCommunity) != community {
+			return nil
+		}
+		_, pdu, _, _ := readBERTLV(message)
+		_, requestID, _, _ := readBERTLV(pdu)
+
+		response := berTLV(0x30, concat(
+			berInteger(1),
+			berTLV(0x04, requestCommunity),
+			berTLV(0xa2, concat(
+				berTLV(0x02, requestID),
+				berInteger(errorStatus),
+				berInteger(0),
+				berTLV(0x30, berTLV(0x30, concat(
+					berTLV(0x06, sysDescrOID),
+					berTLV(0x04, []byte("Linux router 5.15")),
+				))),
+			)),
+		))
+		return [][]byte{response}
+	}
+}
+
+func TestUDPChecker_Check_SNMPPreset(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     entities.UDPConfig
+		agent   func(request []byte) [][]byte
+		success bool
+		errText string
+	}{
+		{"default community", entities.UDPConfig{Preset: "snmp", Expect: "Linux"}, snmpAgent("public", 0), true, ""},
+		{"custom community", entities.UDPConfig{Preset: "snmp", Community: "monitoring"}, snmpAgent("monitoring", 0), true, ""},
+		{"wrong community", entities.UDPConfig{Preset: "snmp", Community: "wrong"}, snmpAgent("monitoring", 0), false, "no reply received"},
+		{"error status", entities.UDPConfig{Preset: "snmp"}, snmpAgent("public", 2), false, "error status 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := startUDPServer(t, tt.agent)
+
+			result := NewUDPChecker().Check(context.Background(), address, tt.cfg, 300*time.Millisecond)
+			if result.Success != tt.success {
+				t.Fatalf("Expected success=%v, got %v (error: %v)", tt.success, result.Success, result.Error)
+			}
+			if tt.errText != "" && (result.Error == nil || !strings.Contains(result.Error.Error(), tt.errText)) {
+				t.Errorf("Expected error containing %q, got %v", tt.errText, result.Error)
+			}
+		})
+	}
+}
+
+func TestUDPMonitorChecker_Check_SNMPCommunity(t *testing.T) {
+	monitor := &entities.Monitor{
+		Type:    "udp",
+		URL:     startUDPServer(t, snmpAgent("monitoring", 0)),
+		Timeout: 1,
+		// The stored config only marks the community as set
+		Config: entities.MonitorConfig{UDP: &entities.UDPConfig{Preset: "snmp", Community: entities.MaskedSecret}},
+	}
+	checker := NewUDPMonitorChecker(NewUDPChecker())
+
+	result := checker.Check(context.Background(), &CheckRequest{Monitor: monitor, Secrets: &entities.MonitorSecrets{SNMPCommunity: "monitoring"}})
+	if !result.Success {
+		t.Fatalf("Expected success with the stored community, got: %v", result.Error)
+	}
+
+	// Without a stored community the request is sent with 'public', which the agent drops
+	if result := checker.Check(context.Background(), &CheckRequest{Monitor: monitor}); result.Success {
+		t.Errorf("Expected no reply without the stored community")
+	}
+}
+
+func TestBERInteger(t *testing.T) {
+	tests := []struct {
+		value int64
+		want  []byte
+	}{
+		{0, []byte{0x02, 0x01, 0x00}},
+		{127, []byte{0x02, 0x01, 0x7f}},
+		{128, []byte{0x02, 0x02, 0x00, 0x80}},
+		{0x12345678, []byte{0x02, 0x04, 0x12, 0x34, 0x56, 0x78}},
+	}
+
+	for _, tt := range tests {
+		if got := berInteger(tt.value); !bytes.Equal(got, tt.want) {
+			t.Errorf("berInteger(%d) = %x, want %x", tt.value, got, tt.want)
+		}
+	}
+
+	long := berTLV(0x04, make([]byte, 300))
+	if !bytes.Equal(long[:4], []byte{0x04, 0x82, 0x01, 0x2c}) {
+		t.Errorf("Expected a long form length, got %x", long[:4])
+	}
+}
+
+func TestPreviewUDPResponse(t *testing.T) {
+	if preview, encoding := previewUDPResponse([]byte("OK\r\n")); preview != "OK\r\n" || encoding != "text" {
+		t.Errorf("Expected a text preview, got %q (%s)", preview, encoding)
+	}
+	if preview, encoding := previewUDPResponse([]byte{0xff, 0x00, 0x10}); preview != "ff0010" || encoding != "hex" {
+		t.Errorf("Expected a hex preview, got %q (%s)", preview, encoding)
+	}
+}
@@ -0,0 +1,149 @@
+package executor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// maxWebSocketMessageSize limits the first message read from the server
+	maxWebSocketMessageSize = 1 << 20
+	// maxWebSocketMessagePreview limits the message stored in check details
+	maxWebSocketMessagePreview = 512
+)
+
+// WebSocketCheckResult represents the result of a WebSocket health check
+type WebSocketCheckResult struct {
+	ResponseTime     time.Duration // handshake and message exchange
+	HandshakeTime    time.Duration
+	FirstMessageTime time.Duration // from sending the message, or the end of the handshake, to the first message
+	StatusCode       int           // status of the handshake response, 101 when the upgrade succeeded
+	Subprotocol      string        // accepted by the server
+	Message          []byte        // first message received, only when an expectation is configured
+	Error            error
+	Success          bool
+}
+
+// WebSocketChecker performs WebSocket health checks
+type WebSocketChecker struct {
+	rootCAs *x509.CertPool // trusted roots for wss:// connections, system roots when nil
+}
+
+// NewWebSocketChecker creates a new WebSocket checker
+func NewWebSocketChecker() *WebSocketChecker {
+	return &WebSocketChecker{}
+}
+
+// Check performs the upgrade handshake with the ws:// or wss:// target, sending the headers and
+// requesting the subprotocol of cfg, which the server must then accept. When cfg sets a message it
+// is sent after the handshake; when it sets an expectation the first message received must contain
+// the expected string (or match the regex) before the timeout.
+func (c *WebSocketChecker) Check(ctx context.Context, target string, cfg entities.WebSocketConfig, timeout time.Duration) WebSocketCheckResult {
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var matcher func([]byte) bool
+	if cfg.Expect != "" {
+		var err error
+		matcher, err = newResponseMatcher(cfg.Expect, cfg.MatchMode)
+		if err != nil {
+			return WebSocketCheckResult{Error: err, Success: false}
+		}
+	}
+
+	header := make(http.Header, len(cfg.Headers))
+	for name, value := range cfg.Headers {
+		header.Set(name, value)
+	}
+	dialer := &websocket.Dialer{
+		HandshakeTimeout: timeout,
+		TLSClientConfig:  &tls.Config{RootCAs: c.rootCAs},
+	}
+	if cfg.Subprotocol != "" {
+		dialer.Subprotocols = []string{cfg.Subprotocol}
+	}
+
+	startTime := time.Now()
+
+	conn, resp, err := dialer.DialContext(checkCtx, target, header)
+	result := WebSocketCheckResult{HandshakeTime: time.Since(startTime)}
+	if resp != nil {
+		result.StatusCode = resp.StatusCode
+	}
+	if err != nil {
+		result.ResponseTime = result.HandshakeTime
+		if errors.Is(err, websocket.ErrBadHandshake) && resp != nil {
+			// Usually a proxy or load balancer in front of the server not passing the upgrade through
+			result.Error = fmt.Errorf("WebSocket upgrade failed: server responded %s", resp.Status)
+		} else {
+			result.Error = fmt.Errorf("WebSocket handshake failed: %w", err)
+		}
+		return result
+	}
+	defer conn.Close()
+	result.Subprotocol = conn.Subprotocol()
+
+	fail := func(err error) WebSocketCheckResult {
+		result.ResponseTime = time.Since(startTime)
+		result.Error = err
+		return result
+	}
+
+	if cfg.Subprotocol != "" && result.Subprotocol != cfg.Subprotocol {
+		return fail(fmt.Errorf("server did not accept subprotocol %q", cfg.Subprotocol))
+	}
+
+	deadline, _ := checkCtx.Deadline()
+	conn.SetWriteDeadline(deadline)
+	conn.SetReadDeadline(deadline)
+
+	exchangeStart := time.Now()
+	if cfg.Send != "" {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(cfg.Send)); err != nil {
+			return fail(fmt.Errorf("failed to send message: %w", err))
+		}
+	}
+
+	if matcher != nil {
+		// Control frames are handled while reading, the first data message is returned
+		conn.SetReadLimit(maxWebSocketMessageSize)
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return fail(errors.New("timed out waiting for the first message"))
+			}
+			return fail(fmt.Errorf("failed to read the first message: %w", err))
+		}
+		result.FirstMessageTime = time.Since(exchangeStart)
+		result.Message = message
+
+		if !matcher(message) {
+			return fail(fmt.Errorf("first message %q does not match the expected response", truncateForError(string(message))))
+		}
+	}
+
+	result.ResponseTime = time.Since(startTime)
+	result.Success = true
+
+	// Close politely so that the server does not log an abnormal closure
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), deadline)
+	return result
+}
+
+// previewWebSocketMessage truncates a message for check details
+func previewWebSocketMessage(message []byte) string {
+	if len(message) > maxWebSocketMessagePreview {
+		message = message[:maxWebSocketMessagePreview]
+	}
+	return string(message)
+}
@@ -0,0 +1,142 @@
+package executor
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+	"github.com/gorilla/websocket"
+)
+
+// newWebSocketTestServer serves WebSocket endpoints for the checker tests:
+// /echo answers each message with "echo: <message>", /greet sends a greeting on connect,
+// /silent never sends anything and /health is a plain HTTP endpoint
+func newWebSocketTestServer(t *testing.T, tls bool) *httptest.Server {
+	t.Helper()
+
+	upgrader := websocket.Upgrader{Subprotocols: []string{"feed.v1"}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			messageType, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			conn.WriteMessage(messageType, append([]byte("echo: "), message...))
+		}
+	})
+	mux.HandleFunc("/greet", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.WriteMessage(websocket.PingMessage, nil)
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"hello","version":3}`))
+		conn.ReadMessage()
+	})
+	mux.HandleFunc("/silent", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.ReadMessage()
+	})
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+
+	var server *httptest.Server
+	if tls {
+		server = httptest.NewTLSServer(mux)
+	} else {
+		server = httptest.NewServer(mux)
+	}
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWebSocketChecker_Check(t *testing.T) {
+	server := newWebSocketTestServer(t, false)
+	baseURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	auth := map[string]string{"Authorization": "Bearer token"}
+
+	tests := []struct {
+		name       string
+		path       string
+		cfg        entities.WebSocketConfig
+		success    bool
+		errText    string
+		statusCode int
+		message    string
+	}{
+		{"handshake only", "/greet", entities.WebSocketConfig{}, true, "", http.StatusSwitchingProtocols, ""},
+		{"first message", "/greet", entities.WebSocketConfig{Expect: `"type":"hello"`}, true, "", http.StatusSwitchingProtocols, `{"type":"hello","version":3}`},
+		{"send and expect", "/echo", entities.WebSocketConfig{Headers: auth, Send: "ping", Expect: "echo: ping"}, true, "", http.StatusSwitchingProtocols, "echo: ping"},
+		{"regex", "/echo", entities.WebSocketConfig{Headers: auth, Send: "ping", Expect: `^echo: \w+$`, MatchMode: "regex"}, true, "", http.StatusSwitchingProtocols, "echo: ping"},
+		{"subprotocol", "/greet", entities.WebSocketConfig{Subprotocol: "feed.v1"}, true, "", http.StatusSwitchingProtocols, ""},
+		{"mismatch", "/echo", entities.WebSocketConfig{Headers: auth, Send: "ping", Expect: "pong"}, false, "does not match", http.StatusSwitchingProtocols, "echo: ping"},
+		{"no message", "/silent", entities.WebSocketConfig{Expect: "hello"}, false, "timed out waiting for the first message", http.StatusSwitchingProtocols, ""},
+		{"subprotocol not accepted", "/greet", entities.WebSocketConfig{Subprotocol: "feed.v2"}, false, `did not accept subprotocol "feed.v2"`, http.StatusSwitchingProtocols, ""},
+		{"missing header", "/echo", entities.WebSocketConfig{}, false, "server responded 401 Unauthorized", http.StatusUnauthorized, ""},
+		{"no upgrade", "/health", entities.WebSocketConfig{}, false, "WebSocket upgrade failed", http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewWebSocketChecker().Check(context.Background(), baseURL+tt.path, tt.cfg, 500*time.Millisecond)
+			if result.Success != tt.success {
+				t.Fatalf("Expected success=%v, got %v (error: %v)", tt.success, result.Success, result.Error)
+			}
+			if tt.errText != "" && (result.Error == nil || !strings.Contains(result.Error.Error(), tt.errText)) {
+				t.Errorf("Expected error containing %q, got %v", tt.errText, result.Error)
+			}
+			if result.StatusCode != tt.statusCode {
+				t.Errorf("Expected status code %d, got %d", tt.statusCode, result.StatusCode)
+			}
+			if string(result.Message) != tt.message {
+				t.Errorf("Expected message %q, got %q", tt.message, result.Message)
+			}
+			if tt.message != "" && (result.FirstMessageTime <= 0 || result.FirstMessageTime > result.ResponseTime) {
+				t.Errorf("Expected the first message latency to be recorded, got %v", result.FirstMessageTime)
+			}
+			if result.HandshakeTime <= 0 || result.HandshakeTime > result.ResponseTime {
+				t.Errorf("Expected the handshake latency to be recorded, got %v of %v", result.HandshakeTime, result.ResponseTime)
+			}
+		})
+	}
+}
+
+func TestWebSocketChecker_Check_TLS(t *testing.T) {
+	server := newWebSocketTestServer(t, true)
+	target := "wss" + strings.TrimPrefix(server.URL, "https") + "/greet"
+	cfg := entities.WebSocketConfig{Expect: "hello"}
+
+	// The test certificate is not trusted by the system roots
+	result := NewWebSocketChecker().Check(context.Background(), target, cfg, time.Second)
+	if result.Success || result.Error == nil || !strings.Contains(result.Error.Error(), "WebSocket handshake failed") {
+		t.Fatalf("Expected a certificate error, got success=%v error=%v", result.Success, result.Error)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	result = (&WebSocketChecker{rootCAs: roots}).Check(context.Background(), target, cfg, time.Second)
+	if !result.Success {
+		t.Errorf("Expected success with the server certificate trusted, got: %v", result.Error)
+	}
+}
@@ -3,6 +3,7 @@ package jobs
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/eovipmak/v-insight/shared/domain/entities"
@@ -143,12 +144,14 @@ func (j *AlertEvaluatorJob) evaluateCheckAgainstRules(check *entities.MonitorChe
 		}
 
 		// Skip if rule is monitor-specific and doesn't match this check's monitor
-		if rule.MonitorID.Valid && rule.MonitorID.String != check.MonitorID {
+		if rule.MonitorID != nil && *rule.MonitorID != check.MonitorID {
 			continue
 		}
 
-		// Skip SSL expiry rules for TCP monitors when rule applies to all monitors
-		if rule.TriggerType == "ssl_expiry" && !rule.MonitorID.Valid && check.MonitorType == "tcp" {
+		// Skip SSL expiry rules for TCP monitors when rule applies to all monitors,
+		// unless the monitor presents a client certificate
+		if rule.TriggerType == "ssl_expiry" && rule.MonitorID == nil && check.MonitorType == "tcp" &&
+			(check.Details == nil || check.Details.ClientCertificate == nil) {
 			continue
 		}
 
@@ -157,7 +160,7 @@ func (j *AlertEvaluatorJob) evaluateCheckAgainstRules(check *entities.MonitorChe
 
 		if triggered {
 			// Alert triggered - create incident if not already open
-			created, err := j.createIncidentIfNeeded(check.MonitorID, rule.ID, rule.UserID, triggerValue)
+			created, err := j.createIncidentIfNeeded(check, rule.ID, rule.UserID, triggerValue)
 			if err != nil {
 				if internal.Log != nil {
 					internal.Log.Error("Failed to create incident",
@@ -241,15 +244,71 @@ func (j *AlertEvaluatorJob) evaluateRule(check *entities.MonitorCheck, rule *ent
 					daysUntilExpiry, check.SSLExpiresAt.Time.Format("2006-01-02"))
 			}
 		}
+		// The client certificate presented for mutual TLS is covered too
+		if check.Details != nil && check.Details.ClientCertificate != nil {
+			clientCertificate := check.Details.ClientCertificate
+			daysUntilExpiry := int(time.Until(clientCertificate.ExpiresAt).Hours() / 24)
+			if daysUntilExpiry <= rule.ThresholdValue && daysUntilExpiry >= 0 {
+				return true, fmt.Sprintf("Client certificate %q expires in %d days (on %s)",
+					clientCertificate.Name, daysUntilExpiry, clientCertificate.ExpiresAt.Format("2006-01-02"))
+			}
+		}
+
+	case "domain_expiry":
+		if check.Details != nil && check.Details.Domain != nil && check.Details.Domain.ExpiresAt != nil {
+			expiresAt := *check.Details.Domain.ExpiresAt
+			daysUntilExpiry := int(time.Until(expiresAt).Hours() / 24)
+			if daysUntilExpiry <= rule.ThresholdValue {
+				if expiresAt.Before(time.Now()) {
+					return true, fmt.Sprintf("Domain %s expired on %s", check.Details.Domain.Domain, expiresAt.Format("2006-01-02"))
+				}
+				return true, fmt.Sprintf("Domain %s expires in %d days (on %s)",
+					check.Details.Domain.Domain, daysUntilExpiry, expiresAt.Format("2006-01-02"))
+			}
+		}
+
+	case "packet_loss":
+		if check.Details != nil && check.Details.ICMP != nil {
+			if check.Details.ICMP.PacketLoss > float64(rule.ThresholdValue) {
+				return true, fmt.Sprintf("Packet loss: %.1f%% (%d/%d received, threshold: %d%%)",
+					check.Details.ICMP.PacketLoss, check.Details.ICMP.PacketsReceived,
+					check.Details.ICMP.PacketsSent, rule.ThresholdValue)
+			}
+		}
+
+	case "ssl_invalid":
+		if check.SSLValid.Valid && !check.SSLValid.Bool {
+			if check.Details != nil && check.Details.SSL != nil && check.Details.SSL.Error != "" {
+				return true, fmt.Sprintf("SSL certificate is invalid: %s", check.Details.SSL.Error)
+			}
+			return true, "SSL certificate is invalid"
+		}
+
+	case "tls_weak":
+		if check.Details != nil && check.Details.SSL != nil && len(check.Details.SSL.Weaknesses) > 0 {
+			return true, fmt.Sprintf("Weak TLS configuration: %s", strings.Join(check.Details.SSL.Weaknesses, "; "))
+		}
+
+	case "ssl_changed":
+		if check.Details != nil && check.Details.SSL != nil && check.Details.SSL.PreviousFingerprint != "" {
+			return true, fmt.Sprintf("SSL certificate changed unexpectedly (SHA-256 %s, was %s)",
+				check.Details.SSL.Fingerprint, check.Details.SSL.PreviousFingerprint)
+		}
+
+	case "content_changed":
+		if check.Details != nil && check.Details.Content != nil && check.Details.Content.Changed {
+			return true, fmt.Sprintf("Content changed from the accepted baseline (SHA-256 %s, baseline %s)",
+				check.Details.Content.Hash, check.Details.Content.BaselineHash)
+		}
 	}
 
 	return false, ""
 }
 
-// createIncidentIfNeeded creates an incident if one doesn't already exist
-func (j *AlertEvaluatorJob) createIncidentIfNeeded(monitorID, ruleID string, userID int, triggerValue string) (bool, error) {
+// createIncidentIfNeeded creates an incident opened by check if one doesn't already exist
+func (j *AlertEvaluatorJob) createIncidentIfNeeded(check *entities.MonitorCheck, ruleID string, userID int, triggerValue string) (bool, error) {
 	// Check if there's already an open incident
-	incident, err := j.incidentRepo.GetOpenIncident(monitorID, ruleID)
+	incident, err := j.incidentRepo.GetOpenIncident(check.MonitorID, ruleID)
 	if err != nil {
 		return false, fmt.Errorf("failed to check for existing incident: %w", err)
 	}
@@ -261,13 +320,16 @@ func (j *AlertEvaluatorJob) createIncidentIfNeeded(monitorID, ruleID string, use
 
 	// Create new incident
 	newIncident := &entities.Incident{
-		MonitorID:    monitorID,
+		MonitorID:    check.MonitorID,
 		AlertRuleID:  ruleID,
 		UserID:       userID,
 		StartedAt:    time.Now(),
 		Status:       "open",
 		TriggerValue: triggerValue,
 	}
+	if check.ID != "" {
+		newIncident.CheckID = &check.ID
+	}
 
 	err = j.incidentRepo.Create(newIncident)
 	if err != nil {
@@ -2,7 +2,9 @@ package jobs
 
 import (
 	"database/sql"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/eovipmak/v-insight/shared/domain/entities"
 )
@@ -104,3 +106,149 @@ func TestEvaluateRule_SlowResponse_NotTriggered(t *testing.T) {
 		t.Error("Expected slow_response rule not to be triggered when response time is below threshold")
 	}
 }
+
+func TestEvaluateRule_PacketLoss_Triggered(t *testing.T) {
+	job := &AlertEvaluatorJob{}
+
+	check := &entities.MonitorCheck{
+		Success: true,
+		Details: &entities.CheckDetails{
+			ICMP: &entities.ICMPCheckDetails{PacketsSent: 5, PacketsReceived: 3, PacketLoss: 40},
+		},
+	}
+
+	rule := &entities.AlertRule{
+		TriggerType:    "packet_loss",
+		ThresholdValue: 20,
+	}
+
+	triggered, value := job.evaluateRule(check, rule)
+	if !triggered {
+		t.Error("Expected packet_loss rule to be triggered when loss exceeds threshold")
+	}
+
+	if value == "" {
+		t.Error("Expected trigger value to be set")
+	}
+}
+
+func TestEvaluateRule_PacketLoss_NotTriggered(t *testing.T) {
+	job := &AlertEvaluatorJob{}
+
+	check := &entities.MonitorCheck{
+		Success: true,
+		Details: &entities.CheckDetails{
+			ICMP: &entities.ICMPCheckDetails{PacketsSent: 5, PacketsReceived: 5, PacketLoss: 0},
+		},
+	}
+
+	rule := &entities.AlertRule{
+		TriggerType:    "packet_loss",
+		ThresholdValue: 20,
+	}
+
+	triggered, _ := job.evaluateRule(check, rule)
+	if triggered {
+		t.Error("Expected packet_loss rule not to be triggered when loss is below threshold")
+	}
+
+	// Checks without ping statistics never trigger packet loss
+	triggered, _ = job.evaluateRule(&entities.MonitorCheck{Success: true}, rule)
+	if triggered {
+		t.Error("Expected packet_loss rule not to be triggered for checks without ICMP details")
+	}
+}
+
+func TestEvaluateRule_DomainExpiry(t *testing.T) {
+	job := &AlertEvaluatorJob{}
+	rule := &entities.AlertRule{
+		TriggerType:    "domain_expiry",
+		ThresholdValue: 30,
+	}
+	domainCheck := func(expiresAt time.Time) *entities.MonitorCheck {
+		return &entities.MonitorCheck{
+			Success: true,
+			Details: &entities.CheckDetails{
+				Domain: &entities.DomainCheckDetails{Domain: "example.com", ExpiresAt: &expiresAt},
+			},
+		}
+	}
+
+	triggered, value := job.evaluateRule(domainCheck(time.Now().Add(10*24*time.Hour)), rule)
+	if !triggered || value == "" {
+		t.Error("Expected domain_expiry rule to be triggered when the domain expires within the threshold")
+	}
+
+	triggered, value = job.evaluateRule(domainCheck(time.Now().Add(-24*time.Hour)), rule)
+	if !triggered || !strings.Contains(value, "expired") {
+		t.Errorf("Expected domain_expiry rule to be triggered for an expired domain, got %q", value)
+	}
+
+	triggered, _ = job.evaluateRule(domainCheck(time.Now().Add(90*24*time.Hour)), rule)
+	if triggered {
+		t.Error("Expected domain_expiry rule not to be triggered when expiry is beyond the threshold")
+	}
+
+	triggered, _ = job.evaluateRule(&entities.MonitorCheck{Success: true}, rule)
+	if triggered {
+		t.Error("Expected domain_expiry rule not to be triggered for checks without domain details")
+	}
+}
+
+func TestEvaluateRule_SSLExpiry_ClientCertificate(t *testing.T) {
+	job := &AlertEvaluatorJob{}
+	rule := &entities.AlertRule{
+		TriggerType:    "ssl_expiry",
+		ThresholdValue: 14,
+	}
+	clientCertificateCheck := func(expiresAt time.Time) *entities.MonitorCheck {
+		return &entities.MonitorCheck{
+			Success:      true,
+			SSLExpiresAt: sql.NullTime{Time: time.Now().Add(90 * 24 * time.Hour), Valid: true},
+			Details: &entities.CheckDetails{
+				ClientCertificate: &entities.ClientCertificateCheckDetails{Name: "billing-client", ExpiresAt: expiresAt},
+			},
+		}
+	}
+
+	triggered, value := job.evaluateRule(clientCertificateCheck(time.Now().Add(5*24*time.Hour)), rule)
+	if !triggered || !strings.Contains(value, "billing-client") {
+		t.Errorf("Expected ssl_expiry rule to be triggered by the client certificate, got %q", value)
+	}
+
+	triggered, _ = job.evaluateRule(clientCertificateCheck(time.Now().Add(60*24*time.Hour)), rule)
+	if triggered {
+		t.Error("Expected ssl_expiry rule not to be triggered when both certificates expire beyond the threshold")
+	}
+}
+
+func TestEvaluateRule_ContentChanged(t *testing.T) {
+	job := &AlertEvaluatorJob{}
+	rule := &entities.AlertRule{
+		TriggerType:    "content_changed",
+		ThresholdValue: 1,
+	}
+	contentCheck := func(hash, baselineHash string) *entities.MonitorCheck {
+		return &entities.MonitorCheck{
+			Success: true,
+			Details: &entities.CheckDetails{
+				Content: &entities.ContentCheckDetails{Hash: hash, BaselineHash: baselineHash, Changed: hash != baselineHash},
+			},
+		}
+	}
+
+	triggered, value := job.evaluateRule(contentCheck("bbb", "aaa"), rule)
+	if !triggered || !strings.Contains(value, "bbb") {
+		t.Errorf("Expected content_changed rule to be triggered when the content differs from the baseline, got %q", value)
+	}
+
+	triggered, _ = job.evaluateRule(contentCheck("aaa", "aaa"), rule)
+	if triggered {
+		t.Error("Expected content_changed rule not to be triggered when the content matches the baseline")
+	}
+
+	triggered, _ = job.evaluateRule(&entities.MonitorCheck{Success: true}, rule)
+	if triggered {
+		t.Error("Expected content_changed rule not to be triggered for checks without content details")
+	}
+}
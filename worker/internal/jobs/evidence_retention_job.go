@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/repository"
+	"github.com/eovipmak/v-insight/worker/internal"
+	"go.uber.org/zap"
+)
+
+// EvidenceRetentionJob deletes the stored responses of checks once they are older than the retention period,
+// the checks themselves are kept
+type EvidenceRetentionJob struct {
+	evidenceRepo repository.CheckEvidenceRepository
+	retention    time.Duration
+}
+
+// NewEvidenceRetentionJob creates a new evidence retention job
+func NewEvidenceRetentionJob(evidenceRepo repository.CheckEvidenceRepository, retention time.Duration) *EvidenceRetentionJob {
+	return &EvidenceRetentionJob{
+		evidenceRepo: evidenceRepo,
+		retention:    retention,
+	}
+}
+
+// Name returns the name of the job
+func (j *EvidenceRetentionJob) Name() string {
+	return "EvidenceRetentionJob"
+}
+
+// Run deletes the evidence stored before the retention period
+func (j *EvidenceRetentionJob) Run(ctx context.Context) error {
+	startTime := time.Now()
+	defer func() {
+		internal.JobExecutionDuration.WithLabelValues("EvidenceRetentionJob").Observe(time.Since(startTime).Seconds())
+	}()
+
+	// Evidence is timestamped with the UTC time of its check
+	deleted, err := j.evidenceRepo.DeleteOlderThan(startTime.UTC().Add(-j.retention))
+	if err != nil {
+		if internal.Log != nil {
+			internal.Log.Error("Failed to delete expired check evidence", zap.Error(err))
+		}
+		internal.JobExecutionTotal.WithLabelValues("EvidenceRetentionJob", "failure").Inc()
+		return err
+	}
+
+	if internal.Log != nil && deleted > 0 {
+		internal.Log.Info("Deleted expired check evidence", zap.Int64("count", deleted))
+	}
+	internal.JobExecutionTotal.WithLabelValues("EvidenceRetentionJob", "success").Inc()
+	return nil
+}
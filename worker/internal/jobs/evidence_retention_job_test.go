@@ -0,0 +1,60 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+)
+
+// fakeEvidenceRepo records the calls made by the evidence retention and notification jobs
+type fakeEvidenceRepo struct {
+	cutoff   time.Time
+	evidence map[string]*entities.CheckEvidence // keyed by check ID
+	err      error
+}
+
+func (r *fakeEvidenceRepo) Create(evidence *entities.CheckEvidence) error {
+	return r.err
+}
+
+func (r *fakeEvidenceRepo) GetByCheckID(checkID string) (*entities.CheckEvidence, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	evidence, ok := r.evidence[checkID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return evidence, nil
+}
+
+func (r *fakeEvidenceRepo) DeleteOlderThan(cutoff time.Time) (int64, error) {
+	r.cutoff = cutoff
+	return 3, r.err
+}
+
+func TestEvidenceRetentionJob_Run(t *testing.T) {
+	repo := &fakeEvidenceRepo{}
+	job := NewEvidenceRetentionJob(repo, 7*24*time.Hour)
+
+	if job.Name() != "EvidenceRetentionJob" {
+		t.Errorf("Expected job name 'EvidenceRetentionJob', got '%s'", job.Name())
+	}
+
+	before := time.Now().UTC().Add(-7 * 24 * time.Hour)
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if repo.cutoff.Before(before) || repo.cutoff.After(before.Add(time.Minute)) {
+		t.Errorf("Expected evidence older than 7 days to be deleted, cutoff was %v", repo.cutoff)
+	}
+
+	repo.err = errors.New("database unavailable")
+	if err := job.Run(context.Background()); err == nil {
+		t.Error("Expected the repository error to be returned")
+	}
+}
@@ -2,17 +2,23 @@ package jobs
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
+	"errors"
 	"fmt"
+	"math/rand/v2"
 	"net"
-	"strconv"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/eovipmak/v-insight/shared/content"
 	"github.com/eovipmak/v-insight/shared/domain/entities"
 	"github.com/eovipmak/v-insight/shared/domain/repository"
+	"github.com/eovipmak/v-insight/shared/secrets"
 	"github.com/eovipmak/v-insight/worker/internal"
+	"github.com/eovipmak/v-insight/worker/internal/config"
 	"github.com/eovipmak/v-insight/worker/internal/executor"
 	"go.uber.org/zap"
 )
@@ -20,20 +26,62 @@ import (
 // HealthCheckJob performs HTTP health checks on monitors
 type HealthCheckJob struct {
 	monitorRepo repository.MonitorRepository
-	httpChecker *executor.HTTPChecker
-	tcpChecker  *executor.TCPChecker
+	checkers    *executor.Registry // checker of each monitor type
 	sslChecker  *executor.SSLChecker
-	icmpChecker *executor.ICMPChecker
+
+	monitorCredentials
+	contentRepo  repository.ContentBaselineRepository
+	evidenceRepo repository.CheckEvidenceRepository
+
+	evidenceCfg config.EvidenceConfig
 }
 
 // NewHealthCheckJob creates a new health check job
-func NewHealthCheckJob(monitorRepo repository.MonitorRepository) *HealthCheckJob {
+func NewHealthCheckJob(monitorRepo repository.MonitorRepository, clientCertRepo repository.ClientCertificateRepository, contentRepo repository.ContentBaselineRepository, evidenceRepo repository.CheckEvidenceRepository, cipher *secrets.Cipher, domainCfg config.DomainConfig, evidenceCfg config.EvidenceConfig, dockerCfg config.DockerConfig) *HealthCheckJob {
+	httpChecker := executor.NewHTTPChecker()
+	icmpChecker := executor.NewICMPChecker()
+	databaseChecker := executor.NewDatabaseChecker()
+	mailChecker := executor.NewMailChecker()
+	sslChecker := executor.NewSSLChecker(30 * time.Second)
+
+	// Docker monitors compare the restart count with the one of the previous check
+	latestCheck := func(monitorID string) (*entities.MonitorCheck, error) {
+		checks, err := monitorRepo.GetChecksByMonitorID(monitorID, 1)
+		if err != nil || len(checks) == 0 {
+			return nil, err
+		}
+		return checks[0], nil
+	}
+
 	return &HealthCheckJob{
 		monitorRepo: monitorRepo,
-		httpChecker: executor.NewHTTPChecker(),
-		tcpChecker:  executor.NewTCPChecker(),
-		sslChecker:  executor.NewSSLChecker(30 * time.Second),
-		icmpChecker: executor.NewICMPChecker(),
+		checkers: executor.NewRegistry(
+			executor.NewHTTPMonitorChecker(httpChecker),
+			executor.NewTransactionMonitorChecker(httpChecker),
+			executor.NewTCPMonitorChecker(executor.NewTCPChecker()),
+			executor.NewUDPMonitorChecker(executor.NewUDPChecker()),
+			executor.NewICMPMonitorChecker(icmpChecker, "ping"),
+			executor.NewICMPMonitorChecker(icmpChecker, "icmp"),
+			executor.NewDNSMonitorChecker(executor.NewDNSChecker()),
+			executor.NewWebSocketMonitorChecker(executor.NewWebSocketChecker()),
+			executor.NewGRPCMonitorChecker(executor.NewGRPCChecker()),
+			executor.NewDatabaseMonitorChecker(databaseChecker, "postgres"),
+			executor.NewDatabaseMonitorChecker(databaseChecker, "mysql"),
+			executor.NewDatabaseMonitorChecker(databaseChecker, "redis"),
+			executor.NewMailMonitorChecker(mailChecker, sslChecker, "smtp"),
+			executor.NewMailMonitorChecker(mailChecker, sslChecker, "imap"),
+			executor.NewMailMonitorChecker(mailChecker, sslChecker, "pop3"),
+			executor.NewDockerMonitorChecker(executor.NewDockerChecker(dockerCfg.Sockets), latestCheck),
+			executor.NewDomainMonitorChecker(executor.NewDomainChecker(domainCfg.RDAPURL, domainCfg.WHOISServer)),
+			executor.NewPushMonitorChecker(),
+		),
+		sslChecker: sslChecker,
+
+		monitorCredentials: monitorCredentials{clientCertRepo: clientCertRepo, cipher: cipher},
+		contentRepo:        contentRepo,
+		evidenceRepo:       evidenceRepo,
+
+		evidenceCfg: evidenceCfg,
 	}
 }
 
@@ -113,10 +161,6 @@ func (j *HealthCheckJob) checkMonitorsConcurrently(ctx context.Context, monitors
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			// Create monitor-specific context with timeout
-			checkCtx, cancel := context.WithTimeout(ctx, time.Duration(m.Timeout)*time.Second)
-			defer cancel()
-
 			// Perform the check
 			if internal.Log != nil {
 				internal.Log.Info("Checking monitor",
@@ -125,7 +169,7 @@ func (j *HealthCheckJob) checkMonitorsConcurrently(ctx context.Context, monitors
 					zap.String("id", m.ID),
 				)
 			}
-			j.checkMonitor(checkCtx, m)
+			j.checkMonitor(ctx, m)
 		}(monitor)
 	}
 
@@ -134,6 +178,7 @@ func (j *HealthCheckJob) checkMonitorsConcurrently(ctx context.Context, monitors
 }
 
 // checkMonitor performs a health check on a single monitor
+// The probe and the SSL check of the URL each get the timeout of the monitor
 func (j *HealthCheckJob) checkMonitor(ctx context.Context, monitor *entities.Monitor) {
 	// Clean up monitor type to ensure reliable comparisons
 	monitor.Type = strings.ToLower(strings.TrimSpace(monitor.Type))
@@ -148,10 +193,16 @@ func (j *HealthCheckJob) checkMonitor(ctx context.Context, monitor *entities.Mon
 	
 	checkedAt := time.Now().UTC()
 
-	var success bool
-	var responseTime time.Duration
-	var statusCode int
-	var checkError error
+	checker, ok := j.checkers.Get(monitor.Type)
+	if !ok {
+		if internal.Log != nil {
+			internal.Log.Info("Falling back to HTTP check", zap.String("type", monitor.Type))
+		}
+		checker, _ = j.checkers.Get("http")
+	}
+
+	clientCertificate, clientCertificateDetails, clientCertificateErr := j.loadClientCertificate(monitor)
+	monitorSecrets, secretsErr := j.loadMonitorSecrets(monitor)
 
 	// Perform check based on monitor type
 	if internal.Log != nil {
@@ -162,63 +213,82 @@ func (j *HealthCheckJob) checkMonitor(ctx context.Context, monitor *entities.Mon
 		)
 	}
 
-	if monitor.Type == "tcp" {
-		// Parse TCP URL (expected format: host:port or tcp://host:port)
-		host, port, err := j.parseTCPAddress(monitor.URL)
-		if err != nil {
-			checkError = fmt.Errorf("invalid TCP address: %w", err)
-			success = false
-			responseTime = 0
-		} else {
-			tcpResult := j.tcpChecker.Check(host, port, time.Duration(monitor.Timeout)*time.Second)
-			success = tcpResult.Success
-			responseTime = tcpResult.ResponseTime
-			if tcpResult.Error != nil {
-				checkError = tcpResult.Error
-			}
-		}
-	} else if monitor.Type == "ping" || monitor.Type == "icmp" {
-		// Prepare host for ping (strip protocol)
-		host := monitor.URL
-		if strings.HasPrefix(host, "http://") {
-			host = host[7:]
-		} else if strings.HasPrefix(host, "https://") {
-			host = host[8:]
-		}
-		// Strip path/query/fragment if present, simplistic approach
-		// Ideally we rely on validation, but let's be safe against basic mistakes
-		// e.g. google.com/foo -> google.com
-		if idx := strings.Index(host, "/"); idx != -1 {
-			host = host[:idx]
-		}
-
-		icmpResult := j.icmpChecker.Check(ctx, host, time.Duration(monitor.Timeout)*time.Second)
-		success = icmpResult.Success
-		responseTime = icmpResult.ResponseTime
-		if icmpResult.Error != nil {
-			checkError = icmpResult.Error
-		}
+	var result *executor.CheckResult
+	if clientCertificateErr != nil {
+		// Checking without the certificate would only report the server rejecting the handshake
+		result = &executor.CheckResult{Error: clientCertificateErr}
+	} else if secretsErr != nil {
+		result = &executor.CheckResult{Error: secretsErr}
 	} else {
-		if internal.Log != nil {
-			internal.Log.Info("Falling back to HTTP check", zap.String("type", monitor.Type))
-		}
-		// HTTP/HTTPS check with expected status codes support
-		httpResult := j.httpChecker.CheckURLWithExpectedCodes(ctx, monitor.URL, time.Duration(monitor.Timeout)*time.Second, monitor.Keyword, monitor.ExpectedStatusCodes)
-		success = httpResult.Success
-		responseTime = httpResult.ResponseTime
-		statusCode = httpResult.StatusCode
-		if httpResult.Error != nil {
-			checkError = httpResult.Error
+		checkCtx, cancel := context.WithTimeout(ctx, time.Duration(monitor.Timeout)*time.Second)
+		result = checker.Check(checkCtx, &executor.CheckRequest{
+			Monitor:           monitor,
+			ClientCertificate: clientCertificate,
+			Secrets:           monitorSecrets,
+			CheckedAt:         checkedAt,
+		})
+		cancel()
+	}
+	if result.Skipped {
+		return
+	}
+
+	success := result.Success
+	responseTime := result.ResponseTime
+	statusCode := result.StatusCode
+	checkError := result.Error
+	details := result.Details
+
+	var httpTiming *executor.HTTPTiming
+	var evidence *entities.CheckEvidence
+	if httpResult := result.HTTP; httpResult != nil {
+		// Content is only compared on successful checks, an error page is reported as down instead
+		if httpResult.Success && monitor.Config.Content != nil {
+			contentDetails, err := j.checkContent(monitor, httpResult.Body, checkedAt)
+			if err != nil {
+				if internal.Log != nil {
+					internal.Log.Error("Failed to compare content with baseline",
+						zap.String("monitor_id", monitor.ID),
+						zap.Error(err),
+					)
+				}
+			} else {
+				if details == nil {
+					details = &entities.CheckDetails{}
+				}
+				details.Content = contentDetails
+			}
 		}
+		httpTiming = &httpResult.Timing
+		evidence = newCheckEvidence(monitor.ID, *httpResult, j.evidenceCfg.BodyBytes, checkedAt)
 	}
 
 	// Create check record
 	check := &entities.MonitorCheck{
 		MonitorID: monitor.ID,
 		CheckedAt: checkedAt,
+		Details:   details,
 		Success:   success,
 	}
 
+	// Record the client certificate so that ssl_expiry rules cover it
+	if clientCertificateDetails != nil {
+		if check.Details == nil {
+			check.Details = &entities.CheckDetails{}
+		}
+		check.Details.ClientCertificate = clientCertificateDetails
+
+		if daysUntil := int(time.Until(clientCertificateDetails.ExpiresAt).Hours() / 24); daysUntil < monitor.SSLAlertDays {
+			if internal.Log != nil {
+				internal.Log.Warn("Client certificate expiring soon",
+					zap.String("monitor_name", monitor.Name),
+					zap.String("client_certificate", clientCertificateDetails.Name),
+					zap.Int("days_until_expiry", daysUntil),
+				)
+			}
+		}
+	}
+
 	// Set status code if available (only for HTTP checks)
 	if statusCode > 0 {
 		check.StatusCode = sql.NullInt64{Int64: int64(statusCode), Valid: true}
@@ -232,6 +302,15 @@ func (j *HealthCheckJob) checkMonitor(ctx context.Context, monitor *entities.Mon
 		}
 	}
 
+	// Set timing breakdown (only for HTTP checks)
+	if httpTiming != nil {
+		check.DNSLookupMs = sql.NullInt64{Int64: httpTiming.DNSLookup.Milliseconds(), Valid: true}
+		check.TCPConnectMs = sql.NullInt64{Int64: httpTiming.TCPConnect.Milliseconds(), Valid: true}
+		check.TLSHandshakeMs = sql.NullInt64{Int64: httpTiming.TLSHandshake.Milliseconds(), Valid: true}
+		check.TTFBMs = sql.NullInt64{Int64: httpTiming.TTFB.Milliseconds(), Valid: true}
+		check.TransferMs = sql.NullInt64{Int64: httpTiming.Transfer.Milliseconds(), Valid: true}
+	}
+
 	// Set error message if check failed
 	if checkError != nil {
 		check.ErrorMessage = sql.NullString{
@@ -240,10 +319,18 @@ func (j *HealthCheckJob) checkMonitor(ctx context.Context, monitor *entities.Mon
 		}
 	}
 
-	// Check SSL certificate for HTTPS URLs if enabled (only for HTTP monitors)
-	if monitor.Type != "tcp" && monitor.Type != "ping" && monitor.Type != "icmp" && monitor.CheckSSL && (len(monitor.URL) >= 5 && monitor.URL[:5] == "https") {
-		sslResult := j.sslChecker.CheckSSL(monitor.URL)
-		
+	// Check SSL certificate for HTTPS and WSS URLs if enabled (only for types probing a URL, e.g. http),
+	// or record the certificate of the TLS session the check negotiated itself
+	var sslResult *executor.SSLCheckResult
+	if checker.Type().CertificateCheck && monitor.CheckSSL {
+		if result.SSL != nil {
+			sslResult = result.SSL
+		} else if strings.HasPrefix(monitor.URL, "https") || strings.HasPrefix(monitor.URL, "wss://") {
+			urlSSLResult := j.checkMonitorSSL(ctx, monitor, clientCertificate, monitorSecrets)
+			sslResult = &urlSSLResult
+		}
+	}
+	if sslResult != nil {
 		// Set SSL validity
 		check.SSLValid = sql.NullBool{
 			Bool:  sslResult.Valid,
@@ -258,6 +345,39 @@ func (j *HealthCheckJob) checkMonitor(ctx context.Context, monitor *entities.Mon
 			}
 		}
 
+		// Record the presented chain and TLS posture
+		if len(sslResult.Chain) > 0 {
+			sslDetails := newSSLCheckDetails(*sslResult)
+			if previous, err := j.monitorRepo.GetLatestSSLCheck(monitor.ID); err != nil {
+				if internal.Log != nil {
+					internal.Log.Error("Failed to get previous SSL check",
+						zap.String("monitor_name", monitor.Name),
+						zap.Error(err),
+					)
+				}
+			} else if previous != nil && previous.Details != nil && previous.Details.SSL != nil {
+				if certificateChangedUnexpectedly(previous.Details.SSL, sslDetails, monitor.SSLAlertDays, checkedAt) {
+					sslDetails.PreviousFingerprint = previous.Details.SSL.Fingerprint
+				}
+			}
+
+			if check.Details == nil {
+				check.Details = &entities.CheckDetails{}
+			}
+			check.Details.SSL = sslDetails
+		}
+
+		// A certificate revoked by its CA fails the check
+		if sslResult.Revocation.Status == executor.RevocationRevoked {
+			success = false
+			checkError = fmt.Errorf("SSL %w", sslResult.Error)
+			check.Success = false
+			check.ErrorMessage = sql.NullString{
+				String: checkError.Error(),
+				Valid:  true,
+			}
+		}
+
 		// Log SSL check result
 		if sslResult.Error != nil {
 			if internal.Log != nil {
@@ -294,6 +414,19 @@ func (j *HealthCheckJob) checkMonitor(ctx context.Context, monitor *entities.Mon
 		return
 	}
 
+	// Keep the response of failed checks, and of a sample of successful ones
+	if evidence != nil && j.evidenceRepo != nil && keepEvidence(check.Success, j.evidenceCfg.SuccessSampleRate) {
+		evidence.CheckID = check.ID
+		if err := j.evidenceRepo.Create(evidence); err != nil {
+			if internal.Log != nil {
+				internal.Log.Error("Failed to save check evidence",
+					zap.String("monitor_name", monitor.Name),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+
 	// Update last_checked_at timestamp
 	if err := j.monitorRepo.UpdateLastCheckedAt(monitor.ID, checkedAt); err != nil {
 		if internal.Log != nil {
@@ -309,47 +442,23 @@ func (j *HealthCheckJob) checkMonitor(ctx context.Context, monitor *entities.Mon
 	if success {
 		internal.MonitorCheckTotal.WithLabelValues("success").Inc()
 		if internal.Log != nil {
-			if monitor.Type == "tcp" {
-				internal.Log.Info("TCP monitor check successful",
-					zap.String("monitor_name", monitor.Name),
-					zap.String("address", monitor.URL),
-					zap.Int64("response_time_ms", responseTime.Milliseconds()),
-				)
-			} else if monitor.Type == "ping" || monitor.Type == "icmp" {
-				internal.Log.Info("Ping monitor check successful",
-					zap.String("monitor_name", monitor.Name),
-					zap.String("address", monitor.URL),
-					zap.Int64("response_time_ms", responseTime.Milliseconds()),
-				)
-			} else {
-				internal.Log.Info("HTTP monitor check successful",
-					zap.String("monitor_name", monitor.Name),
-					zap.Int("status_code", statusCode),
-					zap.Int64("response_time_ms", responseTime.Milliseconds()),
-				)
-			}
+			internal.Log.Info("Monitor check successful",
+				zap.String("monitor_name", monitor.Name),
+				zap.String("type", monitor.Type),
+				zap.String("url", monitor.URL),
+				zap.Int("status_code", statusCode),
+				zap.Int64("response_time_ms", responseTime.Milliseconds()),
+			)
 		}
 	} else {
 		internal.MonitorCheckTotal.WithLabelValues("failure").Inc()
 		if internal.Log != nil {
-			if monitor.Type == "tcp" {
-				internal.Log.Warn("TCP monitor check failed",
-					zap.String("monitor_name", monitor.Name),
-					zap.String("address", monitor.URL),
-					zap.Error(checkError),
-				)
-			} else if monitor.Type == "ping" || monitor.Type == "icmp" {
-				internal.Log.Warn("Ping monitor check failed",
-					zap.String("monitor_name", monitor.Name),
-					zap.String("address", monitor.URL),
-					zap.Error(checkError),
-				)
-			} else {
-				internal.Log.Warn("HTTP monitor check failed",
-					zap.String("monitor_name", monitor.Name),
-					zap.Error(checkError),
-				)
-			}
+			internal.Log.Warn("Monitor check failed",
+				zap.String("monitor_name", monitor.Name),
+				zap.String("type", monitor.Type),
+				zap.String("url", monitor.URL),
+				zap.Error(checkError),
+			)
 		}
 	}
 
@@ -387,33 +496,256 @@ func (j *HealthCheckJob) broadcastMonitorCheckEvent(monitor *entities.Monitor, c
 		data["ssl_expires_at"] = check.SSLExpiresAt.Time.Format(time.RFC3339)
 	}
 
+	if check.Details != nil {
+		data["details"] = check.Details
+	}
+
 	// Send broadcast request to backend
 	broadcastEvent("monitor_check", data, monitor.UserID)
 }
 
-// parseTCPAddress parses a TCP address string and returns host and port
-// Supports formats: "host:port" or "tcp://host:port"
-func (j *HealthCheckJob) parseTCPAddress(address string) (string, int, error) {
-	// Remove tcp:// prefix if present
-	if len(address) > 6 && address[:6] == "tcp://" {
-		address = address[6:]
+// checkMonitorSSL checks the certificate of an HTTPS monitor, connecting to the pinned address or
+// through the proxy and sending the SNI override and client certificate when the monitor has them
+// The check, revocation lookup included, gets its own timeout of the monitor, ctx must not carry
+// the deadline of the probe that preceded it
+func (j *HealthCheckJob) checkMonitorSSL(ctx context.Context, monitor *entities.Monitor, clientCertificate *tls.Certificate, monitorSecrets *entities.MonitorSecrets) executor.SSLCheckResult {
+	if monitor.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(monitor.Timeout)*time.Second)
+		defer cancel()
 	}
 
-	// Parse host:port
-	host, portStr, err := net.SplitHostPort(address)
+	host, port, serverName, err := monitorSSLEndpoint(monitor)
 	if err != nil {
-		return "", 0, fmt.Errorf("invalid address format: %w", err)
+		return executor.SSLCheckResult{Error: err}
 	}
+	return monitorSSLChecker(j.sslChecker, monitor, clientCertificate, monitorSecrets).CheckEndpoint(ctx, host, port, serverName, "")
+}
 
-	// Parse port number
-	port, err := strconv.Atoi(portStr)
+// monitorSSLEndpoint returns the address the certificate of a monitor is served on, the
+// pinned address when connection.resolve_to is set, and the name sent as SNI
+func monitorSSLEndpoint(monitor *entities.Monitor) (host, port, serverName string, err error) {
+	host, port, err = monitorTLSAddress(monitor)
 	if err != nil {
-		return "", 0, fmt.Errorf("invalid port number: %w", err)
+		return "", "", "", err
 	}
 
-	if port < 1 || port > 65535 {
-		return "", 0, fmt.Errorf("port number out of range: %d", port)
+	serverName = host
+	if connection := monitor.Config.Connection; connection != nil {
+		if connection.ResolveTo != "" {
+			host = connection.ResolveTo
+		}
+		if connection.ServerName != "" {
+			serverName = connection.ServerName
+		}
 	}
+	return host, port, serverName, nil
+}
 
+// monitorTLSAddress returns the host and port of a monitor connecting over TLS: the https:// or
+// wss:// URL, 443 when it has no port, or the host:port of tls:// TCP and gRPC monitors
+func monitorTLSAddress(monitor *entities.Monitor) (host, port string, err error) {
+	if !strings.Contains(monitor.URL, "://") {
+		host, port, err = net.SplitHostPort(monitor.URL)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid address: %w", err)
+		}
+		return host, port, nil
+	}
+
+	parsedURL, err := url.Parse(monitor.URL)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse URL: %w", err)
+	}
+	host, port = parsedURL.Hostname(), parsedURL.Port()
+	if port == "" {
+		port = "443"
+	}
 	return host, port, nil
 }
+
+// monitorSSLChecker returns sslChecker connecting like the checks of monitor: presenting its client
+// certificate and going through its proxy
+func monitorSSLChecker(sslChecker *executor.SSLChecker, monitor *entities.Monitor, clientCertificate *tls.Certificate, monitorSecrets *entities.MonitorSecrets) *executor.SSLChecker {
+	if clientCertificate != nil {
+		sslChecker = sslChecker.WithClientCertificate(clientCertificate)
+	}
+	if proxyConfig := executor.MonitorProxy(monitor, monitorSecrets); proxyConfig != nil {
+		sslChecker = sslChecker.WithProxy(proxyConfig)
+	}
+	return sslChecker
+}
+
+// checkContent compares the selected content of a response with the accepted baseline of the monitor
+// The first check, and the first after the selection settings changed, takes the baseline
+func (j *HealthCheckJob) checkContent(monitor *entities.Monitor, body []byte, checkedAt time.Time) (*entities.ContentCheckDetails, error) {
+	if j.contentRepo == nil {
+		return nil, fmt.Errorf("content baselines are not available")
+	}
+
+	cfg := monitor.Config.Content
+	details := &entities.ContentCheckDetails{}
+	text, err := content.Extract(body, cfg)
+	if err != nil {
+		// Compare what was served instead, e.g. an HTML maintenance page where JSON was expected
+		details.Error = err.Error()
+		text = strings.TrimSpace(strings.ReplaceAll(string(body), "\r\n", "\n"))
+	}
+	details.Hash = content.Hash(text)
+	details.Size = len(text)
+
+	configHash := content.ConfigHash(cfg)
+	baseline, err := j.contentRepo.GetByMonitorID(monitor.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	if baseline == nil || baseline.ConfigHash != configHash {
+		err := j.contentRepo.Reset(&entities.ContentBaseline{
+			MonitorID:       monitor.ID,
+			ConfigHash:      configHash,
+			BaselineHash:    details.Hash,
+			BaselineContent: text,
+			AcceptedAt:      checkedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		details.BaselineHash = details.Hash
+		return details, nil
+	}
+
+	// The snapshot is only rewritten when the content differs from the stored one
+	if baseline.CurrentHash != details.Hash {
+		if err := j.contentRepo.UpdateCurrent(monitor.ID, details.Hash, text, checkedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	details.BaselineHash = baseline.BaselineHash
+	details.Changed = details.Hash != baseline.BaselineHash
+	return details, nil
+}
+
+// newSSLCheckDetails converts the result of an SSL check into check details
+func newSSLCheckDetails(result executor.SSLCheckResult) *entities.SSLCheckDetails {
+	details := &entities.SSLCheckDetails{
+		Fingerprint:   result.Fingerprint,
+		HostnameMatch: result.HostnameMatch,
+		TLSVersion:    result.TLSVersion,
+		CipherSuite:   result.CipherSuite,
+		Weaknesses:    result.Weaknesses,
+	}
+	if result.Error != nil {
+		details.Error = result.Error.Error()
+	}
+	if result.Revocation.Status != "" {
+		details.Revocation = &entities.RevocationDetails{
+			Status:     result.Revocation.Status,
+			Source:     result.Revocation.Source,
+			ResponseMs: executor.DurationToMs(result.Revocation.ResponseTime),
+			Reason:     result.Revocation.Reason,
+		}
+		if !result.Revocation.RevokedAt.IsZero() {
+			revokedAt := result.Revocation.RevokedAt
+			details.Revocation.RevokedAt = &revokedAt
+		}
+		if result.Revocation.Error != nil {
+			details.Revocation.Error = result.Revocation.Error.Error()
+		}
+	}
+	details.Chain = newCertificateDetails(result.Chain)
+	return details
+}
+
+// newCertificateDetails converts a presented certificate chain into check details
+func newCertificateDetails(chain []executor.CertificateInfo) []entities.CertificateDetails {
+	var details []entities.CertificateDetails
+	for _, cert := range chain {
+		details = append(details, entities.CertificateDetails{
+			Subject:            cert.Subject,
+			Issuer:             cert.Issuer,
+			SANs:               cert.SANs,
+			NotBefore:          cert.NotBefore,
+			NotAfter:           cert.NotAfter,
+			KeyType:            cert.KeyType,
+			KeyBits:            cert.KeyBits,
+			SignatureAlgorithm: cert.SignatureAlgorithm,
+			Fingerprint:        cert.Fingerprint,
+		})
+	}
+	return details
+}
+
+// newCheckEvidence captures the response of an HTTP check with at most bodyBytes of its body,
+// nil when the check never reached a server
+func newCheckEvidence(monitorID string, result executor.HTTPCheckResult, bodyBytes int, checkedAt time.Time) *entities.CheckEvidence {
+	if result.RemoteIP == "" && result.Headers == nil {
+		return nil
+	}
+
+	evidence := &entities.CheckEvidence{
+		MonitorID: monitorID,
+		RemoteIP:  result.RemoteIP,
+		Headers:   entities.EvidenceHeaders{},
+		CreatedAt: checkedAt,
+	}
+
+	for name, values := range result.Headers {
+		// Session cookies are credentials, only their names are kept
+		if name == "Set-Cookie" {
+			redacted := make([]string, len(values))
+			for i, value := range values {
+				cookieName, _, _ := strings.Cut(value, "=")
+				redacted[i] = cookieName + "=[redacted]"
+			}
+			values = redacted
+		}
+		evidence.Headers[name] = values
+	}
+
+	body := result.Body
+	if len(body) > bodyBytes {
+		body = body[:bodyBytes]
+		evidence.BodyTruncated = true
+	}
+	// Binary bodies are stored as text, PostgreSQL rejects NUL bytes and invalid UTF-8
+	evidence.BodySnippet = strings.ReplaceAll(strings.ToValidUTF8(string(body), "\uFFFD"), "\x00", "\uFFFD")
+
+	if result.TLS != nil {
+		evidence.TLS = &entities.SSLCheckDetails{
+			HostnameMatch: result.TLS.HostnameMatch,
+			TLSVersion:    result.TLS.Version,
+			CipherSuite:   result.TLS.CipherSuite,
+			Weaknesses:    result.TLS.Weaknesses,
+			Chain:         newCertificateDetails(result.TLS.Chain),
+		}
+		if len(result.TLS.Chain) > 0 {
+			evidence.TLS.Fingerprint = result.TLS.Chain[0].Fingerprint
+		}
+	}
+
+	return evidence
+}
+
+// keepEvidence reports whether the evidence of a check is stored: always for failed checks,
+// for the given fraction of successful ones
+func keepEvidence(success bool, successSampleRate float64) bool {
+	if !success {
+		return true
+	}
+	return successSampleRate > 0 && rand.Float64() < successSampleRate
+}
+
+// certificateChangedUnexpectedly reports whether the leaf certificate was replaced although
+// the previous one was not yet within alertDays of its expiry (a renewal is expected then)
+func certificateChangedUnexpectedly(previous, current *entities.SSLCheckDetails, alertDays int, now time.Time) bool {
+	if previous.Fingerprint == "" || previous.Fingerprint == current.Fingerprint {
+		return false
+	}
+	if len(previous.Chain) == 0 {
+		return true
+	}
+	renewalWindow := previous.Chain[0].NotAfter.AddDate(0, 0, -alertDays)
+	return now.Before(renewalWindow)
+}
@@ -3,10 +3,13 @@ package jobs
 import (
 	"context"
 	"database/sql"
+	"net/http"
 	"testing"
 	"time"
 
 	"github.com/eovipmak/v-insight/shared/domain/entities"
+	"github.com/eovipmak/v-insight/worker/internal/config"
+	"github.com/eovipmak/v-insight/worker/internal/executor"
 )
 
 // TestMonitor_StructFields tests that Monitor struct has all required fields
@@ -72,14 +75,14 @@ func TestMonitorCheck_StructFields(t *testing.T) {
 
 // TestHealthCheckJob_NewHealthCheckJob tests that NewHealthCheckJob creates a valid job
 func TestHealthCheckJob_NewHealthCheckJob(t *testing.T) {
-	job := NewHealthCheckJob(nil)
+	job := NewHealthCheckJob(nil, nil, nil, nil, nil, config.DomainConfig{}, config.EvidenceConfig{}, config.DockerConfig{})
 	
 	if job == nil {
 		t.Fatal("Expected job to be created, got nil")
 	}
 	
-	if job.httpChecker == nil {
-		t.Error("Expected httpChecker to be initialized, got nil")
+	if _, ok := job.checkers.Get("http"); !ok {
+		t.Error("Expected an http checker to be registered")
 	}
 	
 	if job.Name() != "HealthCheckJob" {
@@ -89,7 +92,7 @@ func TestHealthCheckJob_NewHealthCheckJob(t *testing.T) {
 
 // TestHealthCheckJob_CheckMonitorsConcurrently_EmptyList tests concurrent checking with empty list
 func TestHealthCheckJob_CheckMonitorsConcurrently_EmptyList(t *testing.T) {
-	job := NewHealthCheckJob(nil)
+	job := NewHealthCheckJob(nil, nil, nil, nil, nil, config.DomainConfig{}, config.EvidenceConfig{}, config.DockerConfig{})
 	ctx := context.Background()
 	
 	// Should handle empty list gracefully
@@ -104,3 +107,128 @@ func TestHealthCheckJob_CheckMonitorsConcurrently_EmptyList(t *testing.T) {
 	
 	job.checkMonitorsConcurrently(ctx, monitors)
 }
+
+// TestCertificateChangedUnexpectedly tests which certificate replacements are reported
+func TestCertificateChangedUnexpectedly(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	previous := func(notAfter time.Time) *entities.SSLCheckDetails {
+		return &entities.SSLCheckDetails{
+			Fingerprint: "aaaa",
+			Chain:       []entities.CertificateDetails{{Fingerprint: "aaaa", NotAfter: notAfter}},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		previous *entities.SSLCheckDetails
+		current  string
+		want     bool
+	}{
+		{"same certificate", previous(now.AddDate(0, 2, 0)), "aaaa", false},
+		{"replaced long before expiry", previous(now.AddDate(0, 2, 0)), "bbbb", true},
+		{"renewed within alert window", previous(now.AddDate(0, 0, 20)), "bbbb", false},
+		{"no previous fingerprint", &entities.SSLCheckDetails{}, "bbbb", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := &entities.SSLCheckDetails{Fingerprint: tt.current}
+			if got := certificateChangedUnexpectedly(tt.previous, current, 30, now); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestNewCheckEvidence tests what is kept of the response of an HTTP check
+func TestNewCheckEvidence(t *testing.T) {
+	checkedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	if evidence := newCheckEvidence("monitor-id", executor.HTTPCheckResult{}, 16, checkedAt); evidence != nil {
+		t.Errorf("Expected no evidence when no server was reached, got %+v", evidence)
+	}
+
+	result := executor.HTTPCheckResult{
+		RemoteIP: "192.0.2.10",
+		Headers: http.Header{
+			"Content-Type": {"text/html"},
+			"Set-Cookie":   {"session=abc123; Path=/; HttpOnly", "theme=dark"},
+		},
+		Body: []byte("<h1>Bad Gateway</h1>\x00\xff and more"),
+		TLS: &executor.TLSInfo{
+			Version:       "TLS 1.3",
+			CipherSuite:   "TLS_AES_128_GCM_SHA256",
+			HostnameMatch: true,
+			Chain:         []executor.CertificateInfo{{Subject: "example.com", Fingerprint: "aaaa"}},
+		},
+	}
+
+	evidence := newCheckEvidence("monitor-id", result, 22, checkedAt)
+	if evidence == nil {
+		t.Fatal("Expected evidence, got nil")
+	}
+	if evidence.MonitorID != "monitor-id" || evidence.RemoteIP != "192.0.2.10" || !evidence.CreatedAt.Equal(checkedAt) {
+		t.Errorf("Unexpected evidence: %+v", evidence)
+	}
+	if got := evidence.Headers["Set-Cookie"]; len(got) != 2 || got[0] != "session=[redacted]" || got[1] != "theme=[redacted]" {
+		t.Errorf("Expected cookie values to be redacted, got %v", got)
+	}
+	if got := evidence.Headers["Content-Type"]; len(got) != 1 || got[0] != "text/html" {
+		t.Errorf("Expected Content-Type to be kept, got %v", got)
+	}
+	if evidence.BodySnippet != "<h1>Bad Gateway</h1>��" || !evidence.BodyTruncated {
+		t.Errorf("Expected the sanitized first 22 bytes, got %q (truncated %v)", evidence.BodySnippet, evidence.BodyTruncated)
+	}
+	if evidence.TLS == nil || evidence.TLS.TLSVersion != "TLS 1.3" || evidence.TLS.Fingerprint != "aaaa" || len(evidence.TLS.Chain) != 1 {
+		t.Errorf("Expected the TLS connection to be kept, got %+v", evidence.TLS)
+	}
+
+	if evidence := newCheckEvidence("monitor-id", result, 1024, checkedAt); evidence.BodyTruncated {
+		t.Errorf("Expected a body shorter than the limit not to be truncated")
+	}
+}
+
+// TestKeepEvidence tests which checks keep their evidence
+func TestKeepEvidence(t *testing.T) {
+	if !keepEvidence(false, 0) {
+		t.Error("Expected failed checks to always keep their evidence")
+	}
+	if keepEvidence(true, 0) {
+		t.Error("Expected successful checks not to keep evidence without sampling")
+	}
+	if !keepEvidence(true, 1) {
+		t.Error("Expected every successful check to keep evidence with a sample rate of 1")
+	}
+}
+
+func TestMonitorSSLEndpoint(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		connection     *entities.ConnectionConfig
+		wantHost       string
+		wantPort       string
+		wantServerName string
+	}{
+		{"monitor URL", "https://example.com/health", nil, "example.com", "443", "example.com"},
+		{"explicit port", "https://example.com:8443", nil, "example.com", "8443", "example.com"},
+		{"pinned address", "https://example.com", &entities.ConnectionConfig{ResolveTo: "203.0.113.10"}, "203.0.113.10", "443", "example.com"},
+		{"SNI override", "https://203.0.113.10", &entities.ConnectionConfig{ServerName: "example.com"}, "203.0.113.10", "443", "example.com"},
+		{"WebSocket URL", "wss://example.com/socket", nil, "example.com", "443", "example.com"},
+		{"TLS TCP address", "tls://example.com:6380", nil, "example.com", "6380", "example.com"},
+		{"gRPC address", "example.com:50051", nil, "example.com", "50051", "example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			monitor := &entities.Monitor{URL: tt.url, Config: entities.MonitorConfig{Connection: tt.connection}}
+			host, port, serverName, err := monitorSSLEndpoint(monitor)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if host != tt.wantHost || port != tt.wantPort || serverName != tt.wantServerName {
+				t.Errorf("Expected %s:%s (%s), got %s:%s (%s)", tt.wantHost, tt.wantPort, tt.wantServerName, host, port, serverName)
+			}
+		})
+	}
+}
@@ -2,18 +2,24 @@ package jobs
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+	"github.com/eovipmak/v-insight/worker/internal/config"
+	"github.com/eovipmak/v-insight/worker/internal/executor"
 )
 
 func TestHealthCheckJob_Name(t *testing.T) {
-	job := NewHealthCheckJob(nil)
+	job := NewHealthCheckJob(nil, nil, nil, nil, nil, config.DomainConfig{}, config.EvidenceConfig{}, config.DockerConfig{})
 	if job.Name() != "HealthCheckJob" {
 		t.Fatalf("Expected job name 'HealthCheckJob', got '%s'", job.Name())
 	}
 }
 
 func TestHealthCheckJob_Run_NilDB(t *testing.T) {
-	job := NewHealthCheckJob(nil)
+	job := NewHealthCheckJob(nil, nil, nil, nil, nil, config.DomainConfig{}, config.EvidenceConfig{}, config.DockerConfig{})
 	ctx := context.Background()
 
 	// Should handle nil DB gracefully by returning an error
@@ -54,14 +60,14 @@ func TestHealthCheckJob_Run_WithMockDB(t *testing.T) {
 }
 
 func TestSSLCheckJob_Name(t *testing.T) {
-	job := NewSSLCheckJob(nil)
+	job := NewSSLCheckJob(nil, nil, nil)
 	if job.Name() != "SSLCheckJob" {
 		t.Fatalf("Expected job name 'SSLCheckJob', got '%s'", job.Name())
 	}
 }
 
 func TestSSLCheckJob_Run(t *testing.T) {
-	job := NewSSLCheckJob(nil)
+	job := NewSSLCheckJob(nil, nil, nil)
 	ctx := context.Background()
 
 	err := job.Run(ctx)
@@ -69,3 +75,44 @@ func TestSSLCheckJob_Run(t *testing.T) {
 		t.Fatalf("Expected no error, got: %v", err)
 	}
 }
+
+func TestFillCertificate(t *testing.T) {
+	checkedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	leafExpiry := checkedAt.AddDate(0, 3, 0)
+	chainExpiry := checkedAt.AddDate(0, 0, 10)
+
+	certificate := &entities.Certificate{}
+	fillCertificate(certificate, executor.SSLCheckResult{
+		Valid:       false,
+		Error:       errors.New("x509: certificate signed by unknown authority"),
+		ExpiresAt:   chainExpiry,
+		Fingerprint: "aaaa",
+		Chain: []executor.CertificateInfo{
+			{Subject: "example.com", Issuer: "Test CA", SANs: []string{"example.com"}, NotAfter: leafExpiry, Fingerprint: "aaaa"},
+			{Subject: "Test CA", Issuer: "Test Root", NotAfter: chainExpiry},
+		},
+	}, checkedAt)
+
+	if certificate.Subject != "example.com" || certificate.Issuer != "Test CA" {
+		t.Errorf("Expected leaf subject and issuer, got %q, %q", certificate.Subject, certificate.Issuer)
+	}
+	if certificate.NotAfter == nil || !certificate.NotAfter.Equal(leafExpiry) {
+		t.Errorf("Expected leaf expiry %v, got %v", leafExpiry, certificate.NotAfter)
+	}
+	if certificate.ExpiresAt == nil || !certificate.ExpiresAt.Equal(chainExpiry) {
+		t.Errorf("Expected chain expiry %v, got %v", chainExpiry, certificate.ExpiresAt)
+	}
+	if certificate.Valid || certificate.Error == nil {
+		t.Errorf("Expected invalid certificate with error")
+	}
+	if certificate.Details == nil || len(certificate.Details.Chain) != 2 {
+		t.Errorf("Expected chain details to be recorded")
+	}
+
+	// A failed connection leaves the certificate fields empty
+	unreachable := &entities.Certificate{}
+	fillCertificate(unreachable, executor.SSLCheckResult{Error: errors.New("connection refused")}, checkedAt)
+	if unreachable.ExpiresAt != nil || unreachable.Error == nil || !unreachable.CheckedAt.Equal(checkedAt) {
+		t.Errorf("Expected only the error and check time, got %+v", unreachable)
+	}
+}
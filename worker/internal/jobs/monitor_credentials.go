@@ -0,0 +1,68 @@
+package jobs
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+	"github.com/eovipmak/v-insight/shared/domain/repository"
+	"github.com/eovipmak/v-insight/shared/secrets"
+)
+
+// monitorCredentials loads the credentials monitors connect with, for the jobs that check them
+type monitorCredentials struct {
+	clientCertRepo repository.ClientCertificateRepository
+	cipher         *secrets.Cipher // decrypts the stored secrets of monitors and client certificates
+}
+
+// loadMonitorSecrets decrypts the stored secrets of a monitor, e.g. the DSN of database monitors
+// Returns nil without error when the monitor has none
+func (c *monitorCredentials) loadMonitorSecrets(monitor *entities.Monitor) (*entities.MonitorSecrets, error) {
+	if monitor.EncryptedSecrets == nil {
+		return nil, nil
+	}
+	if c.cipher == nil {
+		return nil, fmt.Errorf("secrets encryption key not configured")
+	}
+
+	var monitorSecrets entities.MonitorSecrets
+	if err := c.cipher.DecryptJSON(*monitor.EncryptedSecrets, &monitorSecrets); err != nil {
+		// Usually a SECRETS_ENCRYPTION_KEY mismatch between backend and worker
+		return nil, fmt.Errorf("failed to read monitor credentials: %w", err)
+	}
+	return &monitorSecrets, nil
+}
+
+// loadClientCertificate decrypts the client certificate a monitor presents for mutual TLS
+// Returns nil without error when the monitor has none
+func (c *monitorCredentials) loadClientCertificate(monitor *entities.Monitor) (*tls.Certificate, *entities.ClientCertificateCheckDetails, error) {
+	if monitor.ClientCertificateID == nil {
+		return nil, nil, nil
+	}
+	if c.clientCertRepo == nil || c.cipher == nil {
+		return nil, nil, fmt.Errorf("client certificates are not available")
+	}
+
+	stored, err := c.clientCertRepo.GetByID(*monitor.ClientCertificateID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load client certificate: %w", err)
+	}
+
+	var certificateSecrets entities.ClientCertificateSecrets
+	if err := c.cipher.DecryptJSON(stored.EncryptedSecrets, &certificateSecrets); err != nil {
+		// Usually a SECRETS_ENCRYPTION_KEY mismatch between backend and worker
+		return nil, nil, fmt.Errorf("failed to read client certificate: %w", err)
+	}
+
+	certificate, err := tls.X509KeyPair([]byte(certificateSecrets.Certificate), []byte(certificateSecrets.PrivateKey))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid client certificate %q: %w", stored.Name, err)
+	}
+
+	return &certificate, &entities.ClientCertificateCheckDetails{
+		ID:          stored.ID,
+		Name:        stored.Name,
+		Fingerprint: stored.Fingerprint,
+		ExpiresAt:   stored.NotAfter,
+	}, nil
+}
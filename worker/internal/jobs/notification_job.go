@@ -3,7 +3,9 @@ package jobs
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/mail"
@@ -26,6 +28,7 @@ type WebhookPayload struct {
 	Status      string `json:"status"`
 	Message     string `json:"message"`
 	Timestamp   string `json:"timestamp"`
+	EvidenceURL string `json:"evidence_url,omitempty"` // response of the check that failed, when one was kept
 }
 
 // DiscordWebhookPayload represents the Discord-specific webhook format
@@ -54,23 +57,29 @@ type DiscordEmbedField struct {
 type NotificationJob struct {
 	incidentRepo     repository.IncidentRepository
 	alertChannelRepo repository.AlertChannelRepository
+	evidenceRepo     repository.CheckEvidenceRepository
 	httpClient       *http.Client
 	smtpConfig       config.SMTPConfig
+	appURL           string // frontend base URL for evidence links, no links when empty
 }
 
 // NewNotificationJob creates a new notification job
 func NewNotificationJob(
 	incidentRepo repository.IncidentRepository,
 	alertChannelRepo repository.AlertChannelRepository,
+	evidenceRepo repository.CheckEvidenceRepository,
 	smtpConfig config.SMTPConfig,
+	appURL string,
 ) *NotificationJob {
 	return &NotificationJob{
 		incidentRepo:     incidentRepo,
 		alertChannelRepo: alertChannelRepo,
+		evidenceRepo:     evidenceRepo,
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
 		smtpConfig: smtpConfig,
+		appURL:     appURL,
 	}
 }
 
@@ -177,6 +186,8 @@ func (j *NotificationJob) processIncidentNotifications(incident *entities.Incide
 		return 0, nil
 	}
 
+	evidenceURL := j.evidenceURL(incident)
+
 	sentCount := 0
 	for _, channel := range channels {
 		if !channel.Enabled {
@@ -186,11 +197,11 @@ func (j *NotificationJob) processIncidentNotifications(incident *entities.Incide
 		var err error
 		switch channel.Type {
 		case "webhook":
-			err = j.sendWebhookNotification(incident, channel)
+			err = j.sendWebhookNotification(incident, channel, evidenceURL)
 		case "discord":
-			err = j.sendDiscordNotification(incident, channel)
+			err = j.sendDiscordNotification(incident, channel, evidenceURL)
 		case "email":
-			err = j.sendEmailNotification(incident, channel)
+			err = j.sendEmailNotification(incident, channel, evidenceURL)
 		default:
 			if internal.Log != nil {
 				internal.Log.Warn("Unsupported channel type",
@@ -226,8 +237,31 @@ func (j *NotificationJob) processIncidentNotifications(incident *entities.Incide
 	return sentCount, nil
 }
 
+// evidenceURL returns the frontend link to the evidence of the check that opened a down incident,
+// empty when the incident is not an open down incident or its check kept no evidence
+func (j *NotificationJob) evidenceURL(incident *entities.Incident) string {
+	if incident.Status != "open" || incident.TriggerType != "down" || incident.CheckID == nil ||
+		j.evidenceRepo == nil || j.appURL == "" {
+		return ""
+	}
+
+	evidence, err := j.evidenceRepo.GetByCheckID(*incident.CheckID)
+	if err != nil {
+		// Not every check keeps evidence, and it may have expired since
+		if !errors.Is(err, sql.ErrNoRows) && internal.Log != nil {
+			internal.Log.Warn("Failed to look up check evidence for incident",
+				zap.String("incident_id", incident.ID),
+				zap.Error(err),
+			)
+		}
+		return ""
+	}
+
+	return fmt.Sprintf("%s/user/monitors/%s/checks/%s", j.appURL, incident.MonitorID, evidence.CheckID)
+}
+
 // sendWebhookNotification sends a generic webhook notification
-func (j *NotificationJob) sendWebhookNotification(incident *entities.Incident, channel *entities.AlertChannel) error {
+func (j *NotificationJob) sendWebhookNotification(incident *entities.Incident, channel *entities.AlertChannel, evidenceURL string) error {
 	// Get webhook URL from config
 	webhookURL, ok := channel.Config["url"].(string)
 	if !ok || webhookURL == "" {
@@ -242,6 +276,7 @@ func (j *NotificationJob) sendWebhookNotification(incident *entities.Incident, c
 		Status:      incident.Status,
 		Message:     incident.TriggerValue,
 		Timestamp:   incident.StartedAt.Format(time.RFC3339),
+		EvidenceURL: evidenceURL,
 	}
 
 	// Marshal to JSON
@@ -272,7 +307,7 @@ func (j *NotificationJob) sendWebhookNotification(incident *entities.Incident, c
 }
 
 // sendDiscordNotification sends a Discord-specific webhook notification
-func (j *NotificationJob) sendDiscordNotification(incident *entities.Incident, channel *entities.AlertChannel) error {
+func (j *NotificationJob) sendDiscordNotification(incident *entities.Incident, channel *entities.AlertChannel, evidenceURL string) error {
 	// Get webhook URL from config
 	webhookURL, ok := channel.Config["url"].(string)
 	if !ok || webhookURL == "" {
@@ -345,6 +380,13 @@ func (j *NotificationJob) sendDiscordNotification(incident *entities.Incident, c
 			"text": fmt.Sprintf("Incident ID: %s", incident.ID),
 		},
 	}
+	if evidenceURL != "" {
+		embed.Fields = append(embed.Fields, DiscordEmbedField{
+			Name:   "Evidence",
+			Value:  fmt.Sprintf("[Response of the failed check](%s)", evidenceURL),
+			Inline: false,
+		})
+	}
 
 	// Create Discord payload
 	payload := DiscordWebhookPayload{
@@ -379,7 +421,7 @@ func (j *NotificationJob) sendDiscordNotification(incident *entities.Incident, c
 }
 
 // sendEmailNotification sends an email notification
-func (j *NotificationJob) sendEmailNotification(incident *entities.Incident, channel *entities.AlertChannel) error {
+func (j *NotificationJob) sendEmailNotification(incident *entities.Incident, channel *entities.AlertChannel, evidenceURL string) error {
 	to, ok := channel.Config["to"].(string)
 	if !ok || to == "" {
 		return fmt.Errorf("email address not configured")
@@ -441,6 +483,11 @@ func (j *NotificationJob) sendEmailNotification(incident *entities.Incident, cha
 		title = "✅ Resolved: " + incident.MonitorName
 	}
 
+	evidenceLine := ""
+	if evidenceURL != "" {
+		evidenceLine = "Evidence: " + evidenceURL + "\n"
+	}
+
 	// Simple text body
 	body := fmt.Sprintf(`Subject: %s
 From: %s
@@ -453,10 +500,10 @@ URL: %s
 Status: %s
 Message: %s
 Time: %s
-
+%s
 --
 V-Insight Monitoring
-`, title, smtpFrom, to, title, incident.MonitorName, incident.MonitorURL, incident.Status, incident.TriggerValue, incident.StartedAt.Format(time.RFC3339))
+`, title, smtpFrom, to, title, incident.MonitorName, incident.MonitorURL, incident.Status, incident.TriggerValue, incident.StartedAt.Format(time.RFC3339), evidenceLine)
 
 	// Replace \n with \r\n for SMTP compliance
 	body = strings.ReplaceAll(body, "\n", "\r\n")
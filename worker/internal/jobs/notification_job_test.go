@@ -1,10 +1,14 @@
 package jobs
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/eovipmak/v-insight/shared/domain/entities"
+	"github.com/eovipmak/v-insight/worker/internal/config"
 )
 
 // TestIncident_StructFields tests that Incident struct has all required fields for notification
@@ -163,3 +167,47 @@ func TestNotificationJob_Name(t *testing.T) {
 		t.Errorf("Expected job name '%s', got '%s'", expectedName, job.Name())
 	}
 }
+
+// TestNotificationJob_EvidenceURL tests the link to the evidence of the check that opened an incident
+func TestNotificationJob_EvidenceURL(t *testing.T) {
+	repo := &fakeEvidenceRepo{evidence: map[string]*entities.CheckEvidence{"check-id": {CheckID: "check-id"}}}
+	job := NewNotificationJob(nil, nil, repo, config.SMTPConfig{}, "https://monitor.example.com")
+	checkID, otherCheckID := "check-id", "other-check-id"
+	incident := &entities.Incident{ID: "incident-id", MonitorID: "monitor-id", Status: "open", TriggerType: "down", CheckID: &checkID}
+
+	if got, want := job.evidenceURL(incident), "https://monitor.example.com/user/monitors/monitor-id/checks/check-id"; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(incident *entities.Incident)
+	}{
+		{"resolved incident", func(incident *entities.Incident) { incident.Status = "resolved" }},
+		{"not a down incident", func(incident *entities.Incident) { incident.TriggerType = "slow_response" }},
+		{"not opened by a check", func(incident *entities.Incident) { incident.CheckID = nil }},
+		{"check without evidence", func(incident *entities.Incident) { incident.CheckID = &otherCheckID }},
+	}
+	for _, tt := range tests {
+		other := *incident
+		tt.modify(&other)
+		if got := job.evidenceURL(&other); got != "" {
+			t.Errorf("%s: expected no link, got %s", tt.name, got)
+		}
+	}
+
+	var received WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	channel := &entities.AlertChannel{Config: entities.ChannelConfig{"url": server.URL}}
+	if err := job.sendWebhookNotification(incident, channel, "https://monitor.example.com/evidence"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if received.EvidenceURL != "https://monitor.example.com/evidence" {
+		t.Errorf("Expected the evidence link in the webhook payload, got %q", received.EvidenceURL)
+	}
+}
@@ -2,21 +2,38 @@ package jobs
 
 import (
 	"context"
-	"log"
+	"strconv"
+	"sync"
 	"time"
 
-	"github.com/eovipmak/v-insight/worker/internal/database"
+	"github.com/eovipmak/v-insight/shared/domain/entities"
+	"github.com/eovipmak/v-insight/shared/domain/repository"
+	"github.com/eovipmak/v-insight/shared/secrets"
+	"github.com/eovipmak/v-insight/worker/internal"
+	"github.com/eovipmak/v-insight/worker/internal/executor"
+	"go.uber.org/zap"
 )
 
-// SSLCheckJob is a dummy job that logs "Running SSL check"
+// certificateCheckInterval is how often each inventoried certificate is refreshed
+const certificateCheckInterval = 12 * time.Hour
+
+// SSLCheckJob keeps the certificate inventory up to date: it records the certificate served by
+// every enabled monitor connecting over TLS (https://, wss://, tls:// TCP and gRPC with TLS),
+// connecting like its health checks, and every standalone certificate target. Expiry alerts are
+// still raised by the health check of each monitor.
 type SSLCheckJob struct {
-	db *database.DB
+	monitorCredentials
+	certificateRepo repository.CertificateRepository
+	sslChecker      *executor.SSLChecker
 }
 
 // NewSSLCheckJob creates a new SSL check job
-func NewSSLCheckJob(db *database.DB) *SSLCheckJob {
+// cipher decrypts the proxy credentials and client certificates of monitors
+func NewSSLCheckJob(certificateRepo repository.CertificateRepository, clientCertRepo repository.ClientCertificateRepository, cipher *secrets.Cipher) *SSLCheckJob {
 	return &SSLCheckJob{
-		db: db,
+		monitorCredentials: monitorCredentials{clientCertRepo: clientCertRepo, cipher: cipher},
+		certificateRepo:    certificateRepo,
+		sslChecker:         executor.NewSSLChecker(30 * time.Second),
 	}
 }
 
@@ -25,31 +42,142 @@ func (j *SSLCheckJob) Name() string {
 	return "SSLCheckJob"
 }
 
-// Run executes the SSL check job
+// Run refreshes the certificates not checked within certificateCheckInterval
 func (j *SSLCheckJob) Run(ctx context.Context) error {
+	if j.certificateRepo == nil {
+		if internal.Log != nil {
+			internal.Log.Warn("Certificate repository is not configured, skipping SSL check")
+		}
+		return nil
+	}
+
 	startTime := time.Now()
-	log.Println("Running SSL check")
-	
-	// This is a placeholder for actual SSL certificate checking logic
-	// In the future, this would:
-	// 1. Fetch list of domains with SSL certificates from database
-	// 2. Check SSL certificate validity
-	// 3. Check expiration dates
-	// 4. Check certificate chain
-	// 5. Store results in database
-	// 6. Trigger alerts for expiring certificates
-
-	// For now, just check database connectivity
-	if j.db != nil {
-		if err := j.db.Health(); err != nil {
-			log.Printf("Database health check failed: %v", err)
-			return err
+	defer func() {
+		internal.JobExecutionDuration.WithLabelValues("SSLCheckJob").Observe(time.Since(startTime).Seconds())
+	}()
+
+	checkedBefore := startTime.Add(-certificateCheckInterval)
+	monitors, err := j.certificateRepo.GetMonitorsDue(checkedBefore)
+	if err != nil {
+		internal.JobExecutionTotal.WithLabelValues("SSLCheckJob", "failure").Inc()
+		return err
+	}
+	targets, err := j.certificateRepo.GetTargetsDue(checkedBefore)
+	if err != nil {
+		internal.JobExecutionTotal.WithLabelValues("SSLCheckJob", "failure").Inc()
+		return err
+	}
+
+	const maxConcurrent = 10
+	sem := make(chan struct{}, maxConcurrent)
+	var wg sync.WaitGroup
+
+	for _, monitor := range monitors {
+		host, port, err := monitorTLSAddress(monitor)
+		if err != nil {
+			continue
 		}
-		log.Println("Database connection is healthy")
+		monitorID := monitor.ID
+		certificate := &entities.Certificate{UserID: monitor.UserID, MonitorID: &monitorID, Host: host}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			j.inventory(ctx, certificate, port, func(ctx context.Context) executor.SSLCheckResult {
+				return j.checkMonitor(ctx, monitor)
+			})
+		}()
+	}
+
+	for _, target := range targets {
+		targetID := target.ID
+		certificate := &entities.Certificate{UserID: target.UserID, TargetID: &targetID, Host: target.Host}
+		port, serverName, startTLS := strconv.Itoa(target.Port), target.ServerName, target.StartTLS
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			j.inventory(ctx, certificate, port, func(ctx context.Context) executor.SSLCheckResult {
+				return j.sslChecker.CheckEndpoint(ctx, certificate.Host, port, serverName, startTLS)
+			})
+		}()
+	}
+
+	wg.Wait()
+
+	internal.JobExecutionTotal.WithLabelValues("SSLCheckJob", "success").Inc()
+	if internal.Log != nil {
+		internal.Log.Info("SSL check completed",
+			zap.Int("monitors", len(monitors)),
+			zap.Int("targets", len(targets)),
+			zap.Duration("duration", time.Since(startTime)),
+		)
 	}
 
-	duration := time.Since(startTime)
-	log.Printf("SSL check completed in %v", duration)
-	
 	return nil
 }
+
+// checkMonitor checks the certificate of a TLS monitor through its proxy, connecting to the
+// pinned address and sending the SNI override and client certificate of the monitor
+func (j *SSLCheckJob) checkMonitor(ctx context.Context, monitor *entities.Monitor) executor.SSLCheckResult {
+	host, port, serverName, err := monitorSSLEndpoint(monitor)
+	if err != nil {
+		return executor.SSLCheckResult{Error: err}
+	}
+	// Without the certificate a server requiring mutual TLS would only reject the handshake
+	clientCertificate, _, err := j.loadClientCertificate(monitor)
+	if err != nil {
+		return executor.SSLCheckResult{Error: err}
+	}
+	monitorSecrets, err := j.loadMonitorSecrets(monitor)
+	if err != nil {
+		return executor.SSLCheckResult{Error: err}
+	}
+	return monitorSSLChecker(j.sslChecker, monitor, clientCertificate, monitorSecrets).CheckEndpoint(ctx, host, port, serverName, "")
+}
+
+// inventory runs check against the endpoint of certificate on port and stores what it serves
+func (j *SSLCheckJob) inventory(ctx context.Context, certificate *entities.Certificate, port string, check func(ctx context.Context) executor.SSLCheckResult) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	result := check(ctx)
+	fillCertificate(certificate, result, time.Now())
+	certificate.Port, _ = strconv.Atoi(port)
+
+	if err := j.certificateRepo.Upsert(certificate); err != nil && internal.Log != nil {
+		internal.Log.Error("Failed to save certificate",
+			zap.String("host", certificate.Host),
+			zap.String("port", port),
+			zap.Error(err),
+		)
+	}
+}
+
+// fillCertificate copies the result of a certificate check into an inventory entry
+func fillCertificate(certificate *entities.Certificate, result executor.SSLCheckResult, checkedAt time.Time) {
+	certificate.CheckedAt = checkedAt
+	certificate.Valid = result.Valid
+	if result.Error != nil {
+		message := result.Error.Error()
+		certificate.Error = &message
+	}
+	if len(result.Chain) == 0 {
+		return
+	}
+
+	leaf := result.Chain[0]
+	certificate.Subject = leaf.Subject
+	certificate.Issuer = leaf.Issuer
+	certificate.SANs = leaf.SANs
+	certificate.NotBefore = &leaf.NotBefore
+	certificate.NotAfter = &leaf.NotAfter
+	certificate.ExpiresAt = &result.ExpiresAt
+	certificate.Fingerprint = result.Fingerprint
+	certificate.Details = newSSLCheckDetails(result)
+}